@@ -0,0 +1,57 @@
+package binary
+
+import (
+	"encoding/hex"
+	"errors"
+)
+
+var ErrInvalidAddr = errors.New("funny/binary: invalid address length")
+
+const (
+	MACLen  = 6
+	UUIDLen = 16
+)
+
+// UUID is a 128-bit identifier kept in RFC 4122 byte order.
+type UUID [UUIDLen]byte
+
+func (u UUID) String() string {
+	var s [36]byte
+	hex.Encode(s[0:8], u[0:4])
+	s[8] = '-'
+	hex.Encode(s[9:13], u[4:6])
+	s[13] = '-'
+	hex.Encode(s[14:18], u[6:8])
+	s[18] = '-'
+	hex.Encode(s[19:23], u[8:10])
+	s[23] = '-'
+	hex.Encode(s[24:], u[10:])
+	return string(s[:])
+}
+
+func GetUUID(b []byte) (u UUID) {
+	copy(u[:], b)
+	return
+}
+
+func PutUUID(b []byte, u UUID) {
+	copy(b, u[:])
+}
+
+// Microsoft GUIDs store the first three fields little-endian and the last
+// eight bytes as they are.
+
+func GetGUID(b []byte) (u UUID) {
+	PutUint32BE(u[0:], GetUint32LE(b[0:]))
+	PutUint16BE(u[4:], GetUint16LE(b[4:]))
+	PutUint16BE(u[6:], GetUint16LE(b[6:]))
+	copy(u[8:], b[8:16])
+	return
+}
+
+func PutGUID(b []byte, u UUID) {
+	PutUint32LE(b[0:], GetUint32BE(u[0:]))
+	PutUint16LE(b[4:], GetUint16BE(u[4:]))
+	PutUint16LE(b[6:], GetUint16BE(u[6:]))
+	copy(b[8:16], u[8:])
+}
@@ -0,0 +1,39 @@
+//go:build go1.18
+// +build go1.18
+
+package binary
+
+import "net/netip"
+
+func ReadAddr4(r BinaryReader) netip.Addr {
+	var a [4]byte
+	copy(a[:], r.ReadBytes(4))
+	return netip.AddrFrom4(a)
+}
+
+func ReadAddr6(r BinaryReader) netip.Addr {
+	var a [16]byte
+	copy(a[:], r.ReadBytes(16))
+	return netip.AddrFrom16(a)
+}
+
+// WriteAddr4 accepts IPv4 and IPv4-mapped IPv6 addresses.
+func WriteAddr4(w BinaryWriter, addr netip.Addr) error {
+	addr = addr.Unmap()
+	if !addr.Is4() {
+		return ErrInvalidAddr
+	}
+	a := addr.As4()
+	w.WriteBytes(a[:])
+	return nil
+}
+
+// WriteAddr6 writes IPv4 addresses in their IPv4-mapped form.
+func WriteAddr6(w BinaryWriter, addr netip.Addr) error {
+	if !addr.IsValid() {
+		return ErrInvalidAddr
+	}
+	a := addr.As16()
+	w.WriteBytes(a[:])
+	return nil
+}
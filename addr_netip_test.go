@@ -0,0 +1,31 @@
+//go:build go1.18
+// +build go1.18
+
+package binary
+
+import (
+	"net/netip"
+	"testing"
+
+	"github.com/funny/utest"
+)
+
+func Test_Addr_Netip(t *testing.T) {
+	ReadWriteTest(t, 1, func(r *Reader, w *Writer) {
+		a4 := netip.MustParseAddr("192.168.0.1")
+		a6 := netip.MustParseAddr("2001:db8::1")
+
+		utest.IsNilNow(t, WriteAddr4(w, a4))
+		utest.IsNilNow(t, WriteAddr4(w, netip.MustParseAddr("::ffff:10.0.0.1")))
+		utest.IsNilNow(t, WriteAddr6(w, a6))
+		utest.IsNilNow(t, WriteAddr6(w, a4))
+		utest.EqualNow(t, WriteAddr4(w, a6), ErrInvalidAddr)
+		utest.EqualNow(t, WriteAddr6(w, netip.Addr{}), ErrInvalidAddr)
+
+		utest.EqualNow(t, ReadAddr4(r), a4)
+		utest.EqualNow(t, ReadAddr4(r), netip.MustParseAddr("10.0.0.1"))
+		utest.EqualNow(t, ReadAddr6(r), a6)
+		utest.EqualNow(t, ReadAddr6(r), netip.MustParseAddr("::ffff:192.168.0.1"))
+		utest.IsNilNow(t, r.Error())
+	})
+}
@@ -0,0 +1,87 @@
+package binary
+
+import (
+	"math/rand"
+	"net"
+	"testing"
+
+	"github.com/funny/utest"
+)
+
+func Test_Addr_UUID(t *testing.T) {
+	u := UUID{0x00, 0x11, 0x22, 0x33, 0x44, 0x55, 0x66, 0x77, 0x88, 0x99, 0xAA, 0xBB, 0xCC, 0xDD, 0xEE, 0xFF}
+	utest.EqualNow(t, u.String(), "00112233-4455-6677-8899-aabbccddeeff")
+
+	guid := []byte{0x33, 0x22, 0x11, 0x00, 0x55, 0x44, 0x77, 0x66, 0x88, 0x99, 0xAA, 0xBB, 0xCC, 0xDD, 0xEE, 0xFF}
+	utest.EqualNow(t, GetGUID(guid), u)
+	b := make([]byte, UUIDLen)
+	PutGUID(b, u)
+	utest.EqualNow(t, b, guid)
+	PutUUID(b, u)
+	utest.EqualNow(t, GetUUID(b), u)
+}
+
+func Test_Addr_ReadWrite(t *testing.T) {
+	ReadWriteTest(t, 10000, func(r *Reader, w *Writer) {
+		var u, g UUID
+		rand.Read(u[:])
+		rand.Read(g[:])
+		ip4 := net.IP(u[:net.IPv4len])
+		ip6 := net.IP(g[:])
+		mac := net.HardwareAddr(u[UUIDLen-MACLen:])
+
+		w.WriteIPv4(ip4)
+		w.WriteIPv6(ip6)
+		w.WriteMAC(mac)
+		w.WriteUUID(u)
+		w.WriteGUID(g)
+		utest.IsNilNow(t, w.Error())
+
+		utest.EqualNow(t, r.ReadIPv4(), ip4)
+		utest.EqualNow(t, r.ReadIPv6(), ip6)
+		utest.EqualNow(t, r.ReadMAC(), mac)
+		utest.EqualNow(t, r.ReadUUID(), u)
+		utest.EqualNow(t, r.ReadGUID(), g)
+		utest.IsNilNow(t, r.Error())
+	})
+}
+
+func Test_Addr_Buffer(t *testing.T) {
+	var buf = Buffer{Data: make([]byte, 64)}
+	buf.WriteIPv4(net.ParseIP("192.168.0.1"))
+	buf.WriteIPv6(net.ParseIP("10.0.0.1"))
+	buf.WriteMAC(net.HardwareAddr{1, 2, 3, 4, 5, 6})
+	buf.WriteGUID(UUID{0: 1, 4: 2, 6: 3, 15: 4})
+	utest.EqualNow(t, buf.WritePos, 4+16+6+16)
+	utest.EqualNow(t, buf.Data[:4], []byte{192, 168, 0, 1})
+	utest.EqualNow(t, buf.Data[26:34], []byte{0, 0, 0, 1, 0, 2, 0, 3})
+
+	utest.EqualNow(t, buf.ReadIPv4().String(), "192.168.0.1")
+	utest.EqualNow(t, buf.ReadIPv6().String(), "10.0.0.1")
+	utest.EqualNow(t, buf.ReadMAC().String(), "01:02:03:04:05:06")
+	utest.EqualNow(t, buf.ReadGUID(), UUID{0: 1, 4: 2, 6: 3, 15: 4})
+
+	func() {
+		defer func() {
+			utest.EqualNow(t, recover(), ErrInvalidAddr)
+		}()
+		buf.WriteIPv4(net.ParseIP("::1"))
+	}()
+	utest.EqualNow(t, buf.WritePos, 4+16+6+16)
+}
+
+func Test_Addr_Errors(t *testing.T) {
+	ReadWriteTest(t, 1, func(r *Reader, w *Writer) {
+		w.WriteIPv4(net.ParseIP("::1"))
+		w.WriteIPv4(net.IPv4(1, 2, 3, 4))
+		utest.EqualNow(t, w.Error(), ErrInvalidAddr)
+		w.Reset(w.W)
+		w.WriteIPv6(net.IP{1, 2, 3})
+		utest.EqualNow(t, w.Error(), ErrInvalidAddr)
+		w.Reset(w.W)
+		w.WriteMAC(net.HardwareAddr{1, 2, 3, 4, 5, 6, 7, 8})
+		utest.EqualNow(t, w.Error(), ErrInvalidAddr)
+		w.WriteUint8(1)
+		utest.EqualNow(t, w.Error(), ErrInvalidAddr)
+	})
+}
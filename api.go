@@ -1,9 +1,14 @@
 package binary
 
-import "io"
+import (
+	"io"
+	"net"
+)
 
 var _ BinaryReader = (*Buffer)(nil)
 var _ BinaryReader = (*Reader)(nil)
+var _ BinaryReader = (*AtReader)(nil)
+var _ BinaryReader = (*MappedFile)(nil)
 var _ BinaryWriter = (*Buffer)(nil)
 var _ BinaryWriter = (*Writer)(nil)
 
@@ -15,9 +20,24 @@ type BinaryReader interface {
 
 	ReadBytes(n int) []byte
 	ReadString(n int) string
+	ReadBCD(nDigits int) uint64
+	ReadPackedDecimal(nDigits int) int64
+
+	ReadIPv4() net.IP
+	ReadIPv6() net.IP
+	ReadMAC() net.HardwareAddr
+	ReadUUID() UUID
+	ReadGUID() UUID
+	ReadBools(n int, order BitOrder) []bool
 
 	ReadUvarint() uint64
 	ReadVarint() int64
+	ReadQuicVarint() uint64
+	ReadSQLiteVarint() uint64
+	ReadVLQ() uint64
+	ReadGitVarint() uint64
+	ReadUvarint128() Uint128
+	ReadVarint128() Int128
 
 	ReadIntBE() int
 	ReadIntLE() int
@@ -66,6 +86,22 @@ type BinaryReader interface {
 	ReadFloat32LE() float32
 	ReadFloat64BE() float64
 	ReadFloat64LE() float64
+	ReadComplex64BE() complex64
+	ReadComplex64LE() complex64
+	ReadComplex128BE() complex128
+	ReadComplex128LE() complex128
+	ReadFloat80BE() float64
+	ReadFloat80LE() float64
+	ReadIBMFloat32BE() float64
+	ReadIBMFloat64BE() float64
+	ReadUint128BE() Uint128
+	ReadUint128LE() Uint128
+	ReadInt128BE() Int128
+	ReadInt128LE() Int128
+	ReadFloat16BE() float32
+	ReadFloat16LE() float32
+	ReadBFloat16BE() float32
+	ReadBFloat16LE() float32
 }
 
 type BinaryWriter interface {
@@ -75,9 +111,24 @@ type BinaryWriter interface {
 
 	WriteBytes(b []byte)
 	WriteString(s string)
+	WriteBCD(nDigits int, v uint64)
+	WritePackedDecimal(nDigits int, v int64)
+
+	WriteIPv4(ip net.IP)
+	WriteIPv6(ip net.IP)
+	WriteMAC(mac net.HardwareAddr)
+	WriteUUID(u UUID)
+	WriteGUID(u UUID)
+	WriteBools(v []bool, order BitOrder)
 
 	WriteUvarint(v uint64)
 	WriteVarint(v int64)
+	WriteQuicVarint(v uint64)
+	WriteSQLiteVarint(v uint64)
+	WriteVLQ(v uint64)
+	WriteGitVarint(v uint64)
+	WriteUvarint128(v Uint128)
+	WriteVarint128(v Int128)
 
 	WriteIntBE(v int)
 	WriteIntLE(v int)
@@ -126,4 +177,20 @@ type BinaryWriter interface {
 	WriteFloat32LE(v float32)
 	WriteFloat64BE(v float64)
 	WriteFloat64LE(v float64)
+	WriteComplex64BE(v complex64)
+	WriteComplex64LE(v complex64)
+	WriteComplex128BE(v complex128)
+	WriteComplex128LE(v complex128)
+	WriteFloat80BE(v float64)
+	WriteFloat80LE(v float64)
+	WriteIBMFloat32BE(v float64)
+	WriteIBMFloat64BE(v float64)
+	WriteUint128BE(v Uint128)
+	WriteUint128LE(v Uint128)
+	WriteInt128BE(v Int128)
+	WriteInt128LE(v Int128)
+	WriteFloat16BE(v float32)
+	WriteFloat16LE(v float32)
+	WriteBFloat16BE(v float32)
+	WriteBFloat16LE(v float32)
 }
@@ -0,0 +1,88 @@
+// Package asn1 implements the BER and DER tag, length and value layer of
+// ASN.1 on top of the Buffer and BinaryReader types of
+// github.com/funny/binary.
+//
+// Reader walks elements as a stream and never holds more than one value in
+// memory, Builder writes DER into a Buffer. Mapping values to Go types is
+// left to the caller, helped by the Parse and Append functions.
+package asn1
+
+import "errors"
+
+var (
+	ErrInvalidTag     = errors.New("funny/binary/asn1: invalid tag")
+	ErrInvalidLength  = errors.New("funny/binary/asn1: invalid length")
+	ErrInvalidValue   = errors.New("funny/binary/asn1: invalid value encoding")
+	ErrNotDER         = errors.New("funny/binary/asn1: encoding is valid BER but not DER")
+	ErrSetOrder       = errors.New("funny/binary/asn1: DER SET elements out of order")
+	ErrTooLarge       = errors.New("funny/binary/asn1: length exceeds limit")
+	ErrTooDeep        = errors.New("funny/binary/asn1: nesting exceeds limit")
+	ErrOverflow       = errors.New("funny/binary/asn1: value overflows target type")
+	ErrNoElement      = errors.New("funny/binary/asn1: no element to read")
+	ErrNotPrimitive   = errors.New("funny/binary/asn1: element is constructed")
+	ErrNotConstructed = errors.New("funny/binary/asn1: element is primitive")
+	ErrUnbalanced     = errors.New("funny/binary/asn1: End without Begin")
+)
+
+// Class is the class of a tag.
+type Class uint8
+
+const (
+	ClassUniversal   Class = 0
+	ClassApplication Class = 1
+	ClassContext     Class = 2
+	ClassPrivate     Class = 3
+)
+
+// Universal tag numbers.
+const (
+	TagEndOfContents   = 0
+	TagBoolean         = 1
+	TagInteger         = 2
+	TagBitString       = 3
+	TagOctetString     = 4
+	TagNull            = 5
+	TagOID             = 6
+	TagEnumerated      = 10
+	TagUTF8String      = 12
+	TagSequence        = 16
+	TagSet             = 17
+	TagNumericString   = 18
+	TagPrintableString = 19
+	TagT61String       = 20
+	TagIA5String       = 22
+	TagUTCTime         = 23
+	TagGeneralizedTime = 24
+	TagBMPString       = 30
+)
+
+// Indefinite is the Length of a BER element closed by end-of-contents.
+const Indefinite = -1
+
+// MaxTag is the largest supported tag number.
+const MaxTag = 1<<31 - 1
+
+// DefaultMaxLen limits the length of an element when MaxLen of a Reader is
+// zero.
+const DefaultMaxLen = 64 << 20
+
+const maxDepth = 100
+
+// Header is the identifier and length of an element.
+type Header struct {
+	Class       Class
+	Constructed bool
+	Tag         int
+	Length      int
+}
+
+// isString reports the universal types that BER allows in constructed form
+// and DER does not.
+func isString(tag int) bool {
+	switch tag {
+	case TagBitString, TagOctetString, TagUTF8String, TagNumericString, TagPrintableString,
+		TagT61String, TagIA5String, TagUTCTime, TagGeneralizedTime, TagBMPString:
+		return true
+	}
+	return false
+}
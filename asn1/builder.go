@@ -0,0 +1,273 @@
+package asn1
+
+import (
+	"bytes"
+	"math/big"
+	"sort"
+
+	"github.com/funny/binary"
+)
+
+type openElement struct {
+	lenPos int
+	set    bool
+}
+
+// Builder writes DER elements into a Buffer and grows its Data as needed.
+// A constructed element gets a one byte length placeholder that End
+// backpatches, moving the contents when the length needs more bytes. The
+// first error is kept and later calls do nothing.
+type Builder struct {
+	buf   *binary.Buffer
+	stack []openElement
+	err   error
+}
+
+func NewBuilder(buf *binary.Buffer) *Builder {
+	return &Builder{buf: buf}
+}
+
+// Error returns the first error, or ErrUnbalanced while elements are still
+// open.
+func (b *Builder) Error() error {
+	if b.err == nil && len(b.stack) > 0 {
+		return ErrUnbalanced
+	}
+	return b.err
+}
+
+func (b *Builder) grow(n int) {
+	if len(b.buf.Data)-b.buf.WritePos >= n {
+		return
+	}
+	size := 2 * len(b.buf.Data)
+	if size < b.buf.WritePos+n {
+		size = b.buf.WritePos + n
+	}
+	if size < 64 {
+		size = 64
+	}
+	data := make([]byte, size)
+	copy(data, b.buf.Data)
+	b.buf.Data = data
+}
+
+func (b *Builder) write(p []byte) {
+	b.grow(len(p))
+	b.buf.WriteBytes(p)
+}
+
+func appendIdentifier(p []byte, class Class, constructed bool, tag int) []byte {
+	id := byte(class) << 6
+	if constructed {
+		id |= 0x20
+	}
+	if tag < 0x1f {
+		return append(p, id|byte(tag))
+	}
+	return appendBase128(append(p, id|0x1f), uint64(tag))
+}
+
+func appendLength(p []byte, n int) []byte {
+	if n < 0x80 {
+		return append(p, byte(n))
+	}
+	size := 1
+	for x := n >> 8; x > 0; x >>= 8 {
+		size++
+	}
+	p = append(p, 0x80|byte(size))
+	for i := size - 1; i >= 0; i-- {
+		p = append(p, byte(n>>(uint(i)*8)))
+	}
+	return p
+}
+
+func (b *Builder) checkTag(class Class, tag int) bool {
+	if b.err != nil {
+		return false
+	}
+	if class > ClassPrivate || tag < 0 || tag > MaxTag {
+		b.err = ErrInvalidTag
+		return false
+	}
+	return true
+}
+
+// Begin opens a constructed element, End closes it.
+func (b *Builder) Begin(class Class, tag int) {
+	if !b.checkTag(class, tag) {
+		return
+	}
+	var hdr [8]byte
+	b.write(appendIdentifier(hdr[:0], class, true, tag))
+	b.stack = append(b.stack, openElement{lenPos: b.buf.WritePos})
+	b.write([]byte{0})
+}
+
+func (b *Builder) BeginSequence() {
+	b.Begin(ClassUniversal, TagSequence)
+}
+
+// BeginSet opens a SET, End sorts its elements by their encoding as DER
+// requires.
+func (b *Builder) BeginSet() {
+	b.Begin(ClassUniversal, TagSet)
+	if b.err == nil {
+		b.stack[len(b.stack)-1].set = true
+	}
+}
+
+func (b *Builder) End() {
+	if b.err != nil {
+		return
+	}
+	if len(b.stack) == 0 {
+		b.err = ErrUnbalanced
+		return
+	}
+	e := b.stack[len(b.stack)-1]
+	b.stack = b.stack[:len(b.stack)-1]
+	start := e.lenPos + 1
+	n := b.buf.WritePos - start
+	if e.set {
+		sortElements(b.buf.Data[start:b.buf.WritePos])
+	}
+	var lb [9]byte
+	length := appendLength(lb[:0], n)
+	if extra := len(length) - 1; extra > 0 {
+		b.grow(extra)
+		copy(b.buf.Data[start+extra:], b.buf.Data[start:b.buf.WritePos])
+		b.buf.WritePos += extra
+	}
+	copy(b.buf.Data[e.lenPos:], length)
+}
+
+// elementSize returns the size of the first element of p, or -1 when p
+// does not start with a complete definite length element.
+func elementSize(p []byte) int {
+	i := 1
+	if len(p) < 2 {
+		return -1
+	}
+	if p[0]&0x1f == 0x1f {
+		for i < len(p) && p[i]&0x80 != 0 {
+			i++
+		}
+		i++
+	}
+	if i >= len(p) {
+		return -1
+	}
+	l := int(p[i])
+	i++
+	if l >= 0x80 {
+		size := l & 0x7f
+		if size == 0 || size > 4 || i+size > len(p) {
+			return -1
+		}
+		l = 0
+		for _, c := range p[i : i+size] {
+			l = l<<8 | int(c)
+		}
+		i += size
+	}
+	if l < 0 || l > len(p)-i {
+		return -1
+	}
+	return i + l
+}
+
+func sortElements(p []byte) {
+	var elems [][]byte
+	for rest := p; len(rest) > 0; {
+		n := elementSize(rest)
+		elems = append(elems, rest[:n])
+		rest = rest[n:]
+	}
+	if sort.SliceIsSorted(elems, func(i, j int) bool { return bytes.Compare(elems[i], elems[j]) < 0 }) {
+		return
+	}
+	sorted := make([][]byte, len(elems))
+	copy(sorted, elems)
+	sort.SliceStable(sorted, func(i, j int) bool { return bytes.Compare(sorted[i], sorted[j]) < 0 })
+	out := make([]byte, 0, len(p))
+	for _, e := range sorted {
+		out = append(out, e...)
+	}
+	copy(p, out)
+}
+
+// AddPrimitive writes a primitive element.
+func (b *Builder) AddPrimitive(class Class, tag int, contents []byte) {
+	if !b.checkTag(class, tag) {
+		return
+	}
+	var hdr [16]byte
+	p := appendIdentifier(hdr[:0], class, false, tag)
+	b.write(appendLength(p, len(contents)))
+	b.write(contents)
+}
+
+// AddRaw writes an element encoded elsewhere as it is. der has to be one
+// complete element with a definite length.
+func (b *Builder) AddRaw(der []byte) {
+	if b.err != nil {
+		return
+	}
+	if elementSize(der) != len(der) {
+		b.err = ErrInvalidValue
+		return
+	}
+	b.write(der)
+}
+
+func (b *Builder) AddBool(v bool) {
+	if v {
+		b.AddPrimitive(ClassUniversal, TagBoolean, []byte{0xff})
+	} else {
+		b.AddPrimitive(ClassUniversal, TagBoolean, []byte{0})
+	}
+}
+
+func (b *Builder) AddInt64(v int64) {
+	var p [8]byte
+	b.AddPrimitive(ClassUniversal, TagInteger, AppendInt64(p[:0], v))
+}
+
+func (b *Builder) AddBigInt(v *big.Int) {
+	b.AddPrimitive(ClassUniversal, TagInteger, AppendBigInt(nil, v))
+}
+
+func (b *Builder) AddNull() {
+	b.AddPrimitive(ClassUniversal, TagNull, nil)
+}
+
+func (b *Builder) AddOID(oid OID) {
+	p, err := AppendOID(nil, oid)
+	if err != nil {
+		if b.err == nil {
+			b.err = err
+		}
+		return
+	}
+	b.AddPrimitive(ClassUniversal, TagOID, p)
+}
+
+func (b *Builder) AddOctetString(v []byte) {
+	b.AddPrimitive(ClassUniversal, TagOctetString, v)
+}
+
+func (b *Builder) AddUTF8String(v string) {
+	b.AddPrimitive(ClassUniversal, TagUTF8String, []byte(v))
+}
+
+func (b *Builder) AddBitString(v BitString) {
+	if v.BitLength < 0 || (v.BitLength+7)/8 > len(v.Bytes) {
+		if b.err == nil {
+			b.err = ErrInvalidValue
+		}
+		return
+	}
+	b.AddPrimitive(ClassUniversal, TagBitString, AppendBitString(nil, v))
+}
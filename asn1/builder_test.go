@@ -0,0 +1,121 @@
+package asn1
+
+import (
+	"bytes"
+	"io"
+	"math/big"
+	"testing"
+
+	"github.com/funny/binary"
+	"github.com/funny/utest"
+)
+
+func Test_Builder_DER(t *testing.T) {
+	var buf binary.Buffer
+	b := NewBuilder(&buf)
+	b.BeginSequence()
+	b.AddInt64(5)
+	b.Begin(ClassContext, 0)
+	b.AddBool(true)
+	b.End()
+	b.AddPrimitive(ClassApplication, 200, []byte("hi"))
+	b.AddOID(OID{2, 5, 4, 3})
+	b.End()
+	b.AddNull()
+	utest.IsNilNow(t, b.Error())
+	utest.EqualNow(t, buf.Data[:buf.WritePos], []byte{
+		0x30, 0x13,
+		0x02, 0x01, 0x05,
+		0xa0, 0x03, 0x01, 0x01, 0xff,
+		0x5f, 0x81, 0x48, 0x02, 'h', 'i',
+		0x06, 0x03, 0x55, 0x04, 0x03,
+		0x05, 0x00,
+	})
+}
+
+func Test_Builder_Backpatch(t *testing.T) {
+	var buf binary.Buffer
+	b := NewBuilder(&buf)
+	b.BeginSequence()
+	b.BeginSequence()
+	b.AddOctetString(make([]byte, 200))
+	b.End()
+	b.AddUTF8String("x")
+	b.End()
+	utest.IsNilNow(t, b.Error())
+	out := buf.Data[:buf.WritePos]
+	utest.EqualNow(t, out[:8], []byte{0x30, 0x81, 0xd1, 0x30, 0x81, 0xcb, 0x04, 0x81})
+	utest.EqualNow(t, out[len(out)-3:], []byte{0x0c, 0x01, 'x'})
+
+	// The result reads back in DER mode.
+	r := NewReader(binary.NewReader(bytes.NewReader(out)))
+	r.DER = true
+	r.Next()
+	r.Enter()
+	h, _ := r.Next()
+	utest.EqualNow(t, h.Length, 0xcb)
+	h, _ = r.Next()
+	utest.EqualNow(t, h.Tag, TagUTF8String)
+	utest.IsNilNow(t, r.Leave())
+	_, err := r.Next()
+	utest.EqualNow(t, err, io.EOF)
+}
+
+func Test_Builder_Set(t *testing.T) {
+	var buf binary.Buffer
+	b := NewBuilder(&buf)
+	b.BeginSet()
+	b.AddInt64(256)
+	b.AddOctetString([]byte{1})
+	b.AddInt64(2)
+	b.AddBigInt(big.NewInt(-1))
+	b.AddBitString(BitString{[]byte{0x80}, 1})
+	b.End()
+	utest.IsNilNow(t, b.Error())
+	utest.EqualNow(t, buf.Data[:buf.WritePos], []byte{
+		0x31, 0x11,
+		0x02, 0x01, 0x02,
+		0x02, 0x01, 0xff,
+		0x02, 0x02, 0x01, 0x00,
+		0x03, 0x02, 0x07, 0x80,
+		0x04, 0x01, 0x01,
+	})
+}
+
+func Test_Builder_Errors(t *testing.T) {
+	b := NewBuilder(&binary.Buffer{})
+	b.End()
+	utest.EqualNow(t, b.Error(), ErrUnbalanced)
+	b = NewBuilder(&binary.Buffer{})
+	b.BeginSequence()
+	utest.EqualNow(t, b.Error(), ErrUnbalanced)
+	b = NewBuilder(&binary.Buffer{})
+	b.AddPrimitive(ClassContext, -1, nil)
+	utest.EqualNow(t, b.Error(), ErrInvalidTag)
+	b = NewBuilder(&binary.Buffer{})
+	b.AddOID(OID{3})
+	utest.EqualNow(t, b.Error(), ErrInvalidValue)
+	b = NewBuilder(&binary.Buffer{})
+	b.AddBitString(BitString{nil, 1})
+	utest.EqualNow(t, b.Error(), ErrInvalidValue)
+
+	for _, der := range [][]byte{
+		{0x04, 0x05, 0x01},
+		{0x04},
+		{0x1f, 0x81},
+		{0x30, 0x80, 0x00, 0x00},
+		{0x04, 0x84, 0xff, 0xff, 0xff, 0xff},
+		{0x04, 0x01, 0x01, 0x05, 0x00},
+	} {
+		b = NewBuilder(&binary.Buffer{})
+		b.BeginSet()
+		b.AddRaw(der)
+		b.End()
+		utest.EqualNow(t, b.Error(), ErrInvalidValue)
+	}
+	b = NewBuilder(&binary.Buffer{})
+	b.BeginSet()
+	b.AddRaw([]byte{0x04, 0x01, 0x01})
+	b.End()
+	utest.IsNilNow(t, b.Error())
+}
@@ -0,0 +1,323 @@
+package asn1
+
+import (
+	"bytes"
+	"io"
+
+	"github.com/funny/binary"
+)
+
+type frame struct {
+	end  int64 // Indefinite or the offset after the contents
+	done bool
+
+	// In DER mode the children of a SET are captured to check their order.
+	set       bool
+	prev, cur []byte
+}
+
+// Reader reads a stream of elements. Next returns the header of each
+// element in turn, Value reads the contents of a primitive one and Enter
+// descends into a constructed one until Leave. Contents that are not read
+// are skipped.
+type Reader struct {
+	r     binary.BinaryReader
+	pos   int64
+	stack []*frame
+
+	pending *Header
+	err     error
+
+	// DER rejects indefinite and non-minimal lengths, constructed strings
+	// and unsorted SETs.
+	DER bool
+
+	// MaxLen limits the length of an element, zero means DefaultMaxLen.
+	MaxLen int
+}
+
+func NewReader(r binary.BinaryReader) *Reader {
+	return &Reader{r: r}
+}
+
+func (r *Reader) maxLen() int {
+	if r.MaxLen > 0 {
+		return r.MaxLen
+	}
+	return DefaultMaxLen
+}
+
+func (r *Reader) capture(b []byte) {
+	for _, f := range r.stack {
+		if f.set {
+			f.cur = append(f.cur, b...)
+		}
+	}
+}
+
+func (r *Reader) readByte() (byte, error) {
+	b := r.r.ReadUint8()
+	if err := r.r.Error(); err != nil {
+		return 0, err
+	}
+	r.pos++
+	r.capture([]byte{b})
+	return b, nil
+}
+
+// readBytes is never at an element boundary, the end of the stream is
+// always unexpected.
+func (r *Reader) readBytes(n int) ([]byte, error) {
+	b := r.r.ReadBytes(n)
+	if err := r.r.Error(); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, err
+	}
+	r.pos += int64(n)
+	r.capture(b)
+	return b, nil
+}
+
+func (r *Reader) top() *frame {
+	if len(r.stack) == 0 {
+		return nil
+	}
+	return r.stack[len(r.stack)-1]
+}
+
+// limit returns the end of the innermost definite length container.
+func (r *Reader) limit() int64 {
+	for i := len(r.stack) - 1; i >= 0; i-- {
+		if r.stack[i].end != Indefinite {
+			return r.stack[i].end
+		}
+	}
+	return -1
+}
+
+// Depth returns the number of entered containers.
+func (r *Reader) Depth() int {
+	return len(r.stack)
+}
+
+// Next skips what is left of the previous element and returns the header
+// of the next one. It returns io.EOF at the end of the entered container,
+// or at the end of the stream between top level elements.
+func (r *Reader) Next() (Header, error) {
+	if r.err != nil {
+		return Header{}, r.err
+	}
+	h, err := r.next()
+	if err != nil && err != io.EOF {
+		r.err = err
+	}
+	return h, err
+}
+
+func (r *Reader) next() (Header, error) {
+	if err := r.skipPending(); err != nil {
+		return Header{}, err
+	}
+	f := r.top()
+	if f != nil {
+		if f.done {
+			return Header{}, io.EOF
+		}
+		if f.set && f.cur != nil {
+			if f.prev != nil && bytes.Compare(f.prev, f.cur) > 0 {
+				return Header{}, ErrSetOrder
+			}
+			f.prev, f.cur = f.cur, nil
+		}
+		if f.end != Indefinite && r.pos == f.end {
+			f.done = true
+			return Header{}, io.EOF
+		}
+		if limit := r.limit(); limit >= 0 && r.pos >= limit {
+			return Header{}, ErrInvalidLength
+		}
+	}
+	h, err := r.readHeader(f == nil)
+	if err != nil {
+		return Header{}, err
+	}
+	if h.Class == ClassUniversal && h.Tag == TagEndOfContents {
+		if f == nil || f.end != Indefinite || h.Constructed || h.Length != 0 {
+			return Header{}, ErrInvalidTag
+		}
+		f.done = true
+		if f.set {
+			f.cur = nil
+		}
+		return Header{}, io.EOF
+	}
+	if limit := r.limit(); limit >= 0 && h.Length != Indefinite && r.pos+int64(h.Length) > limit {
+		return Header{}, ErrInvalidLength
+	}
+	if r.DER && h.Constructed && h.Class == ClassUniversal && isString(h.Tag) {
+		return Header{}, ErrNotDER
+	}
+	r.pending = &h
+	return h, nil
+}
+
+// readHeader returns io.EOF only when the stream ends before the first
+// byte at the top level.
+func (r *Reader) readHeader(top bool) (h Header, err error) {
+	b, err := r.readByte()
+	if err != nil {
+		if err == io.EOF && !top {
+			err = io.ErrUnexpectedEOF
+		}
+		return
+	}
+	defer func() {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+	}()
+	h.Class = Class(b >> 6)
+	h.Constructed = b&0x20 != 0
+	h.Tag = int(b & 0x1f)
+	if h.Tag == 0x1f {
+		var n uint64
+		for i := 0; ; i++ {
+			if b, err = r.readByte(); err != nil {
+				return
+			}
+			if i == 0 && b == 0x80 {
+				return h, ErrInvalidTag
+			}
+			n = n<<7 | uint64(b&0x7f)
+			if n > MaxTag {
+				return h, ErrInvalidTag
+			}
+			if b&0x80 == 0 {
+				break
+			}
+		}
+		if n < 0x1f {
+			return h, ErrInvalidTag
+		}
+		h.Tag = int(n)
+	}
+	if b, err = r.readByte(); err != nil {
+		return
+	}
+	switch {
+	case b < 0x80:
+		h.Length = int(b)
+	case b == 0x80:
+		if !h.Constructed {
+			return h, ErrInvalidLength
+		}
+		if r.DER {
+			return h, ErrNotDER
+		}
+		h.Length = Indefinite
+	case b == 0xff:
+		return h, ErrInvalidLength
+	default:
+		var lb []byte
+		if lb, err = r.readBytes(int(b & 0x7f)); err != nil {
+			return
+		}
+		if r.DER && (lb[0] == 0 || len(lb) == 1 && lb[0] < 0x80) {
+			return h, ErrNotDER
+		}
+		var n uint64
+		for _, c := range lb {
+			if n > uint64(r.maxLen()) {
+				return h, ErrTooLarge
+			}
+			n = n<<8 | uint64(c)
+		}
+		if n > uint64(r.maxLen()) {
+			return h, ErrTooLarge
+		}
+		h.Length = int(n)
+	}
+	return h, nil
+}
+
+func (r *Reader) skipPending() error {
+	h := r.pending
+	if h == nil {
+		return nil
+	}
+	if h.Length != Indefinite {
+		r.pending = nil
+		_, err := r.readBytes(h.Length)
+		return err
+	}
+	if err := r.Enter(); err != nil {
+		return err
+	}
+	return r.Leave()
+}
+
+// Value reads the contents of the primitive element returned by Next.
+func (r *Reader) Value() ([]byte, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+	h := r.pending
+	if h == nil {
+		return nil, ErrNoElement
+	}
+	if h.Constructed {
+		return nil, ErrNotPrimitive
+	}
+	r.pending = nil
+	b, err := r.readBytes(h.Length)
+	if err != nil {
+		r.err = err
+	}
+	return b, err
+}
+
+// Enter descends into the constructed element returned by Next.
+func (r *Reader) Enter() error {
+	if r.err != nil {
+		return r.err
+	}
+	h := r.pending
+	if h == nil {
+		return ErrNoElement
+	}
+	if !h.Constructed {
+		return ErrNotConstructed
+	}
+	if len(r.stack) >= maxDepth {
+		r.err = ErrTooDeep
+		return r.err
+	}
+	r.pending = nil
+	f := &frame{end: Indefinite}
+	if h.Length != Indefinite {
+		f.end = r.pos + int64(h.Length)
+	}
+	f.set = r.DER && h.Class == ClassUniversal && h.Tag == TagSet
+	r.stack = append(r.stack, f)
+	return nil
+}
+
+// Leave skips the rest of the entered container and returns to its parent.
+func (r *Reader) Leave() error {
+	if len(r.stack) == 0 {
+		return ErrUnbalanced
+	}
+	for {
+		_, err := r.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+	}
+	r.stack = r.stack[:len(r.stack)-1]
+	return nil
+}
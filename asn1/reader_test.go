@@ -0,0 +1,195 @@
+package asn1
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/funny/binary"
+	"github.com/funny/utest"
+)
+
+func newReader(b []byte, der bool) *Reader {
+	r := NewReader(binary.NewReader(bytes.NewReader(b)))
+	r.DER = der
+	return r
+}
+
+func Test_Reader_Walk(t *testing.T) {
+	// SEQUENCE { INTEGER 5, [0] { BOOLEAN TRUE }, [APPLICATION 200] "hi", OID }, NULL
+	b := []byte{
+		0x30, 0x13,
+		0x02, 0x01, 0x05,
+		0xa0, 0x03, 0x01, 0x01, 0xff,
+		0x5f, 0x81, 0x48, 0x02, 'h', 'i',
+		0x06, 0x03, 0x55, 0x04, 0x03,
+		0x05, 0x00,
+	}
+	r := newReader(b, true)
+	h, err := r.Next()
+	utest.IsNilNow(t, err)
+	utest.EqualNow(t, h, Header{ClassUniversal, true, TagSequence, 0x13})
+	utest.IsNilNow(t, r.Enter())
+	utest.EqualNow(t, r.Depth(), 1)
+
+	h, _ = r.Next()
+	utest.EqualNow(t, h.Tag, TagInteger)
+	v, err := r.Value()
+	utest.IsNilNow(t, err)
+	n, _ := ParseInt64(v)
+	utest.EqualNow(t, n, int64(5))
+
+	h, _ = r.Next()
+	utest.EqualNow(t, h, Header{ClassContext, true, 0, 3})
+	utest.IsNilNow(t, r.Enter())
+	h, _ = r.Next()
+	utest.EqualNow(t, h.Tag, TagBoolean)
+	_, err = r.Next()
+	utest.EqualNow(t, err, io.EOF)
+	utest.IsNilNow(t, r.Leave())
+
+	h, _ = r.Next()
+	utest.EqualNow(t, h, Header{ClassApplication, false, 200, 2})
+	v, _ = r.Value()
+	utest.EqualNow(t, string(v), "hi")
+
+	// The OID is skipped by Leave.
+	utest.IsNilNow(t, r.Leave())
+	h, err = r.Next()
+	utest.IsNilNow(t, err)
+	utest.EqualNow(t, h.Tag, TagNull)
+	_, err = r.Next()
+	utest.EqualNow(t, err, io.EOF)
+}
+
+func Test_Reader_Indefinite(t *testing.T) {
+	// SEQUENCE (indefinite) { OCTET STRING (constructed, indefinite) { "ab", "c" }, INTEGER 1 }
+	b := []byte{
+		0x30, 0x80,
+		0x24, 0x80, 0x04, 0x02, 'a', 'b', 0x04, 0x01, 'c', 0x00, 0x00,
+		0x02, 0x01, 0x01,
+		0x00, 0x00,
+		0x05, 0x00,
+	}
+	r := newReader(b, false)
+	h, _ := r.Next()
+	utest.EqualNow(t, h.Length, Indefinite)
+	utest.IsNilNow(t, r.Enter())
+	h, _ = r.Next()
+	utest.EqualNow(t, h, Header{ClassUniversal, true, TagOctetString, Indefinite})
+	utest.IsNilNow(t, r.Enter())
+	var s []byte
+	for {
+		_, err := r.Next()
+		if err == io.EOF {
+			break
+		}
+		utest.IsNilNow(t, err)
+		v, _ := r.Value()
+		s = append(s, v...)
+	}
+	utest.EqualNow(t, string(s), "abc")
+	utest.IsNilNow(t, r.Leave())
+	h, _ = r.Next()
+	utest.EqualNow(t, h.Tag, TagInteger)
+	_, err := r.Next()
+	utest.EqualNow(t, err, io.EOF)
+	utest.IsNilNow(t, r.Leave())
+	h, err = r.Next()
+	utest.IsNilNow(t, err)
+	utest.EqualNow(t, h.Tag, TagNull)
+
+	// Skipping the whole indefinite element.
+	r = newReader(b, false)
+	r.Next()
+	h, err = r.Next()
+	utest.IsNilNow(t, err)
+	utest.EqualNow(t, h.Tag, TagNull)
+
+	_, err = newReader(b, true).Next()
+	utest.EqualNow(t, err, ErrNotDER)
+}
+
+func Test_Reader_DER(t *testing.T) {
+	for _, c := range []struct {
+		b   []byte
+		err error
+	}{
+		{[]byte{0x04, 0x81, 0x01, 0x00}, ErrNotDER},
+		{[]byte{0x04, 0x82, 0x00, 0x01, 0x00}, ErrNotDER},
+		{[]byte{0x24, 0x03, 0x04, 0x01, 0x00}, ErrNotDER},
+		{[]byte{0x31, 0x06, 0x02, 0x01, 0x02, 0x02, 0x01, 0x01}, ErrSetOrder},
+		{[]byte{0x31, 0x07, 0x02, 0x02, 0x00, 0x80, 0x02, 0x01, 0x01}, ErrSetOrder},
+		{[]byte{0x31, 0x06, 0x02, 0x01, 0x01, 0x02, 0x01, 0x01}, io.EOF},
+		{[]byte{0x31, 0x07, 0x02, 0x01, 0x01, 0x04, 0x02, 0x00, 0x80}, io.EOF},
+	} {
+		r := newReader(c.b, true)
+		var err error
+		if _, err = r.Next(); err == nil {
+			if err = r.Enter(); err == nil {
+				for err == nil {
+					_, err = r.Next()
+				}
+			}
+		}
+		utest.EqualNow(t, err, c.err)
+	}
+
+	// BER takes what DER rejects.
+	r := newReader([]byte{0x31, 0x06, 0x02, 0x01, 0x02, 0x02, 0x01, 0x01}, false)
+	r.Next()
+	utest.IsNilNow(t, r.Enter())
+	utest.IsNilNow(t, r.Leave())
+}
+
+func Test_Reader_Errors(t *testing.T) {
+	for _, c := range []struct {
+		b   []byte
+		err error
+	}{
+		{[]byte{}, io.EOF},
+		{[]byte{0x02}, io.ErrUnexpectedEOF},
+		{[]byte{0x02, 0x02, 0x01}, io.ErrUnexpectedEOF},
+		{[]byte{0x1f, 0x80, 0x01, 0x00}, ErrInvalidTag},
+		{[]byte{0x1f, 0x1e, 0x00}, ErrInvalidTag},
+		{[]byte{0x1f, 0x88, 0x80, 0x80, 0x80, 0x00, 0x00}, ErrInvalidTag},
+		{[]byte{0x00, 0x00}, ErrInvalidTag},
+		{[]byte{0x04, 0x80}, ErrInvalidLength},
+		{[]byte{0x04, 0xff}, ErrInvalidLength},
+		{[]byte{0x04, 0x85, 0xff, 0xff, 0xff, 0xff, 0xff}, ErrTooLarge},
+		{[]byte{0x30, 0x03, 0x04, 0x02, 0x00, 0x00}, ErrInvalidLength},
+		{[]byte{0x30, 0x02, 0x30, 0x80, 0x04, 0x00}, ErrInvalidLength},
+		{[]byte{0x30, 0x80, 0x00, 0x01, 0x00}, ErrInvalidTag},
+	} {
+		r := newReader(c.b, false)
+		var err error
+		for err == nil {
+			if _, err = r.Next(); err == nil {
+				if err = r.Enter(); err == ErrNotConstructed {
+					_, err = r.Value()
+				}
+			}
+		}
+		utest.EqualNow(t, err, c.err)
+	}
+
+	r := newReader(bytes.Repeat([]byte{0x30, 0x80}, maxDepth+1), false)
+	var err error
+	for err == nil {
+		if _, err = r.Next(); err == nil {
+			err = r.Enter()
+		}
+	}
+	utest.EqualNow(t, err, ErrTooDeep)
+
+	r = newReader([]byte{0x02, 0x01, 0x00}, false)
+	_, err = r.Value()
+	utest.EqualNow(t, err, ErrNoElement)
+	utest.EqualNow(t, r.Leave(), ErrUnbalanced)
+	r.Next()
+	utest.EqualNow(t, r.Enter(), ErrNotConstructed)
+	r = newReader([]byte{0x30, 0x00}, false)
+	r.Next()
+	_, err = r.Value()
+	utest.EqualNow(t, err, ErrNotPrimitive)
+}
@@ -0,0 +1,225 @@
+package asn1
+
+import (
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// ParseBool decodes BOOLEAN contents. DER only allows 0x00 and 0xff.
+func ParseBool(b []byte, der bool) (bool, error) {
+	if len(b) != 1 {
+		return false, ErrInvalidValue
+	}
+	if der && b[0] != 0 && b[0] != 0xff {
+		return false, ErrNotDER
+	}
+	return b[0] != 0, nil
+}
+
+// checkInteger rejects empty and non-minimal INTEGER contents, which BER
+// does not allow either.
+func checkInteger(b []byte) error {
+	if len(b) == 0 {
+		return ErrInvalidValue
+	}
+	if len(b) > 1 && (b[0] == 0 && b[1]&0x80 == 0 || b[0] == 0xff && b[1]&0x80 != 0) {
+		return ErrInvalidValue
+	}
+	return nil
+}
+
+// ParseInt64 decodes INTEGER or ENUMERATED contents.
+func ParseInt64(b []byte) (int64, error) {
+	if err := checkInteger(b); err != nil {
+		return 0, err
+	}
+	if len(b) > 8 {
+		return 0, ErrOverflow
+	}
+	v := int64(int8(b[0]))
+	for _, c := range b[1:] {
+		v = v<<8 | int64(c)
+	}
+	return v, nil
+}
+
+// ParseBigInt decodes INTEGER contents of any size.
+func ParseBigInt(b []byte) (*big.Int, error) {
+	if err := checkInteger(b); err != nil {
+		return nil, err
+	}
+	v := new(big.Int).SetBytes(b)
+	if b[0]&0x80 != 0 {
+		v.Sub(v, new(big.Int).Lsh(big.NewInt(1), uint(len(b))*8))
+	}
+	return v, nil
+}
+
+// AppendInt64 appends the minimal INTEGER contents of v.
+func AppendInt64(b []byte, v int64) []byte {
+	n := 1
+	for x := v; x > 127 || x < -128; x >>= 8 {
+		n++
+	}
+	for i := n - 1; i >= 0; i-- {
+		b = append(b, byte(v>>(uint(i)*8)))
+	}
+	return b
+}
+
+// AppendBigInt appends the minimal INTEGER contents of v.
+func AppendBigInt(b []byte, v *big.Int) []byte {
+	switch v.Sign() {
+	case 0:
+		return append(b, 0)
+	case 1:
+		m := v.Bytes()
+		if m[0]&0x80 != 0 {
+			b = append(b, 0)
+		}
+		return append(b, m...)
+	}
+	// Two's complement of a negative v is the bitwise not of -v-1.
+	m := new(big.Int).Not(v).Bytes()
+	if len(m) == 0 || m[0]&0x80 != 0 {
+		b = append(b, 0xff)
+	}
+	for _, c := range m {
+		b = append(b, ^c)
+	}
+	return b
+}
+
+// OID is an object identifier as a list of arcs.
+type OID []uint64
+
+func (oid OID) String() string {
+	var s strings.Builder
+	for i, arc := range oid {
+		if i > 0 {
+			s.WriteByte('.')
+		}
+		s.WriteString(strconv.FormatUint(arc, 10))
+	}
+	return s.String()
+}
+
+func (oid OID) Equal(other OID) bool {
+	if len(oid) != len(other) {
+		return false
+	}
+	for i := range oid {
+		if oid[i] != other[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// ParseOID decodes OBJECT IDENTIFIER contents. Arcs are base-128 with the
+// high bit marking continuation, the first one packs two arcs as
+// 40*x + y.
+func ParseOID(b []byte) (OID, error) {
+	if len(b) == 0 {
+		return nil, ErrInvalidValue
+	}
+	oid := OID{0}
+	var v uint64
+	start := true
+	for i, c := range b {
+		if start && c == 0x80 {
+			return nil, ErrInvalidValue
+		}
+		if v > math.MaxUint64>>7 {
+			return nil, ErrOverflow
+		}
+		v = v<<7 | uint64(c&0x7f)
+		start = c&0x80 == 0
+		if !start {
+			if i == len(b)-1 {
+				return nil, ErrInvalidValue
+			}
+			continue
+		}
+		if len(oid) == 1 {
+			switch {
+			case v < 40:
+				oid[0] = 0
+			case v < 80:
+				oid[0], v = 1, v-40
+			default:
+				oid[0], v = 2, v-80
+			}
+		}
+		oid = append(oid, v)
+		v = 0
+	}
+	return oid, nil
+}
+
+func appendBase128(b []byte, v uint64) []byte {
+	n := 1
+	for x := v >> 7; x > 0; x >>= 7 {
+		n++
+	}
+	for i := n - 1; i > 0; i-- {
+		b = append(b, byte(v>>(uint(i)*7))|0x80)
+	}
+	return append(b, byte(v&0x7f))
+}
+
+// AppendOID appends the OBJECT IDENTIFIER contents of oid. The first arc
+// must be 0, 1 or 2, the second below 40 unless the first is 2.
+func AppendOID(b []byte, oid OID) ([]byte, error) {
+	if len(oid) < 2 || oid[0] > 2 || oid[0] < 2 && oid[1] >= 40 || oid[1] > math.MaxUint64-80 {
+		return b, ErrInvalidValue
+	}
+	b = appendBase128(b, oid[0]*40+oid[1])
+	for _, arc := range oid[2:] {
+		b = appendBase128(b, arc)
+	}
+	return b, nil
+}
+
+// BitString is a string of bits, the first bit is the high bit of
+// Bytes[0].
+type BitString struct {
+	Bytes     []byte
+	BitLength int
+}
+
+// At returns bit i, zero when i is out of range.
+func (s BitString) At(i int) int {
+	if i < 0 || i >= s.BitLength {
+		return 0
+	}
+	return int(s.Bytes[i/8]>>(7-uint(i%8))) & 1
+}
+
+// ParseBitString decodes BIT STRING contents. DER requires the unused bits
+// to be zero.
+func ParseBitString(b []byte, der bool) (BitString, error) {
+	if len(b) == 0 || b[0] > 7 || len(b) == 1 && b[0] != 0 {
+		return BitString{}, ErrInvalidValue
+	}
+	unused := b[0]
+	if der && len(b) > 1 && b[len(b)-1]&(1<<unused-1) != 0 {
+		return BitString{}, ErrNotDER
+	}
+	return BitString{b[1:], (len(b)-1)*8 - int(unused)}, nil
+}
+
+// AppendBitString appends the BIT STRING contents of s with the unused
+// bits cleared.
+func AppendBitString(b []byte, s BitString) []byte {
+	n := (s.BitLength + 7) / 8
+	unused := byte(n*8 - s.BitLength)
+	b = append(b, unused)
+	b = append(b, s.Bytes[:n]...)
+	if n > 0 {
+		b[len(b)-1] &^= 1<<unused - 1
+	}
+	return b
+}
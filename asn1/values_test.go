@@ -0,0 +1,114 @@
+package asn1
+
+import (
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/funny/utest"
+)
+
+func Test_Values_Int(t *testing.T) {
+	for _, c := range []struct {
+		v int64
+		b []byte
+	}{
+		{0, []byte{0x00}},
+		{127, []byte{0x7f}},
+		{128, []byte{0x00, 0x80}},
+		{256, []byte{0x01, 0x00}},
+		{-1, []byte{0xff}},
+		{-128, []byte{0x80}},
+		{-129, []byte{0xff, 0x7f}},
+		{math.MaxInt64, []byte{0x7f, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}},
+		{math.MinInt64, []byte{0x80, 0, 0, 0, 0, 0, 0, 0}},
+	} {
+		utest.EqualNow(t, AppendInt64(nil, c.v), c.b)
+		v, err := ParseInt64(c.b)
+		utest.IsNilNow(t, err)
+		utest.EqualNow(t, v, c.v)
+		utest.EqualNow(t, AppendBigInt(nil, big.NewInt(c.v)), c.b)
+		bv, err := ParseBigInt(c.b)
+		utest.IsNilNow(t, err)
+		utest.EqualNow(t, bv.Int64(), c.v)
+	}
+
+	big1, _ := new(big.Int).SetString("-340282366920938463463374607431768211456", 10)
+	b := AppendBigInt(nil, big1)
+	utest.EqualNow(t, b, append([]byte{0xff}, make([]byte, 16)...))
+	v, err := ParseBigInt(b)
+	utest.IsNilNow(t, err)
+	utest.EqualNow(t, v.Cmp(big1), 0)
+
+	for _, bad := range [][]byte{{}, {0x00, 0x7f}, {0xff, 0x80}} {
+		_, err = ParseInt64(bad)
+		utest.EqualNow(t, err, ErrInvalidValue)
+	}
+	_, err = ParseInt64(b)
+	utest.EqualNow(t, err, ErrOverflow)
+}
+
+func Test_Values_OID(t *testing.T) {
+	for _, c := range []struct {
+		oid OID
+		b   []byte
+		s   string
+	}{
+		{OID{1, 2, 840, 113549}, []byte{0x2a, 0x86, 0x48, 0x86, 0xf7, 0x0d}, "1.2.840.113549"},
+		{OID{2, 999, 3}, []byte{0x88, 0x37, 0x03}, "2.999.3"},
+		{OID{0, 0}, []byte{0x00}, "0.0"},
+		{OID{2, 5, 4, 3}, []byte{0x55, 0x04, 0x03}, "2.5.4.3"},
+	} {
+		b, err := AppendOID(nil, c.oid)
+		utest.IsNilNow(t, err)
+		utest.EqualNow(t, b, c.b)
+		oid, err := ParseOID(c.b)
+		utest.IsNilNow(t, err)
+		utest.EqualNow(t, oid.Equal(c.oid), true)
+		utest.EqualNow(t, oid.String(), c.s)
+	}
+	for _, bad := range [][]byte{{}, {0x2a, 0x80, 0x01}, {0x2a, 0x86}} {
+		_, err := ParseOID(bad)
+		utest.EqualNow(t, err, ErrInvalidValue)
+	}
+	_, err := ParseOID([]byte{0x2a, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x7f})
+	utest.EqualNow(t, err, ErrOverflow)
+	for _, bad := range []OID{{1}, {3, 1}, {1, 40}} {
+		_, err := AppendOID(nil, bad)
+		utest.EqualNow(t, err, ErrInvalidValue)
+	}
+	utest.EqualNow(t, OID{1, 2}.Equal(OID{1, 2, 3}), false)
+}
+
+func Test_Values_Bool(t *testing.T) {
+	v, err := ParseBool([]byte{0x01}, false)
+	utest.IsNilNow(t, err)
+	utest.EqualNow(t, v, true)
+	_, err = ParseBool([]byte{0x01}, true)
+	utest.EqualNow(t, err, ErrNotDER)
+	v, err = ParseBool([]byte{0xff}, true)
+	utest.IsNilNow(t, err)
+	utest.EqualNow(t, v, true)
+	_, err = ParseBool([]byte{0, 0}, false)
+	utest.EqualNow(t, err, ErrInvalidValue)
+}
+
+func Test_Values_BitString(t *testing.T) {
+	s, err := ParseBitString([]byte{0x06, 0x6e, 0x5d, 0xc0}, true)
+	utest.IsNilNow(t, err)
+	utest.EqualNow(t, s.BitLength, 18)
+	utest.EqualNow(t, s.At(1), 1)
+	utest.EqualNow(t, s.At(0), 0)
+	utest.EqualNow(t, s.At(17), 1)
+	utest.EqualNow(t, s.At(18), 0)
+	utest.EqualNow(t, AppendBitString(nil, BitString{[]byte{0x6e, 0x5d, 0xff}, 18}), []byte{0x06, 0x6e, 0x5d, 0xc0})
+
+	_, err = ParseBitString([]byte{0x06, 0x6e, 0x5d, 0xe0}, true)
+	utest.EqualNow(t, err, ErrNotDER)
+	_, err = ParseBitString([]byte{0x06, 0x6e, 0x5d, 0xe0}, false)
+	utest.IsNilNow(t, err)
+	for _, bad := range [][]byte{{}, {0x01}, {0x08, 0x00}} {
+		_, err = ParseBitString(bad, false)
+		utest.EqualNow(t, err, ErrInvalidValue)
+	}
+}
@@ -0,0 +1,593 @@
+package binary
+
+import (
+	"errors"
+	"io"
+	"net"
+)
+
+var (
+	ErrInvalidOffset = errors.New("funny/binary.AtReader: invalid offset")
+	ErrInvalidWhence = errors.New("funny/binary.AtReader: invalid whence")
+)
+
+const atCacheSize = 512
+
+// AtReader reads from an io.ReaderAt. Sequential reads move the cursor,
+// the *At methods read at an absolute offset and leave the cursor alone.
+// Small reads are served from an internal cache, so the content of the
+// underlying io.ReaderAt is expected to be immutable.
+type AtReader struct {
+	R        io.ReaderAt
+	offset   int64
+	cache    [atCacheSize]byte
+	cacheOff int64
+	cacheLen int
+	err      error
+}
+
+func NewAtReader(r io.ReaderAt) *AtReader {
+	return &AtReader{R: r}
+}
+
+func (reader *AtReader) Reset(r io.ReaderAt) {
+	reader.R = r
+	reader.offset = 0
+	reader.cacheOff = 0
+	reader.cacheLen = 0
+	reader.err = nil
+}
+
+func (reader *AtReader) Error() error {
+	return reader.err
+}
+
+func (reader *AtReader) Offset() int64 {
+	return reader.offset
+}
+
+// Seek implements io.Seeker. io.SeekEnd is only supported when the
+// underlying io.ReaderAt has a Size() int64 method, like *bytes.Reader,
+// *strings.Reader and *io.SectionReader.
+func (reader *AtReader) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	case io.SeekStart:
+	case io.SeekCurrent:
+		offset += reader.offset
+	case io.SeekEnd:
+		sizer, ok := reader.R.(interface {
+			Size() int64
+		})
+		if !ok {
+			return reader.offset, ErrInvalidWhence
+		}
+		offset += sizer.Size()
+	default:
+		return reader.offset, ErrInvalidWhence
+	}
+	if offset < 0 {
+		return reader.offset, ErrInvalidOffset
+	}
+	reader.offset = offset
+	return offset, nil
+}
+
+// shortRead reports a ReadAt that returned less than wanted the same way
+// io.ReadFull does.
+func shortRead(n int, err error) error {
+	if err == nil || err == io.EOF {
+		if n == 0 {
+			return io.EOF
+		}
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
+
+func (reader *AtReader) fill(b []byte, off int64) {
+	if n, err := reader.R.ReadAt(b, off); n < len(b) {
+		reader.err = shortRead(n, err)
+	}
+}
+
+// load returns n bytes at off from the cache, refilling it when needed, or
+// nil after an error.
+func (reader *AtReader) load(off int64, n int) []byte {
+	if reader.err != nil {
+		return nil
+	}
+	if off < 0 {
+		reader.err = ErrInvalidOffset
+		return nil
+	}
+	if off >= reader.cacheOff && off+int64(n) <= reader.cacheOff+int64(reader.cacheLen) {
+		i := int(off - reader.cacheOff)
+		return reader.cache[i : i+n]
+	}
+	m, err := reader.R.ReadAt(reader.cache[:], off)
+	reader.cacheOff = off
+	reader.cacheLen = m
+	if m < n {
+		reader.err = shortRead(m, err)
+		return nil
+	}
+	return reader.cache[:n]
+}
+
+// at is load for the fixed-width readers, which decode zeros after an error.
+func (reader *AtReader) at(off int64, n int) []byte {
+	if b := reader.load(off, n); b != nil {
+		return b
+	}
+	return zero[:n]
+}
+
+func (reader *AtReader) seek(n int) (b []byte) {
+	b = reader.at(reader.offset, n)
+	if reader.err == nil {
+		reader.offset += int64(n)
+	}
+	return
+}
+
+func (reader *AtReader) Read(b []byte) (n int, err error) {
+	if reader.err == nil {
+		n, err = reader.R.ReadAt(b, reader.offset)
+		reader.offset += int64(n)
+		if n == len(b) && err == io.EOF {
+			err = nil
+		}
+		reader.err = err
+	}
+	return
+}
+
+func (reader *AtReader) ReadByte() (byte, error) {
+	return reader.ReadUint8(), reader.err
+}
+
+func (reader *AtReader) ReadBytesAt(off int64, n int) (b []byte) {
+	b = make([]byte, n)
+	if n <= atCacheSize {
+		copy(b, reader.load(off, n))
+	} else if reader.err == nil {
+		if off < 0 {
+			reader.err = ErrInvalidOffset
+		} else {
+			reader.fill(b, off)
+		}
+	}
+	return
+}
+
+func (reader *AtReader) ReadBytes(n int) (b []byte) {
+	b = reader.ReadBytesAt(reader.offset, n)
+	if reader.err == nil {
+		reader.offset += int64(n)
+	}
+	return
+}
+
+func (reader *AtReader) ReadStringAt(off int64, n int) string {
+	return string(reader.ReadBytesAt(off, n))
+}
+
+func (reader *AtReader) ReadString(n int) string {
+	return string(reader.ReadBytes(n))
+}
+
+func (reader *AtReader) ReadBCD(nDigits int) (v uint64) {
+	if reader.err == nil {
+		b := reader.ReadBytes(BCDSize(nDigits))
+		if reader.err == nil {
+			v, reader.err = GetBCD(b, nDigits)
+		}
+	}
+	return
+}
+
+func (reader *AtReader) ReadPackedDecimal(nDigits int) (v int64) {
+	if reader.err == nil {
+		b := reader.ReadBytes(PackedDecimalSize(nDigits))
+		if reader.err == nil {
+			v, reader.err = GetPackedDecimal(b, nDigits)
+		}
+	}
+	return
+}
+
+func (reader *AtReader) ReadIPv4() net.IP {
+	return net.IP(reader.ReadBytes(net.IPv4len))
+}
+
+func (reader *AtReader) ReadIPv6() net.IP {
+	return net.IP(reader.ReadBytes(net.IPv6len))
+}
+
+func (reader *AtReader) ReadMAC() net.HardwareAddr {
+	return net.HardwareAddr(reader.ReadBytes(MACLen))
+}
+
+func (reader *AtReader) ReadUUID() UUID {
+	return GetUUID(reader.ReadBytes(UUIDLen))
+}
+
+func (reader *AtReader) ReadGUID() UUID {
+	return GetGUID(reader.ReadBytes(UUIDLen))
+}
+
+func (reader *AtReader) ReadBools(n int, order BitOrder) []bool {
+	return GetBools(reader.ReadBytes(BoolsSize(n)), n, order)
+}
+
+func (reader *AtReader) ReadUvarint() (v uint64) {
+	if reader.err == nil {
+		v, reader.err = ReadUvarint(reader)
+	}
+	return
+}
+
+func (reader *AtReader) ReadVarint() (v int64) {
+	if reader.err == nil {
+		v, reader.err = ReadVarint(reader)
+	}
+	return
+}
+
+func (reader *AtReader) ReadQuicVarint() (v uint64) {
+	if reader.err == nil {
+		v, reader.err = ReadQuicVarint(reader)
+	}
+	return
+}
+
+func (reader *AtReader) ReadSQLiteVarint() (v uint64) {
+	if reader.err == nil {
+		v, reader.err = ReadSQLiteVarint(reader)
+	}
+	return
+}
+
+func (reader *AtReader) ReadVLQ() (v uint64) {
+	if reader.err == nil {
+		v, reader.err = ReadVLQ(reader)
+	}
+	return
+}
+
+func (reader *AtReader) ReadGitVarint() (v uint64) {
+	if reader.err == nil {
+		v, reader.err = ReadGitVarint(reader)
+	}
+	return
+}
+
+func (reader *AtReader) ReadUvarint128() (v Uint128) {
+	if reader.err == nil {
+		v, reader.err = ReadUvarint128(reader)
+	}
+	return
+}
+
+func (reader *AtReader) ReadVarint128() (v Int128) {
+	if reader.err == nil {
+		v, reader.err = ReadVarint128(reader)
+	}
+	return
+}
+
+func (reader *AtReader) ReadUint8() uint8 {
+	return uint8(reader.seek(1)[0])
+}
+
+func (reader *AtReader) ReadUint16BE() uint16 {
+	return GetUint16BE(reader.seek(2))
+}
+
+func (reader *AtReader) ReadUint16LE() uint16 {
+	return GetUint16LE(reader.seek(2))
+}
+
+func (reader *AtReader) ReadUint24BE() uint32 {
+	return GetUint24BE(reader.seek(3))
+}
+
+func (reader *AtReader) ReadUint24LE() uint32 {
+	return GetUint24LE(reader.seek(3))
+}
+
+func (reader *AtReader) ReadUint32BE() uint32 {
+	return GetUint32BE(reader.seek(4))
+}
+
+func (reader *AtReader) ReadUint32LE() uint32 {
+	return GetUint32LE(reader.seek(4))
+}
+
+func (reader *AtReader) ReadUint40BE() uint64 {
+	return GetUint40BE(reader.seek(5))
+}
+
+func (reader *AtReader) ReadUint40LE() uint64 {
+	return GetUint40LE(reader.seek(5))
+}
+
+func (reader *AtReader) ReadUint48BE() uint64 {
+	return GetUint48BE(reader.seek(6))
+}
+
+func (reader *AtReader) ReadUint48LE() uint64 {
+	return GetUint48LE(reader.seek(6))
+}
+
+func (reader *AtReader) ReadUint56BE() uint64 {
+	return GetUint56BE(reader.seek(7))
+}
+
+func (reader *AtReader) ReadUint56LE() uint64 {
+	return GetUint56LE(reader.seek(7))
+}
+
+func (reader *AtReader) ReadUint64BE() uint64 {
+	return GetUint64BE(reader.seek(8))
+}
+
+func (reader *AtReader) ReadUint64LE() uint64 {
+	return GetUint64LE(reader.seek(8))
+}
+
+func (reader *AtReader) ReadFloat32BE() float32 {
+	return GetFloat32BE(reader.seek(4))
+}
+
+func (reader *AtReader) ReadFloat32LE() float32 {
+	return GetFloat32LE(reader.seek(4))
+}
+
+func (reader *AtReader) ReadFloat64BE() float64 {
+	return GetFloat64BE(reader.seek(8))
+}
+
+func (reader *AtReader) ReadFloat64LE() float64 {
+	return GetFloat64LE(reader.seek(8))
+}
+
+func (reader *AtReader) ReadComplex64BE() complex64 {
+	return GetComplex64BE(reader.seek(8))
+}
+
+func (reader *AtReader) ReadComplex64LE() complex64 {
+	return GetComplex64LE(reader.seek(8))
+}
+
+func (reader *AtReader) ReadComplex128BE() complex128 {
+	return GetComplex128BE(reader.seek(16))
+}
+
+func (reader *AtReader) ReadComplex128LE() complex128 {
+	return GetComplex128LE(reader.seek(16))
+}
+
+func (reader *AtReader) ReadFloat80BE() float64 {
+	return GetFloat80BE(reader.seek(10))
+}
+
+func (reader *AtReader) ReadFloat80LE() float64 {
+	return GetFloat80LE(reader.seek(10))
+}
+
+func (reader *AtReader) ReadIBMFloat32BE() float64 {
+	return GetIBMFloat32BE(reader.seek(4))
+}
+
+func (reader *AtReader) ReadIBMFloat64BE() float64 {
+	return GetIBMFloat64BE(reader.seek(8))
+}
+
+func (reader *AtReader) ReadUint128BE() Uint128 {
+	return GetUint128BE(reader.seek(16))
+}
+
+func (reader *AtReader) ReadUint128LE() Uint128 {
+	return GetUint128LE(reader.seek(16))
+}
+
+func (reader *AtReader) ReadInt128BE() Int128 {
+	return GetInt128BE(reader.seek(16))
+}
+
+func (reader *AtReader) ReadInt128LE() Int128 {
+	return GetInt128LE(reader.seek(16))
+}
+
+func (reader *AtReader) ReadFloat16BE() float32 {
+	return GetFloat16BE(reader.seek(2))
+}
+
+func (reader *AtReader) ReadFloat16LE() float32 {
+	return GetFloat16LE(reader.seek(2))
+}
+
+func (reader *AtReader) ReadBFloat16BE() float32 {
+	return GetBFloat16BE(reader.seek(2))
+}
+
+func (reader *AtReader) ReadBFloat16LE() float32 {
+	return GetBFloat16LE(reader.seek(2))
+}
+
+func (reader *AtReader) ReadInt8() int8     { return int8(reader.ReadUint8()) }
+func (reader *AtReader) ReadInt16BE() int16 { return int16(reader.ReadUint16BE()) }
+func (reader *AtReader) ReadInt16LE() int16 { return int16(reader.ReadUint16LE()) }
+func (reader *AtReader) ReadInt24BE() int32 { return int32(reader.ReadUint24BE()) }
+func (reader *AtReader) ReadInt24LE() int32 { return int32(reader.ReadUint24LE()) }
+func (reader *AtReader) ReadInt32BE() int32 { return int32(reader.ReadUint32BE()) }
+func (reader *AtReader) ReadInt32LE() int32 { return int32(reader.ReadUint32LE()) }
+func (reader *AtReader) ReadInt40BE() int64 { return int64(reader.ReadUint40BE()) }
+func (reader *AtReader) ReadInt40LE() int64 { return int64(reader.ReadUint40LE()) }
+func (reader *AtReader) ReadInt48BE() int64 { return int64(reader.ReadUint48BE()) }
+func (reader *AtReader) ReadInt48LE() int64 { return int64(reader.ReadUint48LE()) }
+func (reader *AtReader) ReadInt56BE() int64 { return int64(reader.ReadUint56BE()) }
+func (reader *AtReader) ReadInt56LE() int64 { return int64(reader.ReadUint56LE()) }
+func (reader *AtReader) ReadInt64BE() int64 { return int64(reader.ReadUint64BE()) }
+func (reader *AtReader) ReadInt64LE() int64 { return int64(reader.ReadUint64LE()) }
+func (reader *AtReader) ReadIntBE() int     { return int(reader.ReadUint64BE()) }
+func (reader *AtReader) ReadIntLE() int     { return int(reader.ReadUint64LE()) }
+func (reader *AtReader) ReadUintBE() uint   { return uint(reader.ReadUint64BE()) }
+func (reader *AtReader) ReadUintLE() uint   { return uint(reader.ReadUint64LE()) }
+
+func (reader *AtReader) ReadUint8At(off int64) uint8 {
+	return uint8(reader.at(off, 1)[0])
+}
+
+func (reader *AtReader) ReadUint16BEAt(off int64) uint16 {
+	return GetUint16BE(reader.at(off, 2))
+}
+
+func (reader *AtReader) ReadUint16LEAt(off int64) uint16 {
+	return GetUint16LE(reader.at(off, 2))
+}
+
+func (reader *AtReader) ReadUint24BEAt(off int64) uint32 {
+	return GetUint24BE(reader.at(off, 3))
+}
+
+func (reader *AtReader) ReadUint24LEAt(off int64) uint32 {
+	return GetUint24LE(reader.at(off, 3))
+}
+
+func (reader *AtReader) ReadUint32BEAt(off int64) uint32 {
+	return GetUint32BE(reader.at(off, 4))
+}
+
+func (reader *AtReader) ReadUint32LEAt(off int64) uint32 {
+	return GetUint32LE(reader.at(off, 4))
+}
+
+func (reader *AtReader) ReadUint40BEAt(off int64) uint64 {
+	return GetUint40BE(reader.at(off, 5))
+}
+
+func (reader *AtReader) ReadUint40LEAt(off int64) uint64 {
+	return GetUint40LE(reader.at(off, 5))
+}
+
+func (reader *AtReader) ReadUint48BEAt(off int64) uint64 {
+	return GetUint48BE(reader.at(off, 6))
+}
+
+func (reader *AtReader) ReadUint48LEAt(off int64) uint64 {
+	return GetUint48LE(reader.at(off, 6))
+}
+
+func (reader *AtReader) ReadUint56BEAt(off int64) uint64 {
+	return GetUint56BE(reader.at(off, 7))
+}
+
+func (reader *AtReader) ReadUint56LEAt(off int64) uint64 {
+	return GetUint56LE(reader.at(off, 7))
+}
+
+func (reader *AtReader) ReadUint64BEAt(off int64) uint64 {
+	return GetUint64BE(reader.at(off, 8))
+}
+
+func (reader *AtReader) ReadUint64LEAt(off int64) uint64 {
+	return GetUint64LE(reader.at(off, 8))
+}
+
+func (reader *AtReader) ReadFloat32BEAt(off int64) float32 {
+	return GetFloat32BE(reader.at(off, 4))
+}
+
+func (reader *AtReader) ReadFloat32LEAt(off int64) float32 {
+	return GetFloat32LE(reader.at(off, 4))
+}
+
+func (reader *AtReader) ReadFloat64BEAt(off int64) float64 {
+	return GetFloat64BE(reader.at(off, 8))
+}
+
+func (reader *AtReader) ReadFloat64LEAt(off int64) float64 {
+	return GetFloat64LE(reader.at(off, 8))
+}
+
+func (reader *AtReader) ReadComplex64BEAt(off int64) complex64 {
+	return GetComplex64BE(reader.at(off, 8))
+}
+
+func (reader *AtReader) ReadComplex64LEAt(off int64) complex64 {
+	return GetComplex64LE(reader.at(off, 8))
+}
+
+func (reader *AtReader) ReadComplex128BEAt(off int64) complex128 {
+	return GetComplex128BE(reader.at(off, 16))
+}
+
+func (reader *AtReader) ReadComplex128LEAt(off int64) complex128 {
+	return GetComplex128LE(reader.at(off, 16))
+}
+
+func (reader *AtReader) ReadFloat80BEAt(off int64) float64 {
+	return GetFloat80BE(reader.at(off, 10))
+}
+
+func (reader *AtReader) ReadFloat80LEAt(off int64) float64 {
+	return GetFloat80LE(reader.at(off, 10))
+}
+
+func (reader *AtReader) ReadIBMFloat32BEAt(off int64) float64 {
+	return GetIBMFloat32BE(reader.at(off, 4))
+}
+
+func (reader *AtReader) ReadIBMFloat64BEAt(off int64) float64 {
+	return GetIBMFloat64BE(reader.at(off, 8))
+}
+
+func (reader *AtReader) ReadUint128BEAt(off int64) Uint128 {
+	return GetUint128BE(reader.at(off, 16))
+}
+
+func (reader *AtReader) ReadUint128LEAt(off int64) Uint128 {
+	return GetUint128LE(reader.at(off, 16))
+}
+
+func (reader *AtReader) ReadInt128BEAt(off int64) Int128 {
+	return GetInt128BE(reader.at(off, 16))
+}
+
+func (reader *AtReader) ReadInt128LEAt(off int64) Int128 {
+	return GetInt128LE(reader.at(off, 16))
+}
+
+func (reader *AtReader) ReadFloat16BEAt(off int64) float32 {
+	return GetFloat16BE(reader.at(off, 2))
+}
+
+func (reader *AtReader) ReadFloat16LEAt(off int64) float32 {
+	return GetFloat16LE(reader.at(off, 2))
+}
+
+func (reader *AtReader) ReadBFloat16BEAt(off int64) float32 {
+	return GetBFloat16BE(reader.at(off, 2))
+}
+
+func (reader *AtReader) ReadBFloat16LEAt(off int64) float32 {
+	return GetBFloat16LE(reader.at(off, 2))
+}
+
+func (reader *AtReader) ReadInt8At(off int64) int8     { return int8(reader.ReadUint8At(off)) }
+func (reader *AtReader) ReadInt16BEAt(off int64) int16 { return int16(reader.ReadUint16BEAt(off)) }
+func (reader *AtReader) ReadInt16LEAt(off int64) int16 { return int16(reader.ReadUint16LEAt(off)) }
+func (reader *AtReader) ReadInt24BEAt(off int64) int32 { return int32(reader.ReadUint24BEAt(off)) }
+func (reader *AtReader) ReadInt24LEAt(off int64) int32 { return int32(reader.ReadUint24LEAt(off)) }
+func (reader *AtReader) ReadInt32BEAt(off int64) int32 { return int32(reader.ReadUint32BEAt(off)) }
+func (reader *AtReader) ReadInt32LEAt(off int64) int32 { return int32(reader.ReadUint32LEAt(off)) }
+func (reader *AtReader) ReadInt40BEAt(off int64) int64 { return int64(reader.ReadUint40BEAt(off)) }
+func (reader *AtReader) ReadInt40LEAt(off int64) int64 { return int64(reader.ReadUint40LEAt(off)) }
+func (reader *AtReader) ReadInt48BEAt(off int64) int64 { return int64(reader.ReadUint48BEAt(off)) }
+func (reader *AtReader) ReadInt48LEAt(off int64) int64 { return int64(reader.ReadUint48LEAt(off)) }
+func (reader *AtReader) ReadInt56BEAt(off int64) int64 { return int64(reader.ReadUint56BEAt(off)) }
+func (reader *AtReader) ReadInt56LEAt(off int64) int64 { return int64(reader.ReadUint56LEAt(off)) }
+func (reader *AtReader) ReadInt64BEAt(off int64) int64 { return int64(reader.ReadUint64BEAt(off)) }
+func (reader *AtReader) ReadInt64LEAt(off int64) int64 { return int64(reader.ReadUint64LEAt(off)) }
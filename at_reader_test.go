@@ -0,0 +1,157 @@
+package binary
+
+import (
+	"bytes"
+	"io"
+	"math/rand"
+	"testing"
+
+	"github.com/funny/utest"
+)
+
+func AtReaderTest(t *testing.T, f1 func(w *Writer), f2 func(r *AtReader)) {
+	var buf bytes.Buffer
+	var w Writer
+	var r AtReader
+	for i := 0; i < 10000; i++ {
+		buf.Reset()
+		w.Reset(&buf)
+		f1(&w)
+		utest.IsNilNow(t, w.Error())
+
+		r.Reset(bytes.NewReader(buf.Bytes()))
+		f2(&r)
+		utest.IsNilNow(t, r.Error())
+	}
+}
+
+func Test_AtReader_ReadWrite(t *testing.T) {
+	var b []byte
+	AtReaderTest(t, func(w *Writer) {
+		b = RandBytes(256)
+		w.Write(b)
+	}, func(r *AtReader) {
+		c := make([]byte, len(b))
+		n, err := r.Read(c)
+		utest.IsNilNow(t, err)
+		utest.EqualNow(t, n, len(b))
+		utest.EqualNow(t, b, c)
+	})
+}
+
+func Test_AtReader_Bytes(t *testing.T) {
+	var b []byte
+	AtReaderTest(t, func(w *Writer) {
+		b = RandBytes(2048)
+		w.WriteBytes(b)
+	}, func(r *AtReader) {
+		utest.EqualNow(t, r.ReadBytes(len(b)), b)
+		utest.EqualNow(t, r.Offset(), int64(len(b)))
+	})
+}
+
+func Test_AtReader_Uvarint(t *testing.T) {
+	var v1 uint64
+	AtReaderTest(t, func(w *Writer) {
+		v1 = uint64(rand.Int63())
+		w.WriteUvarint(v1)
+	}, func(r *AtReader) {
+		utest.EqualNow(t, r.ReadUvarint(), v1)
+	})
+}
+
+func Test_AtReader_Varint(t *testing.T) {
+	var v1 int64
+	AtReaderTest(t, func(w *Writer) {
+		v1 = rand.Int63() - rand.Int63()
+		w.WriteVarint(v1)
+	}, func(r *AtReader) {
+		utest.EqualNow(t, r.ReadVarint(), v1)
+	})
+}
+
+func Test_AtReader_Sequence(t *testing.T) {
+	var v1 uint16
+	var v2 uint32
+	var v3 uint64
+	var v4 float64
+	AtReaderTest(t, func(w *Writer) {
+		v1 = uint16(rand.Intn(0xFFFF))
+		v2 = uint32(rand.Intn(0xFFFFFF))
+		v3 = uint64(rand.Intn(0xFFFFFFFFFFFF))
+		v4 = rand.Float64()
+		w.WriteUint16BE(v1)
+		w.WriteUint24LE(v2)
+		w.WriteUint48BE(v3)
+		w.WriteFloat64LE(v4)
+	}, func(r *AtReader) {
+		utest.EqualNow(t, r.ReadUint16BE(), v1)
+		utest.EqualNow(t, r.ReadUint24LE(), v2)
+		utest.EqualNow(t, r.ReadUint48BE(), v3)
+		utest.EqualNow(t, r.ReadFloat64LE(), v4)
+		utest.EqualNow(t, r.Offset(), int64(19))
+	})
+}
+
+func Test_AtReader_At(t *testing.T) {
+	var data = make([]byte, 4096)
+	for i := 0; i+4 <= len(data); i += 4 {
+		PutUint32BE(data[i:], uint32(i))
+	}
+	r := NewAtReader(bytes.NewReader(data))
+	for i := 0; i < 10000; i++ {
+		off := int64(rand.Intn(len(data)/4) * 4)
+		utest.EqualNow(t, r.ReadUint32BEAt(off), uint32(off))
+		utest.EqualNow(t, r.ReadUint16BEAt(off+2), uint16(off))
+		utest.EqualNow(t, r.Offset(), int64(0))
+	}
+	utest.IsNilNow(t, r.Error())
+}
+
+func Test_AtReader_Seek(t *testing.T) {
+	var data = make([]byte, 64)
+	for i := 0; i < len(data); i++ {
+		data[i] = byte(i)
+	}
+	r := NewAtReader(bytes.NewReader(data))
+
+	off, err := r.Seek(10, io.SeekStart)
+	utest.IsNilNow(t, err)
+	utest.EqualNow(t, off, int64(10))
+	utest.EqualNow(t, r.ReadUint8(), 10)
+
+	off, err = r.Seek(5, io.SeekCurrent)
+	utest.IsNilNow(t, err)
+	utest.EqualNow(t, off, int64(16))
+	utest.EqualNow(t, r.ReadUint8(), 16)
+
+	off, err = r.Seek(-2, io.SeekEnd)
+	utest.IsNilNow(t, err)
+	utest.EqualNow(t, off, int64(62))
+	utest.EqualNow(t, r.ReadUint16BE(), 0x3E3F)
+
+	_, err = r.Seek(-1, io.SeekStart)
+	utest.EqualNow(t, err, ErrInvalidOffset)
+	utest.EqualNow(t, r.Offset(), int64(64))
+	utest.IsNilNow(t, r.Error())
+}
+
+func Test_AtReader_EOF(t *testing.T) {
+	r := NewAtReader(bytes.NewReader([]byte{1, 2, 3}))
+	utest.EqualNow(t, r.ReadUint16BE(), 0x0102)
+	utest.EqualNow(t, r.ReadUint16BE(), 0)
+	utest.EqualNow(t, r.Error(), io.ErrUnexpectedEOF)
+	utest.EqualNow(t, r.Offset(), int64(2))
+
+	r.Reset(bytes.NewReader([]byte{1, 2, 3}))
+	utest.EqualNow(t, r.ReadUint32LEAt(3), 0)
+	utest.EqualNow(t, r.Error(), io.EOF)
+}
+
+func Test_AtReader_ShortBytes(t *testing.T) {
+	r := NewAtReader(bytes.NewReader([]byte("abc")))
+	utest.EqualNow(t, r.ReadBytes(20), make([]byte, 20))
+	utest.EqualNow(t, r.Error(), io.ErrUnexpectedEOF)
+	utest.EqualNow(t, r.ReadBytes(30), make([]byte, 30))
+	utest.EqualNow(t, r.ReadString(25), string(make([]byte, 25)))
+}
@@ -0,0 +1,57 @@
+// Package avro implements the Apache Avro binary encoding on top of the
+// BinaryReader and BinaryWriter interfaces of github.com/funny/binary.
+//
+// Values are generic and follow the schema: null is nil, boolean is bool,
+// int and long are int32 and int64, float and double are float32 and
+// float64, bytes and fixed are []byte, string and enum are string, array is
+// []interface{}, and map and record are map[string]interface{}. A union
+// value is the value of its branch. Logical types are not converted.
+package avro
+
+import "errors"
+
+var (
+	ErrInvalidSchema    = errors.New("funny/binary/avro: invalid schema")
+	ErrUnknownType      = errors.New("funny/binary/avro: unknown type name")
+	ErrTypeMismatch     = errors.New("funny/binary/avro: value does not match schema")
+	ErrMissingField     = errors.New("funny/binary/avro: record field missing and has no default")
+	ErrInvalidData      = errors.New("funny/binary/avro: malformed data")
+	ErrTooLarge         = errors.New("funny/binary/avro: length exceeds limit")
+	ErrTooDeep          = errors.New("funny/binary/avro: nesting exceeds limit")
+	ErrNotContainer     = errors.New("funny/binary/avro: not an object container file")
+	ErrBadSync          = errors.New("funny/binary/avro: sync marker mismatch")
+	ErrUnsupportedCodec = errors.New("funny/binary/avro: unsupported codec")
+)
+
+// Type is the type of a schema.
+type Type string
+
+const (
+	Null    Type = "null"
+	Boolean Type = "boolean"
+	Int     Type = "int"
+	Long    Type = "long"
+	Float   Type = "float"
+	Double  Type = "double"
+	Bytes   Type = "bytes"
+	String  Type = "string"
+	Record  Type = "record"
+	Enum    Type = "enum"
+	Array   Type = "array"
+	Map     Type = "map"
+	Union   Type = "union"
+	Fixed   Type = "fixed"
+)
+
+// DefaultMaxLen limits bytes, strings and block counts when MaxLen of a
+// Decoder is zero.
+const DefaultMaxLen = 16 << 20
+
+const maxDepth = 1000
+
+// Branch selects a union branch explicitly when encoding, for values that
+// would match an earlier branch too.
+type Branch struct {
+	Index int
+	Value interface{}
+}
@@ -0,0 +1,212 @@
+package avro
+
+import (
+	"math"
+
+	"github.com/funny/binary"
+)
+
+// Decoder reads values of one schema. Errors of the BinaryReader are
+// returned as they are, the decoder must not be used after an error.
+type Decoder struct {
+	r      binary.BinaryReader
+	schema *Schema
+
+	// MaxLen limits lengths and block counts, zero means DefaultMaxLen.
+	MaxLen int
+}
+
+func NewDecoder(r binary.BinaryReader, s *Schema) *Decoder {
+	return &Decoder{r: r, schema: s}
+}
+
+func (d *Decoder) maxLen() int {
+	if d.MaxLen > 0 {
+		return d.MaxLen
+	}
+	return DefaultMaxLen
+}
+
+// Decode reads one value.
+func (d *Decoder) Decode() (interface{}, error) {
+	return d.decode(d.schema, 0)
+}
+
+func (d *Decoder) readLong() (int64, error) {
+	v := d.r.ReadVarint()
+	return v, d.r.Error()
+}
+
+func (d *Decoder) readLen() (int, error) {
+	n, err := d.readLong()
+	if err != nil {
+		return 0, err
+	}
+	if n < 0 {
+		return 0, ErrInvalidData
+	}
+	if n > int64(d.maxLen()) {
+		return 0, ErrTooLarge
+	}
+	return int(n), nil
+}
+
+// readBlock returns the item count of the next array or map block, zero at
+// the end. The byte size of a negative count block is not needed.
+func (d *Decoder) readBlock() (int, error) {
+	n, err := d.readLong()
+	if err != nil {
+		return 0, err
+	}
+	if n < 0 {
+		if n == math.MinInt64 {
+			return 0, ErrInvalidData
+		}
+		n = -n
+		if _, err := d.readLong(); err != nil {
+			return 0, err
+		}
+	}
+	if n > int64(d.maxLen()) {
+		return 0, ErrTooLarge
+	}
+	return int(n), nil
+}
+
+// initialCap keeps a forged block count from allocating up front.
+func initialCap(n int) int {
+	if n > 1024 {
+		return 1024
+	}
+	return n
+}
+
+func (d *Decoder) decode(s *Schema, depth int) (interface{}, error) {
+	if depth > maxDepth {
+		return nil, ErrTooDeep
+	}
+	switch s.Type {
+	case Null:
+		return nil, nil
+	case Boolean:
+		b := d.r.ReadUint8()
+		if err := d.r.Error(); err != nil {
+			return nil, err
+		}
+		if b > 1 {
+			return nil, ErrInvalidData
+		}
+		return b == 1, nil
+	case Int:
+		n, err := d.readLong()
+		if err != nil {
+			return nil, err
+		}
+		if n < math.MinInt32 || n > math.MaxInt32 {
+			return nil, ErrInvalidData
+		}
+		return int32(n), nil
+	case Long:
+		return d.readLong()
+	case Float:
+		f := d.r.ReadFloat32LE()
+		return f, d.r.Error()
+	case Double:
+		f := d.r.ReadFloat64LE()
+		return f, d.r.Error()
+	case Bytes:
+		n, err := d.readLen()
+		if err != nil {
+			return nil, err
+		}
+		b := d.r.ReadBytes(n)
+		return b, d.r.Error()
+	case String:
+		n, err := d.readLen()
+		if err != nil {
+			return nil, err
+		}
+		str := d.r.ReadString(n)
+		return str, d.r.Error()
+	case Fixed:
+		b := d.r.ReadBytes(s.Size)
+		return b, d.r.Error()
+	case Enum:
+		i, err := d.readLong()
+		if err != nil {
+			return nil, err
+		}
+		if i < 0 || i >= int64(len(s.Symbols)) {
+			return nil, ErrInvalidData
+		}
+		return s.Symbols[i], nil
+	case Array:
+		a := []interface{}{}
+		for {
+			n, err := d.readBlock()
+			if err != nil {
+				return nil, err
+			}
+			if n == 0 {
+				return a, nil
+			}
+			if len(a)+n > d.maxLen() {
+				return nil, ErrTooLarge
+			}
+			if len(a) == 0 {
+				a = make([]interface{}, 0, initialCap(n))
+			}
+			for i := 0; i < n; i++ {
+				v, err := d.decode(s.Items, depth+1)
+				if err != nil {
+					return nil, err
+				}
+				a = append(a, v)
+			}
+		}
+	case Map:
+		m := make(map[string]interface{})
+		for {
+			n, err := d.readBlock()
+			if err != nil {
+				return nil, err
+			}
+			if n == 0 {
+				return m, nil
+			}
+			for i := 0; i < n; i++ {
+				kn, err := d.readLen()
+				if err != nil {
+					return nil, err
+				}
+				key := d.r.ReadString(kn)
+				if err := d.r.Error(); err != nil {
+					return nil, err
+				}
+				if m[key], err = d.decode(s.Values, depth+1); err != nil {
+					return nil, err
+				}
+			}
+		}
+	case Record:
+		m := make(map[string]interface{}, len(s.Fields))
+		for _, f := range s.Fields {
+			v, err := d.decode(f.Type, depth+1)
+			if err != nil {
+				return nil, err
+			}
+			m[f.Name] = v
+		}
+		return m, nil
+	case Union:
+		i, err := d.readLong()
+		if err != nil {
+			return nil, err
+		}
+		if i < 0 || i >= int64(len(s.Branches)) {
+			return nil, ErrInvalidData
+		}
+		return d.decode(s.Branches[i], depth+1)
+	}
+	return nil, ErrInvalidSchema
+}
@@ -0,0 +1,90 @@
+package avro
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/funny/binary"
+	"github.com/funny/utest"
+)
+
+func decode(s *Schema, b []byte) (interface{}, error) {
+	return NewDecoder(binary.NewReader(bytes.NewReader(b)), s).Decode()
+}
+
+func Test_Decoder_RoundTrip(t *testing.T) {
+	s := MustParseSchema(`{
+		"type": "record", "name": "Node", "fields": [
+			{"name": "b", "type": "boolean"},
+			{"name": "i", "type": "int"},
+			{"name": "l", "type": "long"},
+			{"name": "f", "type": "float"},
+			{"name": "d", "type": "double"},
+			{"name": "bs", "type": "bytes"},
+			{"name": "s", "type": "string"},
+			{"name": "e", "type": {"type": "enum", "name": "E", "symbols": ["A", "B"]}},
+			{"name": "x", "type": {"type": "fixed", "name": "X", "size": 3}},
+			{"name": "m", "type": {"type": "map", "values": "string"}},
+			{"name": "children", "type": {"type": "array", "items": "Node"}},
+			{"name": "next", "type": ["null", "Node"]}
+		]}`)
+	leaf := map[string]interface{}{
+		"b": false, "i": int32(-1), "l": int64(1) << 62, "f": float32(0.5), "d": -0.25,
+		"bs": []byte{}, "s": "", "e": "A", "x": []byte{0, 0, 0},
+		"m": map[string]interface{}{}, "children": []interface{}{}, "next": nil,
+	}
+	root := map[string]interface{}{
+		"b": true, "i": int32(7), "l": int64(-7), "f": float32(1.5), "d": 2.5,
+		"bs": []byte{1, 2, 3}, "s": "héllo", "e": "B", "x": []byte{7, 8, 9},
+		"m":        map[string]interface{}{"k1": "v1", "k2": "v2"},
+		"children": []interface{}{leaf, leaf},
+		"next":     leaf,
+	}
+	v, err := decode(s, encode(t, s, root))
+	utest.IsNilNow(t, err)
+	utest.EqualNow(t, v, root)
+}
+
+func Test_Decoder_Blocks(t *testing.T) {
+	// Two blocks, the second with a negative count followed by its size.
+	b := []byte{0x02, 0x02, 0x03, 0x04, 0x04, 0x06, 0x00}
+	v, err := decode(MustParseSchema(`{"type": "array", "items": "int"}`), b)
+	utest.IsNilNow(t, err)
+	utest.EqualNow(t, v, []interface{}{int32(1), int32(2), int32(3)})
+
+	b = []byte{0x01, 0x08, 0x02, 'a', 0x02, 0x02, 0x02, 'b', 0x04, 0x00}
+	v, err = decode(MustParseSchema(`{"type": "map", "values": "int"}`), b)
+	utest.IsNilNow(t, err)
+	utest.EqualNow(t, v, map[string]interface{}{"a": int32(1), "b": int32(2)})
+}
+
+func Test_Decoder_Errors(t *testing.T) {
+	for _, c := range []struct {
+		schema string
+		data   []byte
+		err    error
+	}{
+		{`"boolean"`, []byte{2}, ErrInvalidData},
+		{`"int"`, []byte{0x80, 0x80, 0x80, 0x80, 0x10}, ErrInvalidData},
+		{`"string"`, []byte{0x01}, ErrInvalidData},
+		{`"string"`, []byte{0x04, 'a'}, io.ErrUnexpectedEOF},
+		{`"bytes"`, []byte{0x80, 0x80, 0x80, 0x20}, ErrTooLarge},
+		{`{"type": "enum", "name": "E", "symbols": ["A"]}`, []byte{0x02}, ErrInvalidData},
+		{`["null"]`, []byte{0x02}, ErrInvalidData},
+		{`{"type": "array", "items": "int"}`, []byte{0x02}, io.EOF},
+	} {
+		_, err := decode(MustParseSchema(c.schema), c.data)
+		utest.EqualNow(t, err, c.err)
+	}
+
+	// Zero sized items still count against MaxLen across blocks.
+	d := NewDecoder(binary.NewReader(bytes.NewReader([]byte{0x14, 0x02})), MustParseSchema(`{"type": "array", "items": "null"}`))
+	d.MaxLen = 10
+	_, err := d.Decode()
+	utest.EqualNow(t, err, ErrTooLarge)
+
+	deep := MustParseSchema(`{"type": "record", "name": "R", "fields": [{"name": "r", "type": ["null", "R"]}]}`)
+	_, err = decode(deep, bytes.Repeat([]byte{0x02}, maxDepth))
+	utest.EqualNow(t, err, ErrTooDeep)
+}
@@ -0,0 +1,233 @@
+package avro
+
+import (
+	"math"
+	"reflect"
+
+	"github.com/funny/binary"
+)
+
+// Encoder writes values of one schema. Write errors are left in the
+// BinaryWriter, Encode only reports values that do not match the schema.
+type Encoder struct {
+	w      binary.BinaryWriter
+	schema *Schema
+}
+
+func NewEncoder(w binary.BinaryWriter, s *Schema) *Encoder {
+	return &Encoder{w: w, schema: s}
+}
+
+// Encode writes v. Besides the types listed in the package documentation
+// any integer, float, slice or string keyed map of the right kind is
+// accepted, and a union branch is picked by the first match unless v is a
+// Branch.
+func (e *Encoder) Encode(v interface{}) error {
+	return e.encode(e.schema, v, 0)
+}
+
+func (e *Encoder) encode(s *Schema, v interface{}, depth int) error {
+	if depth > maxDepth {
+		return ErrTooDeep
+	}
+	switch s.Type {
+	case Null:
+		if v != nil {
+			return ErrTypeMismatch
+		}
+	case Boolean:
+		b, ok := v.(bool)
+		if !ok {
+			return ErrTypeMismatch
+		}
+		if b {
+			e.w.WriteUint8(1)
+		} else {
+			e.w.WriteUint8(0)
+		}
+	case Int, Long:
+		n, ok := toInt(v, s.Type)
+		if !ok {
+			return ErrTypeMismatch
+		}
+		e.w.WriteVarint(n)
+	case Float:
+		f, ok := toFloat(v)
+		if !ok {
+			return ErrTypeMismatch
+		}
+		e.w.WriteFloat32LE(float32(f))
+	case Double:
+		f, ok := toFloat(v)
+		if !ok {
+			return ErrTypeMismatch
+		}
+		e.w.WriteFloat64LE(f)
+	case Bytes:
+		b, ok := v.([]byte)
+		if !ok {
+			return ErrTypeMismatch
+		}
+		e.w.WriteVarint(int64(len(b)))
+		e.w.WriteBytes(b)
+	case String:
+		str, ok := v.(string)
+		if !ok {
+			return ErrTypeMismatch
+		}
+		e.w.WriteVarint(int64(len(str)))
+		e.w.WriteString(str)
+	case Fixed:
+		b, ok := v.([]byte)
+		if !ok || len(b) != s.Size {
+			return ErrTypeMismatch
+		}
+		e.w.WriteBytes(b)
+	case Enum:
+		str, _ := v.(string)
+		i, ok := s.symbols[str]
+		if !ok {
+			return ErrTypeMismatch
+		}
+		e.w.WriteVarint(int64(i))
+	case Array:
+		rv := reflect.ValueOf(v)
+		if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+			return ErrTypeMismatch
+		}
+		if rv.Len() > 0 {
+			e.w.WriteVarint(int64(rv.Len()))
+			for i := 0; i < rv.Len(); i++ {
+				if err := e.encode(s.Items, rv.Index(i).Interface(), depth+1); err != nil {
+					return err
+				}
+			}
+		}
+		e.w.WriteVarint(0)
+	case Map:
+		rv := reflect.ValueOf(v)
+		if rv.Kind() != reflect.Map || rv.Type().Key().Kind() != reflect.String {
+			return ErrTypeMismatch
+		}
+		if rv.Len() > 0 {
+			e.w.WriteVarint(int64(rv.Len()))
+			iter := rv.MapRange()
+			for iter.Next() {
+				key := iter.Key().String()
+				e.w.WriteVarint(int64(len(key)))
+				e.w.WriteString(key)
+				if err := e.encode(s.Values, iter.Value().Interface(), depth+1); err != nil {
+					return err
+				}
+			}
+		}
+		e.w.WriteVarint(0)
+	case Record:
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return ErrTypeMismatch
+		}
+		for _, f := range s.Fields {
+			fv, ok := m[f.Name]
+			if !ok {
+				if !f.HasDefault {
+					return ErrMissingField
+				}
+				fv = f.Default
+			}
+			if err := e.encode(f.Type, fv, depth+1); err != nil {
+				return err
+			}
+		}
+	case Union:
+		if b, ok := v.(Branch); ok {
+			if b.Index < 0 || b.Index >= len(s.Branches) {
+				return ErrTypeMismatch
+			}
+			e.w.WriteVarint(int64(b.Index))
+			return e.encode(s.Branches[b.Index], b.Value, depth+1)
+		}
+		for i, b := range s.Branches {
+			if matches(b, v) {
+				e.w.WriteVarint(int64(i))
+				return e.encode(b, v, depth+1)
+			}
+		}
+		return ErrTypeMismatch
+	default:
+		return ErrInvalidSchema
+	}
+	return nil
+}
+
+func toInt(v interface{}, t Type) (int64, bool) {
+	rv := reflect.ValueOf(v)
+	var n int64
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n = rv.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if rv.Uint() > math.MaxInt64 {
+			return 0, false
+		}
+		n = int64(rv.Uint())
+	default:
+		return 0, false
+	}
+	if t == Int && (n < math.MinInt32 || n > math.MaxInt32) {
+		return 0, false
+	}
+	return n, true
+}
+
+func toFloat(v interface{}) (float64, bool) {
+	switch v := v.(type) {
+	case float32:
+		return float64(v), true
+	case float64:
+		return v, true
+	}
+	return 0, false
+}
+
+// matches reports whether v can be encoded as s, for picking a union
+// branch. Records and maps both match a map, the earlier branch wins.
+func matches(s *Schema, v interface{}) bool {
+	switch s.Type {
+	case Null:
+		return v == nil
+	case Boolean:
+		_, ok := v.(bool)
+		return ok
+	case Int, Long:
+		_, ok := toInt(v, s.Type)
+		return ok
+	case Float, Double:
+		_, ok := toFloat(v)
+		return ok
+	case Bytes:
+		_, ok := v.([]byte)
+		return ok
+	case Fixed:
+		b, ok := v.([]byte)
+		return ok && len(b) == s.Size
+	case String:
+		_, ok := v.(string)
+		return ok
+	case Enum:
+		str, _ := v.(string)
+		_, ok := s.symbols[str]
+		return ok
+	case Array:
+		k := reflect.ValueOf(v).Kind()
+		_, isBytes := v.([]byte)
+		return (k == reflect.Slice || k == reflect.Array) && !isBytes
+	case Map:
+		rv := reflect.ValueOf(v)
+		return rv.Kind() == reflect.Map && rv.Type().Key().Kind() == reflect.String
+	case Record:
+		_, ok := v.(map[string]interface{})
+		return ok
+	}
+	return false
+}
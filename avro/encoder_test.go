@@ -0,0 +1,84 @@
+package avro
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/funny/binary"
+	"github.com/funny/utest"
+)
+
+func encode(t *testing.T, s *Schema, v interface{}) []byte {
+	var buf bytes.Buffer
+	w := binary.NewWriter(&buf)
+	utest.IsNilNow(t, NewEncoder(w, s).Encode(v))
+	utest.IsNilNow(t, w.Error())
+	return buf.Bytes()
+}
+
+func Test_Encoder_Spec(t *testing.T) {
+	long := MustParseSchema(`"long"`)
+	for v, b := range map[int64][]byte{0: {0x00}, -1: {0x01}, 1: {0x02}, -64: {0x7f}, 64: {0x80, 0x01}} {
+		utest.EqualNow(t, encode(t, long, v), b)
+	}
+	utest.EqualNow(t, encode(t, MustParseSchema(`"string"`), "foo"), []byte{0x06, 'f', 'o', 'o'})
+
+	record := MustParseSchema(`{"type": "record", "name": "test", "fields": [
+		{"name": "a", "type": "long"}, {"name": "b", "type": "string"}]}`)
+	utest.EqualNow(t, encode(t, record, map[string]interface{}{"a": 27, "b": "foo"}), []byte{0x36, 0x06, 'f', 'o', 'o'})
+
+	array := MustParseSchema(`{"type": "array", "items": "long"}`)
+	utest.EqualNow(t, encode(t, array, []int{3, 27}), []byte{0x04, 0x06, 0x36, 0x00})
+	utest.EqualNow(t, encode(t, array, []int64{}), []byte{0x00})
+
+	union := MustParseSchema(`["null", "string"]`)
+	utest.EqualNow(t, encode(t, union, nil), []byte{0x00})
+	utest.EqualNow(t, encode(t, union, "a"), []byte{0x02, 0x02, 'a'})
+}
+
+func Test_Encoder_Types(t *testing.T) {
+	utest.EqualNow(t, encode(t, MustParseSchema(`"boolean"`), true), []byte{1})
+	utest.EqualNow(t, encode(t, MustParseSchema(`"float"`), float32(1)), []byte{0, 0, 0x80, 0x3f})
+	utest.EqualNow(t, encode(t, MustParseSchema(`"double"`), 1.0), []byte{0, 0, 0, 0, 0, 0, 0xf0, 0x3f})
+	utest.EqualNow(t, encode(t, MustParseSchema(`"bytes"`), []byte{1, 2}), []byte{4, 1, 2})
+	utest.EqualNow(t, encode(t, MustParseSchema(`{"type": "fixed", "name": "F", "size": 2}`), []byte{1, 2}), []byte{1, 2})
+	utest.EqualNow(t, encode(t, MustParseSchema(`{"type": "enum", "name": "E", "symbols": ["A", "B"]}`), "B"), []byte{2})
+	utest.EqualNow(t, encode(t, MustParseSchema(`{"type": "map", "values": "int"}`), map[string]int32{"k": 1}), []byte{2, 2, 'k', 2, 0})
+
+	// The first matching branch wins unless a Branch picks one.
+	union := MustParseSchema(`["int", "long", {"type": "map", "values": "int"}, {"type": "record", "name": "R", "fields": []}]`)
+	utest.EqualNow(t, encode(t, union, 1), []byte{0, 2})
+	utest.EqualNow(t, encode(t, union, int64(1)<<40), []byte{2, 0x80, 0x80, 0x80, 0x80, 0x80, 0x40})
+	utest.EqualNow(t, encode(t, union, map[string]interface{}{}), []byte{4, 0})
+	utest.EqualNow(t, encode(t, union, Branch{3, map[string]interface{}{}}), []byte{6})
+
+	// Missing fields take their defaults.
+	record := MustParseSchema(`{"type": "record", "name": "R", "fields": [
+		{"name": "a", "type": ["null", "int"], "default": null},
+		{"name": "b", "type": "string", "default": "x"}]}`)
+	utest.EqualNow(t, encode(t, record, map[string]interface{}{}), []byte{0, 2, 'x'})
+}
+
+func Test_Encoder_Errors(t *testing.T) {
+	for _, c := range []struct {
+		schema string
+		value  interface{}
+		err    error
+	}{
+		{`"null"`, 0, ErrTypeMismatch},
+		{`"int"`, int64(1) << 31, ErrTypeMismatch},
+		{`"long"`, uint64(1) << 63, ErrTypeMismatch},
+		{`"double"`, 1, ErrTypeMismatch},
+		{`"string"`, []byte("x"), ErrTypeMismatch},
+		{`{"type": "fixed", "name": "F", "size": 2}`, []byte{1}, ErrTypeMismatch},
+		{`{"type": "enum", "name": "E", "symbols": ["A"]}`, "C", ErrTypeMismatch},
+		{`{"type": "array", "items": "int"}`, []string{"x"}, ErrTypeMismatch},
+		{`{"type": "map", "values": "int"}`, map[int]int{}, ErrTypeMismatch},
+		{`["null", "int"]`, "x", ErrTypeMismatch},
+		{`["null", "int"]`, Branch{2, nil}, ErrTypeMismatch},
+		{`{"type": "record", "name": "R", "fields": [{"name": "a", "type": "int"}]}`, map[string]interface{}{}, ErrMissingField},
+	} {
+		err := NewEncoder(binary.NewWriter(&bytes.Buffer{}), MustParseSchema(c.schema)).Encode(c.value)
+		utest.EqualNow(t, err, c.err)
+	}
+}
@@ -0,0 +1,164 @@
+package avro
+
+import (
+	"bytes"
+	"compress/flate"
+	"io"
+	"io/ioutil"
+
+	"github.com/funny/binary"
+)
+
+// SyncLen is the length of the sync marker between container file blocks.
+const SyncLen = 16
+
+var fileMagic = []byte{'O', 'b', 'j', 1}
+
+var metaSchema = &Schema{Type: Map, Values: &Schema{Type: Bytes}}
+
+// FileReader reads the objects of an Avro object container file. The
+// "null" and "deflate" codecs are supported.
+type FileReader struct {
+	r binary.BinaryReader
+
+	Schema *Schema
+	Meta   map[string][]byte
+	Codec  string
+	Sync   [SyncLen]byte
+
+	// MaxLen limits the size of a block, compressed and not, and is passed
+	// on to the Decoder of each block. Zero means DefaultMaxLen.
+	MaxLen int
+
+	block *Decoder
+	data  *bytes.Reader
+	count int64
+	err   error
+}
+
+// NewFileReader reads the file header and parses the writer schema.
+func NewFileReader(r binary.BinaryReader) (*FileReader, error) {
+	// Reading past the data of a Buffer panics instead of giving io.EOF.
+	if buf, ok := r.(*binary.Buffer); ok {
+		r = binary.NewReader(bufferSource{buf})
+	}
+	if !bytes.Equal(r.ReadBytes(len(fileMagic)), fileMagic) {
+		if err := r.Error(); err != nil {
+			return nil, err
+		}
+		return nil, ErrNotContainer
+	}
+	meta, err := NewDecoder(r, metaSchema).Decode()
+	if err != nil {
+		return nil, err
+	}
+	f := &FileReader{r: r, Meta: make(map[string][]byte), Codec: "null"}
+	for k, v := range meta.(map[string]interface{}) {
+		f.Meta[k] = v.([]byte)
+	}
+	copy(f.Sync[:], r.ReadBytes(SyncLen))
+	if err := r.Error(); err != nil {
+		return nil, err
+	}
+	if codec, ok := f.Meta["avro.codec"]; ok && len(codec) > 0 {
+		f.Codec = string(codec)
+	}
+	if f.Codec != "null" && f.Codec != "deflate" {
+		return nil, ErrUnsupportedCodec
+	}
+	if f.Schema, err = ParseSchema(f.Meta["avro.schema"]); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+func (f *FileReader) maxLen() int {
+	if f.MaxLen > 0 {
+		return f.MaxLen
+	}
+	return DefaultMaxLen
+}
+
+// Next returns the next object, or io.EOF after the last block. A block
+// whose objects do not use up exactly its bytes gives ErrInvalidData or
+// io.ErrUnexpectedEOF.
+func (f *FileReader) Next() (interface{}, error) {
+	for f.err == nil && f.count == 0 {
+		f.err = f.readBlock()
+	}
+	if f.err != nil {
+		return nil, f.err
+	}
+	f.count--
+	v, err := f.block.Decode()
+	if err != nil {
+		// The end of a block is not the end of the file.
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		f.err = err
+		return v, err
+	}
+	if f.count == 0 && f.data.Len() != 0 {
+		f.err = ErrInvalidData
+		return nil, f.err
+	}
+	return v, nil
+}
+
+func (f *FileReader) readBlock() error {
+	count := f.r.ReadVarint()
+	size := f.r.ReadVarint()
+	if err := f.r.Error(); err != nil {
+		return err
+	}
+	if count < 0 || size < 0 {
+		return ErrInvalidData
+	}
+	if size > int64(f.maxLen()) {
+		return ErrTooLarge
+	}
+	data := f.r.ReadBytes(int(size))
+	sync := f.r.ReadBytes(SyncLen)
+	if err := f.r.Error(); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return err
+	}
+	if !bytes.Equal(sync, f.Sync[:]) {
+		return ErrBadSync
+	}
+	if f.Codec == "deflate" {
+		var err error
+		zr := flate.NewReader(bytes.NewReader(data))
+		data, err = ioutil.ReadAll(io.LimitReader(zr, int64(f.maxLen())+1))
+		if err != nil {
+			return err
+		}
+		if len(data) > f.maxLen() {
+			return ErrTooLarge
+		}
+	}
+	if count == 0 && len(data) != 0 {
+		return ErrInvalidData
+	}
+	f.data = bytes.NewReader(data)
+	f.block = NewDecoder(binary.NewReader(f.data), f.Schema)
+	f.block.MaxLen = f.MaxLen
+	f.count = count
+	return nil
+}
+
+// bufferSource reports the end of a Buffer as io.EOF.
+type bufferSource struct {
+	buf *binary.Buffer
+}
+
+func (s bufferSource) Read(b []byte) (int, error) {
+	n, err := s.buf.Read(b)
+	if n == 0 && err == nil && len(b) > 0 {
+		err = io.EOF
+	}
+	return n, err
+}
@@ -0,0 +1,138 @@
+package avro
+
+import (
+	"bytes"
+	"compress/flate"
+	"io"
+	"testing"
+
+	"github.com/funny/binary"
+	"github.com/funny/utest"
+)
+
+const fileSchema = `{"type": "record", "name": "P", "fields": [{"name": "n", "type": "long"}, {"name": "s", "type": "string"}]}`
+
+var testSync = []byte("0123456789abcdef")
+
+func writeFile(t *testing.T, codec string, blocks ...[]interface{}) []byte {
+	var buf bytes.Buffer
+	w := binary.NewWriter(&buf)
+	w.WriteBytes(fileMagic)
+	meta := map[string][]byte{"avro.schema": []byte(fileSchema), "avro.codec": []byte(codec)}
+	utest.IsNilNow(t, NewEncoder(w, metaSchema).Encode(meta))
+	w.WriteBytes(testSync)
+
+	s := MustParseSchema(fileSchema)
+	for _, block := range blocks {
+		var data bytes.Buffer
+		enc := NewEncoder(binary.NewWriter(&data), s)
+		for _, v := range block {
+			utest.IsNilNow(t, enc.Encode(v))
+		}
+		if codec == "deflate" {
+			var z bytes.Buffer
+			zw, _ := flate.NewWriter(&z, flate.BestCompression)
+			zw.Write(data.Bytes())
+			zw.Close()
+			data = z
+		}
+		w.WriteVarint(int64(len(block)))
+		w.WriteVarint(int64(data.Len()))
+		w.WriteBytes(data.Bytes())
+		w.WriteBytes(testSync)
+	}
+	utest.IsNilNow(t, w.Error())
+	return buf.Bytes()
+}
+
+func readFile(b []byte) ([]interface{}, error) {
+	f, err := NewFileReader(binary.NewReader(bytes.NewReader(b)))
+	if err != nil {
+		return nil, err
+	}
+	var all []interface{}
+	for {
+		v, err := f.Next()
+		if err == io.EOF {
+			return all, nil
+		}
+		if err != nil {
+			return all, err
+		}
+		all = append(all, v)
+	}
+}
+
+func Test_File_Read(t *testing.T) {
+	p1 := map[string]interface{}{"n": int64(1), "s": "one"}
+	p2 := map[string]interface{}{"n": int64(2), "s": "two"}
+	p3 := map[string]interface{}{"n": int64(3), "s": "three"}
+	for _, codec := range []string{"null", "deflate", ""} {
+		all, err := readFile(writeFile(t, codec, []interface{}{p1, p2}, []interface{}{}, []interface{}{p3}))
+		utest.IsNilNow(t, err)
+		utest.EqualNow(t, all, []interface{}{p1, p2, p3})
+	}
+
+	f, err := NewFileReader(binary.NewReader(bytes.NewReader(writeFile(t, "null"))))
+	utest.IsNilNow(t, err)
+	utest.EqualNow(t, f.Codec, "null")
+	utest.EqualNow(t, f.Schema.Name, "P")
+	utest.EqualNow(t, f.Sync[:], testSync)
+	utest.EqualNow(t, string(f.Meta["avro.schema"]), fileSchema)
+	_, err = f.Next()
+	utest.EqualNow(t, err, io.EOF)
+}
+
+func Test_File_Buffer(t *testing.T) {
+	p := map[string]interface{}{"n": int64(1), "s": "one"}
+	b := writeFile(t, "null", []interface{}{p})
+	f, err := NewFileReader(&binary.Buffer{Data: b})
+	utest.IsNilNow(t, err)
+	v, err := f.Next()
+	utest.IsNilNow(t, err)
+	utest.EqualNow(t, v, p)
+	_, err = f.Next()
+	utest.EqualNow(t, err, io.EOF)
+
+	f, err = NewFileReader(&binary.Buffer{Data: b[:len(b)-SyncLen-1]})
+	utest.IsNilNow(t, err)
+	_, err = f.Next()
+	utest.EqualNow(t, err, io.ErrUnexpectedEOF)
+}
+
+func Test_File_Errors(t *testing.T) {
+	_, err := readFile([]byte("Obj\x02"))
+	utest.EqualNow(t, err, ErrNotContainer)
+	_, err = readFile(writeFile(t, "snappy"))
+	utest.EqualNow(t, err, ErrUnsupportedCodec)
+
+	p := map[string]interface{}{"n": int64(1), "s": "x"}
+	b := writeFile(t, "null", []interface{}{p})
+	b[len(b)-1] ^= 1
+	all, err := readFile(b)
+	utest.EqualNow(t, err, ErrBadSync)
+	utest.EqualNow(t, len(all), 0)
+
+	b = writeFile(t, "null", []interface{}{p})
+	_, err = readFile(b[:len(b)-4])
+	utest.EqualNow(t, err, io.ErrUnexpectedEOF)
+
+	// A block count beyond the data of the block.
+	b = writeFile(t, "null", []interface{}{p})
+	b[len(b)-SyncLen-5] = 0x04
+	all, err = readFile(b)
+	utest.EqualNow(t, len(all), 1)
+	utest.EqualNow(t, err, io.ErrUnexpectedEOF)
+
+	// Bytes left in a block after its last object.
+	b = writeFile(t, "null", []interface{}{p, p})
+	b[len(b)-SyncLen-8] = 0x02
+	all, err = readFile(b)
+	utest.EqualNow(t, len(all), 0)
+	utest.EqualNow(t, err, ErrInvalidData)
+
+	b = writeFile(t, "null", []interface{}{p})
+	b[len(b)-SyncLen-5] = 0x00
+	_, err = readFile(b)
+	utest.EqualNow(t, err, ErrInvalidData)
+}
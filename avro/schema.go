@@ -0,0 +1,347 @@
+package avro
+
+import (
+	"encoding/json"
+	"math"
+	"strings"
+)
+
+// Schema is a parsed Avro schema. Named types are shared, so a recursive
+// record refers back to itself through its fields.
+type Schema struct {
+	Type Type
+
+	// Name is the full name of a record, enum or fixed.
+	Name string
+
+	// LogicalType is kept for the application, values are not converted.
+	LogicalType string
+
+	Fields   []*Field  // record
+	Symbols  []string  // enum
+	Items    *Schema   // array
+	Values   *Schema   // map
+	Branches []*Schema // union
+	Size     int       // fixed
+
+	symbols map[string]int
+}
+
+type Field struct {
+	Name string
+	Type *Schema
+
+	// Default is the default value converted to the representation of
+	// Type. The Encoder uses it when a record value lacks the field, the
+	// Decoder reads with the writer schema and never needs it.
+	Default    interface{}
+	HasDefault bool
+}
+
+// ParseSchema parses a schema in its JSON form.
+func ParseSchema(data []byte) (*Schema, error) {
+	var v interface{}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return nil, err
+	}
+	p := &schemaParser{names: make(map[string]*Schema)}
+	return p.parse(v, "")
+}
+
+// MustParseSchema is like ParseSchema but panics on error.
+func MustParseSchema(data string) *Schema {
+	s, err := ParseSchema([]byte(data))
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+func isPrimitive(t Type) bool {
+	switch t {
+	case Null, Boolean, Int, Long, Float, Double, Bytes, String:
+		return true
+	}
+	return false
+}
+
+func fullName(name, namespace string) string {
+	if namespace == "" || strings.Contains(name, ".") {
+		return name
+	}
+	return namespace + "." + name
+}
+
+type schemaParser struct {
+	names map[string]*Schema
+}
+
+func (p *schemaParser) parse(v interface{}, namespace string) (*Schema, error) {
+	switch v := v.(type) {
+	case string:
+		return p.reference(v, namespace)
+	case []interface{}:
+		return p.parseUnion(v, namespace)
+	case map[string]interface{}:
+		return p.parseObject(v, namespace)
+	}
+	return nil, ErrInvalidSchema
+}
+
+func (p *schemaParser) reference(name, namespace string) (*Schema, error) {
+	if isPrimitive(Type(name)) {
+		return &Schema{Type: Type(name)}, nil
+	}
+	if s, ok := p.names[fullName(name, namespace)]; ok {
+		return s, nil
+	}
+	if s, ok := p.names[name]; ok {
+		return s, nil
+	}
+	return nil, ErrUnknownType
+}
+
+func (p *schemaParser) parseUnion(v []interface{}, namespace string) (*Schema, error) {
+	s := &Schema{Type: Union, Branches: make([]*Schema, len(v))}
+	seen := make(map[string]bool)
+	for i, item := range v {
+		b, err := p.parse(item, namespace)
+		if err != nil {
+			return nil, err
+		}
+		if b.Type == Union {
+			return nil, ErrInvalidSchema
+		}
+		key := string(b.Type)
+		if b.Name != "" {
+			key = b.Name
+		}
+		if seen[key] {
+			return nil, ErrInvalidSchema
+		}
+		seen[key] = true
+		s.Branches[i] = b
+	}
+	return s, nil
+}
+
+func (p *schemaParser) parseObject(v map[string]interface{}, namespace string) (*Schema, error) {
+	name, ok := v["type"].(string)
+	if !ok {
+		// {"type": {...}} and {"type": [...]} wrap another schema.
+		return p.parse(v["type"], namespace)
+	}
+	logical, _ := v["logicalType"].(string)
+	switch t := Type(name); {
+	case isPrimitive(t):
+		return &Schema{Type: t, LogicalType: logical}, nil
+	case t == Record || name == "error":
+		return p.parseRecord(v, namespace)
+	case t == Enum:
+		return p.parseEnum(v, namespace)
+	case t == Fixed:
+		s, _, err := p.define(v, Fixed, namespace)
+		if err != nil {
+			return nil, err
+		}
+		size, ok := v["size"].(float64)
+		if !ok || size < 0 || size > math.MaxInt32 || size != math.Trunc(size) {
+			return nil, ErrInvalidSchema
+		}
+		s.Size = int(size)
+		s.LogicalType = logical
+		return s, nil
+	case t == Array:
+		items, err := p.parse(v["items"], namespace)
+		if err != nil {
+			return nil, err
+		}
+		return &Schema{Type: Array, Items: items, LogicalType: logical}, nil
+	case t == Map:
+		values, err := p.parse(v["values"], namespace)
+		if err != nil {
+			return nil, err
+		}
+		return &Schema{Type: Map, Values: values, LogicalType: logical}, nil
+	}
+	return p.reference(name, namespace)
+}
+
+// define registers a named type before its body is parsed so the body can
+// refer to it, and returns the namespace for the body.
+func (p *schemaParser) define(v map[string]interface{}, t Type, namespace string) (*Schema, string, error) {
+	name, _ := v["name"].(string)
+	if name == "" || isPrimitive(Type(name)) {
+		return nil, "", ErrInvalidSchema
+	}
+	if ns, ok := v["namespace"].(string); ok {
+		namespace = ns
+	}
+	name = fullName(name, namespace)
+	if _, dup := p.names[name]; dup {
+		return nil, "", ErrInvalidSchema
+	}
+	s := &Schema{Type: t, Name: name}
+	p.names[name] = s
+	if i := strings.LastIndexByte(name, '.'); i >= 0 {
+		return s, name[:i], nil
+	}
+	return s, "", nil
+}
+
+func (p *schemaParser) parseRecord(v map[string]interface{}, namespace string) (*Schema, error) {
+	s, namespace, err := p.define(v, Record, namespace)
+	if err != nil {
+		return nil, err
+	}
+	fields, ok := v["fields"].([]interface{})
+	if !ok {
+		return nil, ErrInvalidSchema
+	}
+	seen := make(map[string]bool)
+	for _, item := range fields {
+		fv, ok := item.(map[string]interface{})
+		if !ok {
+			return nil, ErrInvalidSchema
+		}
+		f := &Field{}
+		f.Name, _ = fv["name"].(string)
+		if f.Name == "" || seen[f.Name] {
+			return nil, ErrInvalidSchema
+		}
+		seen[f.Name] = true
+		if f.Type, err = p.parse(fv["type"], namespace); err != nil {
+			return nil, err
+		}
+		if def, ok := fv["default"]; ok {
+			if f.Default, err = convertDefault(f.Type, def); err != nil {
+				return nil, err
+			}
+			f.HasDefault = true
+		}
+		s.Fields = append(s.Fields, f)
+	}
+	return s, nil
+}
+
+func (p *schemaParser) parseEnum(v map[string]interface{}, namespace string) (*Schema, error) {
+	s, _, err := p.define(v, Enum, namespace)
+	if err != nil {
+		return nil, err
+	}
+	symbols, ok := v["symbols"].([]interface{})
+	if !ok {
+		return nil, ErrInvalidSchema
+	}
+	s.symbols = make(map[string]int, len(symbols))
+	for i, item := range symbols {
+		sym, ok := item.(string)
+		if _, dup := s.symbols[sym]; !ok || sym == "" || dup {
+			return nil, ErrInvalidSchema
+		}
+		s.symbols[sym] = i
+		s.Symbols = append(s.Symbols, sym)
+	}
+	return s, nil
+}
+
+// convertDefault turns the JSON form of a default value into the value
+// representation of s. A union default belongs to its first branch.
+func convertDefault(s *Schema, v interface{}) (interface{}, error) {
+	switch s.Type {
+	case Null:
+		if v == nil {
+			return nil, nil
+		}
+	case Boolean:
+		if b, ok := v.(bool); ok {
+			return b, nil
+		}
+	case Int:
+		if f, ok := v.(float64); ok && f == math.Trunc(f) && f >= math.MinInt32 && f <= math.MaxInt32 {
+			return int32(f), nil
+		}
+	case Long:
+		if f, ok := v.(float64); ok && f == math.Trunc(f) && f >= math.MinInt64 && f < math.MaxInt64 {
+			return int64(f), nil
+		}
+	case Float:
+		if f, ok := v.(float64); ok {
+			return float32(f), nil
+		}
+	case Double:
+		if f, ok := v.(float64); ok {
+			return f, nil
+		}
+	case Bytes, Fixed:
+		// Bytes are written as a string of code points 0 to 255.
+		if str, ok := v.(string); ok {
+			b := make([]byte, 0, len(str))
+			for _, r := range str {
+				if r > 0xff {
+					return nil, ErrInvalidSchema
+				}
+				b = append(b, byte(r))
+			}
+			if s.Type == Bytes || len(b) == s.Size {
+				return b, nil
+			}
+		}
+	case String:
+		if str, ok := v.(string); ok {
+			return str, nil
+		}
+	case Enum:
+		if str, ok := v.(string); ok {
+			if _, ok := s.symbols[str]; ok {
+				return str, nil
+			}
+		}
+	case Array:
+		if items, ok := v.([]interface{}); ok {
+			a := make([]interface{}, len(items))
+			for i, item := range items {
+				var err error
+				if a[i], err = convertDefault(s.Items, item); err != nil {
+					return nil, err
+				}
+			}
+			return a, nil
+		}
+	case Map:
+		if values, ok := v.(map[string]interface{}); ok {
+			m := make(map[string]interface{}, len(values))
+			for k, item := range values {
+				var err error
+				if m[k], err = convertDefault(s.Values, item); err != nil {
+					return nil, err
+				}
+			}
+			return m, nil
+		}
+	case Record:
+		if values, ok := v.(map[string]interface{}); ok {
+			m := make(map[string]interface{}, len(s.Fields))
+			for _, f := range s.Fields {
+				item, ok := values[f.Name]
+				if !ok {
+					if !f.HasDefault {
+						return nil, ErrInvalidSchema
+					}
+					m[f.Name] = f.Default
+					continue
+				}
+				var err error
+				if m[f.Name], err = convertDefault(f.Type, item); err != nil {
+					return nil, err
+				}
+			}
+			return m, nil
+		}
+	case Union:
+		if len(s.Branches) > 0 {
+			return convertDefault(s.Branches[0], v)
+		}
+	}
+	return nil, ErrInvalidSchema
+}
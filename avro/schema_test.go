@@ -0,0 +1,69 @@
+package avro
+
+import (
+	"testing"
+
+	"github.com/funny/utest"
+)
+
+func Test_Schema_Parse(t *testing.T) {
+	s, err := ParseSchema([]byte(`{
+		"type": "record", "name": "LinkedList", "namespace": "org.example",
+		"fields": [
+			{"name": "value", "type": {"type": "int", "logicalType": "date"}},
+			{"name": "next", "type": ["null", "LinkedList"], "default": null},
+			{"name": "kind", "type": {"type": "enum", "name": "Kind", "symbols": ["A", "B"]}, "default": "B"},
+			{"name": "id", "type": {"type": "fixed", "name": "other.Id", "size": 2}, "default": "ÿ\u0001"},
+			{"name": "ids", "type": {"type": "array", "items": "other.Id"}, "default": []},
+			{"name": "tags", "type": {"type": "map", "values": "org.example.Kind"}, "default": {"x": "A"}},
+			{"name": "n", "type": "long", "default": 42}
+		]
+	}`))
+	utest.IsNilNow(t, err)
+	utest.EqualNow(t, s.Type, Record)
+	utest.EqualNow(t, s.Name, "org.example.LinkedList")
+	utest.EqualNow(t, len(s.Fields), 7)
+	utest.EqualNow(t, s.Fields[0].Type.LogicalType, "date")
+	utest.EqualNow(t, s.Fields[1].Type.Branches[1] == s, true)
+	utest.EqualNow(t, s.Fields[1].HasDefault, true)
+	utest.IsNilNow(t, s.Fields[1].Default)
+	utest.EqualNow(t, s.Fields[2].Type.Name, "org.example.Kind")
+	utest.EqualNow(t, s.Fields[2].Default, "B")
+	utest.EqualNow(t, s.Fields[3].Type.Name, "other.Id")
+	utest.EqualNow(t, s.Fields[3].Default, []byte{0xff, 1})
+	utest.EqualNow(t, s.Fields[4].Type.Items == s.Fields[3].Type, true)
+	utest.EqualNow(t, s.Fields[5].Default, map[string]interface{}{"x": "A"})
+	utest.EqualNow(t, s.Fields[6].Default, int64(42))
+	utest.EqualNow(t, s.Fields[0].HasDefault, false)
+
+	s, err = ParseSchema([]byte(`"string"`))
+	utest.IsNilNow(t, err)
+	utest.EqualNow(t, s.Type, String)
+}
+
+func Test_Schema_Errors(t *testing.T) {
+	for _, c := range []struct {
+		schema string
+		err    error
+	}{
+		{`"Nope"`, ErrUnknownType},
+		{`{"type": "array", "items": "Nope"}`, ErrUnknownType},
+		{`42`, ErrInvalidSchema},
+		{`["null", ["int"]]`, ErrInvalidSchema},
+		{`["int", "int"]`, ErrInvalidSchema},
+		{`{"type": "fixed", "name": "F", "size": -1}`, ErrInvalidSchema},
+		{`{"type": "fixed", "name": "F", "size": 1.5}`, ErrInvalidSchema},
+		{`{"type": "fixed", "size": 1}`, ErrInvalidSchema},
+		{`{"type": "enum", "name": "E", "symbols": ["A", "A"]}`, ErrInvalidSchema},
+		{`{"type": "record", "name": "R"}`, ErrInvalidSchema},
+		{`{"type": "record", "name": "R", "fields": [{"name": "a", "type": "int"}, {"name": "a", "type": "int"}]}`, ErrInvalidSchema},
+		{`{"type": "record", "name": "R", "fields": [{"name": "a", "type": "R"}, {"name": "b", "type": {"type": "fixed", "name": "R", "size": 1}}]}`, ErrInvalidSchema},
+		{`{"type": "record", "name": "R", "fields": [{"name": "a", "type": "int", "default": 1.5}]}`, ErrInvalidSchema},
+		{`{"type": "record", "name": "R", "fields": [{"name": "a", "type": ["int", "null"], "default": null}]}`, ErrInvalidSchema},
+	} {
+		_, err := ParseSchema([]byte(c.schema))
+		utest.EqualNow(t, err, c.err)
+	}
+	_, err := ParseSchema([]byte(`{`))
+	utest.NotNilNow(t, err)
+}
@@ -0,0 +1,159 @@
+package binary
+
+import (
+	"errors"
+	"math"
+)
+
+var (
+	ErrBCDInvalid  = errors.New("funny/binary: invalid BCD nibble")
+	ErrBCDOverflow = errors.New("funny/binary: BCD value does not fit")
+)
+
+// Packed BCD stores two decimal digits per byte, high nibble first. An odd
+// digit count is padded with a leading zero nibble.
+
+func BCDSize(nDigits int) int {
+	return (nDigits + 1) / 2
+}
+
+func nibble(b []byte, i int) byte {
+	if i%2 == 0 {
+		return b[i/2] >> 4
+	}
+	return b[i/2] & 0x0F
+}
+
+func setNibble(b []byte, i int, d byte) {
+	if i%2 == 0 {
+		b[i/2] = b[i/2]&0x0F | d<<4
+	} else {
+		b[i/2] = b[i/2]&0xF0 | d
+	}
+}
+
+// getDigits accumulates the digit nibbles from first to end, the nibbles
+// before first must be zero padding.
+func getDigits(b []byte, first, end int) (v uint64, err error) {
+	for i := 0; i < first; i++ {
+		if nibble(b, i) != 0 {
+			return 0, ErrBCDInvalid
+		}
+	}
+	for i := first; i < end; i++ {
+		d := nibble(b, i)
+		if d > 9 {
+			return 0, ErrBCDInvalid
+		}
+		if v > (math.MaxUint64-uint64(d))/10 {
+			err = ErrBCDOverflow
+		}
+		v = v*10 + uint64(d)
+	}
+	return
+}
+
+func putDigits(b []byte, first, end int, v uint64) error {
+	for i := 0; i < first; i++ {
+		setNibble(b, i, 0)
+	}
+	for i := end - 1; i >= first; i-- {
+		setNibble(b, i, byte(v%10))
+		v /= 10
+	}
+	if v != 0 {
+		return ErrBCDOverflow
+	}
+	return nil
+}
+
+func GetBCD(b []byte, nDigits int) (uint64, error) {
+	n := BCDSize(nDigits) * 2
+	return getDigits(b, n-nDigits, n)
+}
+
+func PutBCD(b []byte, nDigits int, v uint64) error {
+	n := BCDSize(nDigits) * 2
+	return putDigits(b, n-nDigits, n, v)
+}
+
+// GetBCDString decodes any number of digits, like phone numbers that do
+// not fit in an integer.
+func GetBCDString(b []byte, nDigits int) (string, error) {
+	n := BCDSize(nDigits) * 2
+	if n != nDigits && nibble(b, 0) != 0 {
+		return "", ErrBCDInvalid
+	}
+	s := make([]byte, nDigits)
+	for i := range s {
+		d := nibble(b, n-nDigits+i)
+		if d > 9 {
+			return "", ErrBCDInvalid
+		}
+		s[i] = '0' + d
+	}
+	return string(s), nil
+}
+
+func PutBCDString(b []byte, digits string) error {
+	n := BCDSize(len(digits)) * 2
+	if n != len(digits) {
+		setNibble(b, 0, 0)
+	}
+	for i := 0; i < len(digits); i++ {
+		d := digits[i] - '0'
+		if d > 9 {
+			return ErrBCDInvalid
+		}
+		setNibble(b, n-len(digits)+i, d)
+	}
+	return nil
+}
+
+// Packed decimal, COBOL COMP-3, adds a sign nibble after the digits: 0xC
+// or 0xF for positive and 0xD for negative, 0xA, 0xE and 0xB are accepted
+// when reading.
+
+func PackedDecimalSize(nDigits int) int {
+	return nDigits/2 + 1
+}
+
+func GetPackedDecimal(b []byte, nDigits int) (int64, error) {
+	n := PackedDecimalSize(nDigits) * 2
+	v, err := getDigits(b, n-1-nDigits, n-1)
+	if err == ErrBCDInvalid {
+		return 0, err
+	}
+	var neg bool
+	switch nibble(b, n-1) {
+	case 0xA, 0xC, 0xE, 0xF:
+	case 0xB, 0xD:
+		neg = true
+	default:
+		return 0, ErrBCDInvalid
+	}
+	if err != nil || v > math.MaxInt64+uint64(btoi(neg)) {
+		return 0, ErrBCDOverflow
+	}
+	if neg {
+		return -int64(v), nil
+	}
+	return int64(v), nil
+}
+
+func PutPackedDecimal(b []byte, nDigits int, v int64) error {
+	n := PackedDecimalSize(nDigits) * 2
+	sign, mag := byte(0xC), uint64(v)
+	if v < 0 {
+		sign, mag = 0xD, -uint64(v)
+	}
+	setNibble(b, n-1, sign)
+	return putDigits(b, n-1-nDigits, n-1, mag)
+}
+
+func btoi(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
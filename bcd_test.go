@@ -0,0 +1,164 @@
+package binary
+
+import (
+	"bytes"
+	"errors"
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/funny/utest"
+)
+
+func Test_BCD_Vectors(t *testing.T) {
+	b := make([]byte, 4)
+	utest.IsNilNow(t, PutBCD(b, 8, 12345678))
+	utest.EqualNow(t, b, []byte{0x12, 0x34, 0x56, 0x78})
+	v, err := GetBCD(b, 8)
+	utest.IsNilNow(t, err)
+	utest.EqualNow(t, v, 12345678)
+
+	utest.IsNilNow(t, PutBCD(b[:3], 5, 12345))
+	utest.EqualNow(t, b[:3], []byte{0x01, 0x23, 0x45})
+	v, err = GetBCD(b[:3], 5)
+	utest.IsNilNow(t, err)
+	utest.EqualNow(t, v, 12345)
+
+	utest.EqualNow(t, PutBCD(b[:2], 4, 12345), ErrBCDOverflow)
+	_, err = GetBCD([]byte{0x1A}, 2)
+	utest.EqualNow(t, err, ErrBCDInvalid)
+	_, err = GetBCD([]byte{0x11}, 1)
+	utest.EqualNow(t, err, ErrBCDInvalid)
+
+	twenty := []byte{0x18, 0x44, 0x67, 0x44, 0x07, 0x37, 0x09, 0x55, 0x16, 0x15}
+	v, err = GetBCD(twenty, 20)
+	utest.IsNilNow(t, err)
+	utest.EqualNow(t, v, uint64(math.MaxUint64))
+	twenty[9] = 0x16
+	_, err = GetBCD(twenty, 20)
+	utest.EqualNow(t, err, ErrBCDOverflow)
+}
+
+func Test_BCD_String(t *testing.T) {
+	b := make([]byte, 13)
+	utest.IsNilNow(t, PutBCDString(b, "8613912345678901234567890"))
+	s, err := GetBCDString(b, 25)
+	utest.IsNilNow(t, err)
+	utest.EqualNow(t, s, "8613912345678901234567890")
+	utest.EqualNow(t, b[0], 0x08)
+	utest.EqualNow(t, PutBCDString(b, "12a4"), ErrBCDInvalid)
+	_, err = GetBCDString([]byte{0x1F}, 2)
+	utest.EqualNow(t, err, ErrBCDInvalid)
+}
+
+func Test_PackedDecimal_Vectors(t *testing.T) {
+	b := make([]byte, 3)
+	utest.IsNilNow(t, PutPackedDecimal(b, 5, 12345))
+	utest.EqualNow(t, b, []byte{0x12, 0x34, 0x5C})
+	utest.IsNilNow(t, PutPackedDecimal(b, 5, -12345))
+	utest.EqualNow(t, b, []byte{0x12, 0x34, 0x5D})
+	utest.IsNilNow(t, PutPackedDecimal(b, 4, -1234))
+	utest.EqualNow(t, b, []byte{0x01, 0x23, 0x4D})
+
+	for _, c := range []struct {
+		b []byte
+		v int64
+	}{
+		{[]byte{0x12, 0x34, 0x5C}, 12345},
+		{[]byte{0x12, 0x34, 0x5F}, 12345},
+		{[]byte{0x12, 0x34, 0x5D}, -12345},
+		{[]byte{0x12, 0x34, 0x5B}, -12345},
+	} {
+		v, err := GetPackedDecimal(c.b, 5)
+		utest.IsNilNow(t, err)
+		utest.EqualNow(t, v, c.v)
+	}
+
+	_, err := GetPackedDecimal([]byte{0x12, 0x34, 0x59}, 5)
+	utest.EqualNow(t, err, ErrBCDInvalid)
+	_, err = GetPackedDecimal([]byte{0x1A, 0x34, 0x5C}, 5)
+	utest.EqualNow(t, err, ErrBCDInvalid)
+	utest.EqualNow(t, PutPackedDecimal(b, 5, 123456), ErrBCDOverflow)
+
+	b = make([]byte, PackedDecimalSize(19))
+	utest.IsNilNow(t, PutPackedDecimal(b, 19, math.MinInt64))
+	v, err := GetPackedDecimal(b, 19)
+	utest.IsNilNow(t, err)
+	utest.EqualNow(t, v, int64(math.MinInt64))
+	b[len(b)-1] = 0x8C
+	_, err = GetPackedDecimal(b, 19)
+	utest.EqualNow(t, err, ErrBCDOverflow)
+}
+
+func Test_BCD_ReadWrite(t *testing.T) {
+	ReadWriteTest(t, 10000, func(r *Reader, w *Writer) {
+		n1 := rand.Intn(19) + 1
+		v1 := uint64(rand.Int63()) % uint64(math.Pow10(n1))
+		n2 := rand.Intn(18) + 1
+		v2 := rand.Int63() % int64(math.Pow10(n2))
+		if rand.Intn(2) == 0 {
+			v2 = -v2
+		}
+
+		w.WriteBCD(n1, v1)
+		w.WritePackedDecimal(n2, v2)
+		utest.IsNilNow(t, w.Error())
+
+		utest.EqualNow(t, r.ReadBCD(n1), v1)
+		utest.EqualNow(t, r.ReadPackedDecimal(n2), v2)
+		utest.IsNilNow(t, r.Error())
+	})
+}
+
+func Test_BCD_Errors(t *testing.T) {
+	var buf = Buffer{Data: []byte{0x12, 0xA4}}
+	func() {
+		defer func() {
+			utest.EqualNow(t, recover(), ErrBCDInvalid)
+		}()
+		buf.ReadBCD(4)
+	}()
+	utest.EqualNow(t, buf.ReadPos, 0)
+
+	ReadWriteTest(t, 1, func(r *Reader, w *Writer) {
+		w.WriteBCD(2, 123)
+		utest.EqualNow(t, w.Error(), ErrBCDOverflow)
+		w.WriteUint8(1)
+		utest.EqualNow(t, w.Error(), ErrBCDOverflow)
+		w.Reset(w.W)
+		w.WritePackedDecimal(1, -12)
+		w.WriteUint8(1)
+		utest.EqualNow(t, w.Error(), ErrBCDOverflow)
+
+		w.Reset(w.W)
+		w.WriteBytes([]byte{0x12, 0xA4})
+		r.ReadBCD(4)
+		utest.EqualNow(t, r.Error(), ErrBCDInvalid)
+	})
+
+	utest.EqualNow(t, BCDSize(7), 4)
+	utest.EqualNow(t, PackedDecimalSize(7), 4)
+}
+
+type failWriter struct{ err error }
+
+func (w *failWriter) Write(b []byte) (int, error) { return len(b), w.err }
+
+func Test_BCD_WriterError(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewWriter(&buf)
+	w.WriteBCD(2, 123)
+	w.WriteUint8(1)
+	utest.EqualNow(t, w.Error(), ErrBCDOverflow)
+	utest.EqualNow(t, buf.Len(), 0)
+
+	// I/O errors keep their baseline behaviour, the next write replaces
+	// them.
+	fw := &failWriter{errors.New("fail")}
+	w = NewWriter(fw)
+	w.WriteUint8(1)
+	utest.NotNilNow(t, w.Error())
+	fw.err = nil
+	w.WriteUint8(1)
+	utest.IsNilNow(t, w.Error())
+}
@@ -0,0 +1,121 @@
+package binary
+
+import (
+	"errors"
+	"math/big"
+)
+
+var (
+	ErrBigIntTooLarge = errors.New("funny/binary: big integer exceeds size limit")
+	ErrBigIntNegative = errors.New("funny/binary: negative big integer in unsigned encoding")
+)
+
+// DefaultBigIntMaxLen limits length prefixed big integers when
+// BigIntEncoding.MaxLen is zero, enough for 64K-bit values.
+const DefaultBigIntMaxLen = 8192
+
+// BigIntEncoding describes a big-endian big integer. Signed values are two's
+// complement, unsigned values are plain magnitudes. A zero Width means the
+// value is prefixed by its byte length as a big-endian uint32, otherwise it
+// takes exactly Width bytes, zero padded or sign extended.
+type BigIntEncoding struct {
+	Signed bool
+	Width  int
+	MaxLen int
+}
+
+var (
+	// SSHMpint is the mpint of RFC 4251. Zero has an empty encoding.
+	SSHMpint = BigIntEncoding{Signed: true}
+	// BigIntMagnitude is a length prefixed unsigned magnitude.
+	BigIntMagnitude = BigIntEncoding{}
+)
+
+func (enc BigIntEncoding) maxLen() int {
+	if enc.MaxLen > 0 {
+		return enc.MaxLen
+	}
+	return DefaultBigIntMaxLen
+}
+
+// BigIntSize returns the minimal number of bytes holding v. A signed zero
+// takes no bytes, a positive value with its top bit set gets a leading zero.
+func BigIntSize(v *big.Int, signed bool) int {
+	switch {
+	case !signed || v.Sign() == 0:
+		return (v.BitLen() + 7) / 8
+	case v.Sign() > 0:
+		return v.BitLen()/8 + 1
+	}
+	// -2^(8n-1) is the smallest value in n bytes, so look at -v-1.
+	m := new(big.Int).Not(v)
+	return m.BitLen()/8 + 1
+}
+
+func GetBigInt(b []byte, signed bool) *big.Int {
+	v := new(big.Int).SetBytes(b)
+	if signed && len(b) > 0 && b[0]&0x80 != 0 {
+		v.Sub(v, new(big.Int).Lsh(big.NewInt(1), uint(len(b))*8))
+	}
+	return v
+}
+
+// PutBigInt fills all of b with v, it returns ErrBigIntTooLarge when v
+// needs more than len(b) bytes.
+func PutBigInt(b []byte, v *big.Int, signed bool) error {
+	if !signed && v.Sign() < 0 {
+		return ErrBigIntNegative
+	}
+	if BigIntSize(v, signed) > len(b) {
+		return ErrBigIntTooLarge
+	}
+	if v.Sign() >= 0 {
+		v.FillBytes(b)
+		return nil
+	}
+	x := new(big.Int).Lsh(big.NewInt(1), uint(len(b))*8)
+	x.Add(x, v).FillBytes(b)
+	return nil
+}
+
+// ReadBigInt returns ErrBigIntTooLarge without consuming the payload when
+// a length prefix exceeds the limit, otherwise any error of r.
+func ReadBigInt(r BinaryReader, enc BigIntEncoding) (*big.Int, error) {
+	n := enc.Width
+	if n == 0 {
+		size := r.ReadUint32BE()
+		if err := r.Error(); err != nil {
+			return nil, err
+		}
+		if size > uint32(enc.maxLen()) {
+			return nil, ErrBigIntTooLarge
+		}
+		n = int(size)
+	}
+	b := r.ReadBytes(n)
+	if err := r.Error(); err != nil {
+		return nil, err
+	}
+	return GetBigInt(b, enc.Signed), nil
+}
+
+// WriteBigInt reports encoding errors before writing anything, write errors
+// are left in w.
+func WriteBigInt(w BinaryWriter, v *big.Int, enc BigIntEncoding) error {
+	n := enc.Width
+	if n == 0 {
+		n = BigIntSize(v, enc.Signed)
+		if n > enc.maxLen() {
+			return ErrBigIntTooLarge
+		}
+	}
+	b := make([]byte, n)
+	if err := PutBigInt(b, v, enc.Signed); err != nil {
+		return err
+	}
+	if enc.Width == 0 {
+		w.WriteUint32BE(uint32(n))
+	}
+	w.WriteBytes(b)
+	return nil
+}
@@ -0,0 +1,97 @@
+package binary
+
+import (
+	"math/big"
+	"math/rand"
+	"testing"
+
+	"github.com/funny/utest"
+)
+
+func Test_BigInt_Mpint(t *testing.T) {
+	// Examples from RFC 4251 section 5.
+	for _, c := range []struct {
+		v string
+		b []byte
+	}{
+		{"0", []byte{0, 0, 0, 0}},
+		{"9a378f9b2e332a7", []byte{0, 0, 0, 8, 0x09, 0xa3, 0x78, 0xf9, 0xb2, 0xe3, 0x32, 0xa7}},
+		{"80", []byte{0, 0, 0, 2, 0x00, 0x80}},
+		{"-1234", []byte{0, 0, 0, 2, 0xed, 0xcc}},
+		{"-deadbeef", []byte{0, 0, 0, 5, 0xff, 0x21, 0x52, 0x41, 0x11}},
+		{"-80", []byte{0, 0, 0, 1, 0x80}},
+		{"-81", []byte{0, 0, 0, 2, 0xff, 0x7f}},
+	} {
+		v, _ := new(big.Int).SetString(c.v, 16)
+		buf := Buffer{Data: make([]byte, 16)}
+		utest.IsNilNow(t, WriteBigInt(&buf, v, SSHMpint))
+		utest.EqualNow(t, buf.Data[:buf.WritePos], c.b)
+
+		v2, err := ReadBigInt(&buf, SSHMpint)
+		utest.IsNilNow(t, err)
+		utest.EqualNow(t, v2.Cmp(v), 0)
+	}
+}
+
+func Test_BigInt_Fixed(t *testing.T) {
+	b := make([]byte, 4)
+	utest.IsNilNow(t, PutBigInt(b, big.NewInt(0x80), false))
+	utest.EqualNow(t, b, []byte{0, 0, 0, 0x80})
+	utest.IsNilNow(t, PutBigInt(b, big.NewInt(-2), true))
+	utest.EqualNow(t, b, []byte{0xff, 0xff, 0xff, 0xfe})
+	utest.EqualNow(t, GetBigInt(b, true).Int64(), int64(-2))
+	utest.EqualNow(t, GetBigInt(b, false).Int64(), int64(0xfffffffe))
+
+	utest.EqualNow(t, PutBigInt(b, big.NewInt(1<<31), true), ErrBigIntTooLarge)
+	utest.EqualNow(t, PutBigInt(b, big.NewInt(-1<<31-1), true), ErrBigIntTooLarge)
+	utest.IsNilNow(t, PutBigInt(b, big.NewInt(-1<<31), true))
+	utest.EqualNow(t, PutBigInt(b, big.NewInt(1<<32), false), ErrBigIntTooLarge)
+	utest.EqualNow(t, PutBigInt(b, big.NewInt(-1), false), ErrBigIntNegative)
+}
+
+func Test_BigInt_Limit(t *testing.T) {
+	enc := BigIntEncoding{MaxLen: 4}
+	buf := Buffer{Data: make([]byte, 16)}
+	utest.EqualNow(t, WriteBigInt(&buf, big.NewInt(1<<32), enc), ErrBigIntTooLarge)
+	utest.EqualNow(t, buf.WritePos, 0)
+
+	buf.WriteUint32BE(5)
+	buf.WriteBytes([]byte{1, 2, 3, 4, 5})
+	_, err := ReadBigInt(&buf, enc)
+	utest.EqualNow(t, err, ErrBigIntTooLarge)
+	utest.EqualNow(t, buf.ReadPos, 4)
+
+	ReadWriteTest(t, 1, func(r *Reader, w *Writer) {
+		w.WriteUint32BE(DefaultBigIntMaxLen + 1)
+		_, err := ReadBigInt(r, SSHMpint)
+		utest.EqualNow(t, err, ErrBigIntTooLarge)
+
+		w.WriteUint32BE(8)
+		w.WriteBytes([]byte{1, 2, 3})
+		_, err = ReadBigInt(r, SSHMpint)
+		utest.NotNilNow(t, err)
+	})
+}
+
+func Test_BigInt_ReadWrite(t *testing.T) {
+	encs := []BigIntEncoding{
+		SSHMpint,
+		BigIntMagnitude,
+		{Signed: true, Width: 40},
+		{Width: 40},
+	}
+	ReadWriteTest(t, 10000, func(r *Reader, w *Writer) {
+		for _, enc := range encs {
+			v := new(big.Int).SetBytes(RandBytes(39))
+			if enc.Signed && rand.Intn(2) == 0 {
+				v.Neg(v)
+			}
+			utest.IsNilNow(t, WriteBigInt(w, v, enc))
+			utest.IsNilNow(t, w.Error())
+
+			v2, err := ReadBigInt(r, enc)
+			utest.IsNilNow(t, err)
+			utest.EqualNow(t, v2.Cmp(v), 0)
+		}
+	})
+}
@@ -224,9 +224,9 @@ func PutVarint(b []byte, v int64) int {
 }
 
 func ReadUvarint(r io.ByteReader) (uint64, error) {
-	return binary.ReadUvarint(r)
+	return ReadUvarintStrict(r, false)
 }
 
 func ReadVarint(r io.ByteReader) (int64, error) {
-	return binary.ReadVarint(r)
+	return ReadVarintStrict(r, false)
 }
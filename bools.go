@@ -0,0 +1,41 @@
+package binary
+
+// BitOrder selects which bit of a byte holds the first bool.
+type BitOrder int
+
+const (
+	MSBFirst BitOrder = iota
+	LSBFirst
+)
+
+// BoolsSize returns the bytes taken by n packed bools, the unused bits of
+// the last byte are zero.
+func BoolsSize(n int) int {
+	return (n + 7) / 8
+}
+
+func (order BitOrder) mask(i int) byte {
+	if order == LSBFirst {
+		return 1 << uint(i%8)
+	}
+	return 0x80 >> uint(i%8)
+}
+
+func GetBools(b []byte, n int, order BitOrder) []bool {
+	v := make([]bool, n)
+	for i := range v {
+		v[i] = b[i/8]&order.mask(i) != 0
+	}
+	return v
+}
+
+func PutBools(b []byte, v []bool, order BitOrder) {
+	for i := 0; i < BoolsSize(len(v)); i++ {
+		b[i] = 0
+	}
+	for i, x := range v {
+		if x {
+			b[i/8] |= order.mask(i)
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package binary
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/funny/utest"
+)
+
+func Test_Bools_GetPut(t *testing.T) {
+	v := []bool{true, false, true, true, false, false, false, false, false, true}
+	b := []byte{0xFF, 0xFF, 0xFF}
+	PutBools(b, v, MSBFirst)
+	utest.EqualNow(t, b, []byte{0xB0, 0x40, 0xFF})
+	utest.EqualNow(t, GetBools(b, len(v), MSBFirst), v)
+	PutBools(b, v, LSBFirst)
+	utest.EqualNow(t, b, []byte{0x0D, 0x02, 0xFF})
+	utest.EqualNow(t, GetBools(b, len(v), LSBFirst), v)
+	utest.EqualNow(t, BoolsSize(0), 0)
+	utest.EqualNow(t, BoolsSize(8), 1)
+	utest.EqualNow(t, BoolsSize(9), 2)
+}
+
+func Test_Bools_ReadWrite(t *testing.T) {
+	ReadWriteTest(t, 10000, func(r *Reader, w *Writer) {
+		v := make([]bool, rand.Intn(100))
+		for i := range v {
+			v[i] = rand.Intn(2) == 0
+		}
+		order := BitOrder(rand.Intn(2))
+
+		w.WriteBools(v, order)
+		w.WriteUint8(0x5A)
+		utest.IsNilNow(t, w.Error())
+
+		utest.EqualNow(t, r.ReadBools(len(v), order), v)
+		utest.EqualNow(t, r.ReadUint8(), uint8(0x5A))
+		utest.IsNilNow(t, r.Error())
+	})
+
+	var buf = Buffer{Data: make([]byte, 4)}
+	buf.WriteBools([]bool{true, true}, LSBFirst)
+	utest.EqualNow(t, buf.WritePos, 1)
+	utest.EqualNow(t, buf.ReadBools(2, LSBFirst), []bool{true, true})
+}
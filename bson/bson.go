@@ -0,0 +1,148 @@
+// Package bson implements BSON documents on top of the Buffer, BinaryReader
+// and BinaryWriter types of github.com/funny/binary.
+//
+// Documents decode into D, keeping the order of their elements, and arrays
+// into A. Encoding also takes maps with string keys, sorted by key, and
+// structs, where the "bson" struct tag renames a field, "-" skips it and the
+// "omitempty" option drops empty values like encoding/json does. Raw gives
+// access to the fields of an encoded document without decoding all of it.
+package bson
+
+import (
+	"encoding/hex"
+	"errors"
+	"time"
+)
+
+var (
+	ErrUnsupportedType = errors.New("funny/binary/bson: unsupported type")
+	ErrInvalidCString  = errors.New("funny/binary/bson: key or regex contains a NUL byte")
+	ErrInvalidDocument = errors.New("funny/binary/bson: malformed document")
+	ErrInvalidType     = errors.New("funny/binary/bson: unknown element type")
+	ErrOverflow        = errors.New("funny/binary/bson: integer overflows int64")
+	ErrTooLarge        = errors.New("funny/binary/bson: document exceeds limit")
+	ErrTooDeep         = errors.New("funny/binary/bson: nesting exceeds limit")
+	ErrNotFound        = errors.New("funny/binary/bson: key not found")
+)
+
+// Element types.
+const (
+	TypeDouble        = 0x01
+	TypeString        = 0x02
+	TypeDocument      = 0x03
+	TypeArray         = 0x04
+	TypeBinary        = 0x05
+	TypeUndefined     = 0x06
+	TypeObjectID      = 0x07
+	TypeBoolean       = 0x08
+	TypeDateTime      = 0x09
+	TypeNull          = 0x0A
+	TypeRegex         = 0x0B
+	TypeDBPointer     = 0x0C
+	TypeJavaScript    = 0x0D
+	TypeSymbol        = 0x0E
+	TypeCodeWithScope = 0x0F
+	TypeInt32         = 0x10
+	TypeTimestamp     = 0x11
+	TypeInt64         = 0x12
+	TypeDecimal128    = 0x13
+	TypeMinKey        = 0xFF
+	TypeMaxKey        = 0x7F
+)
+
+// Binary subtypes.
+const (
+	BinaryGeneric     = 0x00
+	BinaryFunction    = 0x01
+	BinaryOld         = 0x02
+	BinaryUUIDOld     = 0x03
+	BinaryUUID        = 0x04
+	BinaryMD5         = 0x05
+	BinaryEncrypted   = 0x06
+	BinaryColumn      = 0x07
+	BinarySensitive   = 0x08
+	BinaryUserDefined = 0x80
+)
+
+// DefaultMaxLen limits the size of a document read by a Decoder when its
+// MaxLen is zero. MongoDB itself stops at 16 MiB.
+const DefaultMaxLen = 16 << 20
+
+const maxDepth = 1000
+
+// E is an element of a document.
+type E struct {
+	Key   string
+	Value interface{}
+}
+
+// D is a document with its elements in order.
+type D []E
+
+// Get returns the value of the first element named key.
+func (d D) Get(key string) (interface{}, bool) {
+	for _, e := range d {
+		if e.Key == key {
+			return e.Value, true
+		}
+	}
+	return nil, false
+}
+
+// M is an unordered document, encoded with its keys sorted.
+type M map[string]interface{}
+
+// A is an array.
+type A []interface{}
+
+type ObjectID [12]byte
+
+func (id ObjectID) String() string {
+	return hex.EncodeToString(id[:])
+}
+
+// Time returns the creation time held in the first four bytes.
+func (id ObjectID) Time() time.Time {
+	return time.Unix(int64(uint32(id[0])<<24|uint32(id[1])<<16|uint32(id[2])<<8|uint32(id[3])), 0)
+}
+
+// Binary is binary data with a subtype. Generic binary data also decodes
+// from and encodes to a plain []byte.
+type Binary struct {
+	Subtype byte
+	Data    []byte
+}
+
+// Decimal128 is an IEEE 754-2008 decimal kept as its little endian bytes.
+type Decimal128 [16]byte
+
+// Timestamp is the internal MongoDB replication timestamp.
+type Timestamp struct {
+	T uint32
+	I uint32
+}
+
+type Regex struct {
+	Pattern string
+	Options string
+}
+
+type JavaScript string
+
+type Symbol string
+
+type CodeWithScope struct {
+	Code  JavaScript
+	Scope D
+}
+
+type DBPointer struct {
+	Ref string
+	ID  ObjectID
+}
+
+type Undefined struct{}
+
+type MinKey struct{}
+
+type MaxKey struct{}
@@ -0,0 +1,23 @@
+package bson
+
+import (
+	"testing"
+	"time"
+
+	"github.com/funny/utest"
+)
+
+func Test_ObjectID(t *testing.T) {
+	id := ObjectID{0x50, 0x7f, 0x1f, 0x77, 0xbc, 0xf8, 0x6c, 0xd7, 0x99, 0x43, 0x90, 0x11}
+	utest.EqualNow(t, id.String(), "507f1f77bcf86cd799439011")
+	utest.EqualNow(t, id.Time().Equal(time.Unix(0x507f1f77, 0)), true)
+}
+
+func Test_D_Get(t *testing.T) {
+	d := D{{"a", 1}, {"b", 2}, {"a", 3}}
+	v, ok := d.Get("a")
+	utest.EqualNow(t, ok, true)
+	utest.EqualNow(t, v, 1)
+	_, ok = d.Get("c")
+	utest.EqualNow(t, ok, false)
+}
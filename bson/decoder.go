@@ -0,0 +1,72 @@
+package bson
+
+import (
+	"io"
+	"math"
+
+	"github.com/funny/binary"
+)
+
+// Decoder reads a stream of documents, like a mongodump file, from a
+// BinaryReader.
+type Decoder struct {
+	r binary.BinaryReader
+
+	// MaxLen limits the size of a document, zero means DefaultMaxLen.
+	MaxLen int
+}
+
+func NewDecoder(r binary.BinaryReader) *Decoder {
+	return &Decoder{r: r}
+}
+
+// ReadRaw reads and validates the next document, io.EOF means the stream
+// ended cleanly between documents.
+func (d *Decoder) ReadRaw() (Raw, error) {
+	// Reading past the data of a Buffer panics instead of giving io.EOF.
+	buf, isBuf := d.r.(*binary.Buffer)
+	if isBuf {
+		if buf.ReadPos >= len(buf.Data) {
+			return nil, io.EOF
+		}
+		if len(buf.Data)-buf.ReadPos < 4 {
+			return nil, io.ErrUnexpectedEOF
+		}
+	}
+	n := d.r.ReadUint32LE()
+	if err := d.r.Error(); err != nil {
+		return nil, err
+	}
+	maxLen := d.MaxLen
+	if maxLen <= 0 {
+		maxLen = DefaultMaxLen
+	}
+	if n < 5 || n > math.MaxInt32 {
+		return nil, ErrInvalidDocument
+	}
+	if n > uint32(maxLen) {
+		return nil, ErrTooLarge
+	}
+	if isBuf && uint32(len(buf.Data)-buf.ReadPos) < n-4 {
+		return nil, io.ErrUnexpectedEOF
+	}
+	raw := make(Raw, 4, n)
+	binary.PutUint32LE(raw, n)
+	raw = append(raw, d.r.ReadBytes(int(n)-4)...)
+	if err := d.r.Error(); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, err
+	}
+	return raw, raw.Validate()
+}
+
+// Decode reads the next document.
+func (d *Decoder) Decode() (D, error) {
+	raw, err := d.ReadRaw()
+	if err != nil {
+		return nil, err
+	}
+	return raw.decode()
+}
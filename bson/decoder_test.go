@@ -0,0 +1,113 @@
+package bson
+
+import (
+	"bytes"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/funny/binary"
+	"github.com/funny/utest"
+)
+
+func Test_Decoder_RoundTrip(t *testing.T) {
+	doc := D{
+		{"double", 1.25},
+		{"string", "héllo"},
+		{"doc", D{{"x", nil}}},
+		{"array", A{int32(1), "two", A{}}},
+		{"bin", []byte{1, 2}},
+		{"old", Binary{BinaryOld, []byte{3}}},
+		{"uuid", Binary{BinaryUUID, make([]byte, 16)}},
+		{"undef", Undefined{}},
+		{"id", ObjectID{1, 2, 3}},
+		{"bool", false},
+		{"date", time.Unix(-2, 1e6).UTC()},
+		{"null", nil},
+		{"re", Regex{"^a", "im"}},
+		{"ptr", DBPointer{"coll", ObjectID{4}}},
+		{"js", JavaScript("f()")},
+		{"sym", Symbol("s")},
+		{"cws", CodeWithScope{"g()", D{{"y", int32(2)}}}},
+		{"i32", int32(-5)},
+		{"ts", Timestamp{T: 100, I: 7}},
+		{"i64", int64(-1) << 40},
+		{"dec", Decimal128{1, 15: 0x30}},
+		{"min", MinKey{}},
+		{"max", MaxKey{}},
+	}
+	b, err := Marshal(doc)
+	utest.IsNilNow(t, err)
+	d, err := Raw(b).Decode()
+	utest.IsNilNow(t, err)
+	utest.EqualNow(t, d, doc)
+}
+
+func Test_Decoder_Stream(t *testing.T) {
+	var buf bytes.Buffer
+	for i := 0; i < 3; i++ {
+		b, _ := Marshal(M{"i": i})
+		buf.Write(b)
+	}
+	d := NewDecoder(binary.NewReader(&buf))
+	for i := 0; i < 3; i++ {
+		doc, err := d.Decode()
+		utest.IsNilNow(t, err)
+		utest.EqualNow(t, doc, D{{"i", int32(i)}})
+	}
+	_, err := d.Decode()
+	utest.EqualNow(t, err, io.EOF)
+}
+
+func Test_Decoder_Buffer(t *testing.T) {
+	var data []byte
+	for i := 0; i < 2; i++ {
+		b, _ := Marshal(M{"i": i})
+		data = append(data, b...)
+	}
+	d := NewDecoder(&binary.Buffer{Data: data})
+	for i := 0; i < 2; i++ {
+		doc, err := d.Decode()
+		utest.IsNilNow(t, err)
+		utest.EqualNow(t, doc, D{{"i", int32(i)}})
+	}
+	_, err := d.Decode()
+	utest.EqualNow(t, err, io.EOF)
+
+	for _, n := range []int{2, 7} {
+		_, err = NewDecoder(&binary.Buffer{Data: data[:n]}).Decode()
+		utest.EqualNow(t, err, io.ErrUnexpectedEOF)
+	}
+}
+
+func Test_Decoder_Errors(t *testing.T) {
+	newDecoder := func(b string) *Decoder {
+		return NewDecoder(binary.NewReader(bytes.NewReader([]byte(b))))
+	}
+	_, err := newDecoder("\x04\x00\x00\x00").ReadRaw()
+	utest.EqualNow(t, err, ErrInvalidDocument)
+	_, err = newDecoder("\xff\xff\xff\xff").ReadRaw()
+	utest.EqualNow(t, err, ErrInvalidDocument)
+	_, err = newDecoder("\x06\x00\x00\x00\x00").ReadRaw()
+	utest.EqualNow(t, err, io.ErrUnexpectedEOF)
+	_, err = newDecoder("\x06\x00\x00\x00\x00\x00").ReadRaw()
+	utest.EqualNow(t, err, ErrInvalidDocument)
+	d := newDecoder("\x06\x00\x00\x00\x00\x00")
+	d.MaxLen = 5
+	_, err = d.ReadRaw()
+	utest.EqualNow(t, err, ErrTooLarge)
+
+	// Embedded documents nested past the limit.
+	var b []byte
+	for i := 0; i <= maxDepth+1; i++ {
+		inner := b
+		if inner == nil {
+			inner = []byte{5, 0, 0, 0, 0}
+		}
+		n := 4 + 3 + len(inner) + 1
+		b = append([]byte{byte(n), byte(n >> 8), byte(n >> 16), 0, TypeDocument, 'a', 0}, inner...)
+		b = append(b, 0)
+	}
+	_, err = Raw(b).Decode()
+	utest.EqualNow(t, err, ErrTooDeep)
+}
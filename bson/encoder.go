@@ -0,0 +1,340 @@
+package bson
+
+import (
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/funny/binary"
+	"github.com/funny/binary/internal/structs"
+)
+
+// Encoder appends documents to a Buffer and grows its Data as needed. The
+// length of a document is written as a placeholder and backpatched once the
+// document is complete, so nothing is encoded twice.
+type Encoder struct {
+	buf *binary.Buffer
+}
+
+func NewEncoder(buf *binary.Buffer) *Encoder {
+	return &Encoder{buf: buf}
+}
+
+// Marshal returns the encoding of doc.
+func Marshal(doc interface{}) ([]byte, error) {
+	var buf binary.Buffer
+	if err := NewEncoder(&buf).Encode(doc); err != nil {
+		return nil, err
+	}
+	return buf.Data[:buf.WritePos], nil
+}
+
+// Encode appends doc, which is a D, Raw, map with string keys, struct or a
+// pointer to one. Nothing is left in the Buffer on error.
+func (e *Encoder) Encode(doc interface{}) error {
+	start := e.buf.WritePos
+	err := e.encodeDocument(reflect.ValueOf(doc), 0)
+	if err != nil {
+		e.buf.WritePos = start
+	}
+	return err
+}
+
+func (e *Encoder) grow(n int) {
+	if len(e.buf.Data)-e.buf.WritePos >= n {
+		return
+	}
+	size := 2 * len(e.buf.Data)
+	if size < e.buf.WritePos+n {
+		size = e.buf.WritePos + n
+	}
+	if size < 64 {
+		size = 64
+	}
+	data := make([]byte, size)
+	copy(data, e.buf.Data)
+	e.buf.Data = data
+}
+
+func (e *Encoder) writeByte(b byte) {
+	e.grow(1)
+	e.buf.WriteUint8(b)
+}
+
+func (e *Encoder) writeBytes(b []byte) {
+	e.grow(len(b))
+	e.buf.WriteBytes(b)
+}
+
+func (e *Encoder) writeInt32(v int32) {
+	e.grow(4)
+	e.buf.WriteInt32LE(v)
+}
+
+func (e *Encoder) writeInt64(v int64) {
+	e.grow(8)
+	e.buf.WriteInt64LE(v)
+}
+
+func (e *Encoder) writeString(s string) {
+	e.writeInt32(int32(len(s) + 1))
+	e.grow(len(s) + 1)
+	e.buf.WriteString(s)
+	e.buf.WriteUint8(0)
+}
+
+func (e *Encoder) writeCString(s string) error {
+	if strings.IndexByte(s, 0) >= 0 {
+		return ErrInvalidCString
+	}
+	e.grow(len(s) + 1)
+	e.buf.WriteString(s)
+	e.buf.WriteUint8(0)
+	return nil
+}
+
+// begin writes a length placeholder and returns its position for end.
+func (e *Encoder) begin() int {
+	pos := e.buf.WritePos
+	e.writeInt32(0)
+	return pos
+}
+
+func (e *Encoder) end(pos int) error {
+	e.writeByte(0)
+	n := e.buf.WritePos - pos
+	if n > math.MaxInt32 {
+		return ErrTooLarge
+	}
+	binary.PutUint32LE(e.buf.Data[pos:], uint32(n))
+	return nil
+}
+
+func (e *Encoder) encodeDocument(v reflect.Value, depth int) error {
+	if depth > maxDepth {
+		return ErrTooDeep
+	}
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return ErrUnsupportedType
+		}
+		if depth++; depth > maxDepth {
+			return ErrTooDeep
+		}
+		v = v.Elem()
+	}
+	if !v.IsValid() {
+		return ErrUnsupportedType
+	}
+	switch x := v.Interface().(type) {
+	case D:
+		pos := e.begin()
+		for _, el := range x {
+			if err := e.encodeElement(el.Key, reflect.ValueOf(el.Value), depth); err != nil {
+				return err
+			}
+		}
+		return e.end(pos)
+	case Raw:
+		if err := x.Validate(); err != nil {
+			return err
+		}
+		e.writeBytes(x)
+		return nil
+	}
+	switch v.Kind() {
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			return ErrUnsupportedType
+		}
+		keys := v.MapKeys()
+		sort.Slice(keys, func(i, j int) bool { return keys[i].String() < keys[j].String() })
+		pos := e.begin()
+		for _, k := range keys {
+			if err := e.encodeElement(k.String(), v.MapIndex(k), depth); err != nil {
+				return err
+			}
+		}
+		return e.end(pos)
+	case reflect.Struct:
+		pos := e.begin()
+		for _, f := range structs.Fields(v.Type(), "bson") {
+			fv := v.FieldByIndex(f.Index)
+			if f.OmitEmpty && structs.IsEmpty(fv) {
+				continue
+			}
+			if err := e.encodeElement(f.Name, fv, depth); err != nil {
+				return err
+			}
+		}
+		return e.end(pos)
+	}
+	return ErrUnsupportedType
+}
+
+func (e *Encoder) encodeArray(v reflect.Value, depth int) error {
+	pos := e.begin()
+	for i := 0; i < v.Len(); i++ {
+		if err := e.encodeElement(strconv.Itoa(i), v.Index(i), depth); err != nil {
+			return err
+		}
+	}
+	return e.end(pos)
+}
+
+// encodeElement writes the type byte after the value tells what it is.
+func (e *Encoder) encodeElement(key string, v reflect.Value, depth int) error {
+	pos := e.buf.WritePos
+	e.writeByte(0)
+	if err := e.writeCString(key); err != nil {
+		return err
+	}
+	typ, err := e.encodeValue(v, depth+1)
+	if err != nil {
+		return err
+	}
+	e.buf.Data[pos] = typ
+	return nil
+}
+
+func (e *Encoder) writeBinary(subtype byte, data []byte) {
+	if subtype == BinaryOld {
+		e.writeInt32(int32(len(data) + 4))
+		e.writeByte(subtype)
+		e.writeInt32(int32(len(data)))
+	} else {
+		e.writeInt32(int32(len(data)))
+		e.writeByte(subtype)
+	}
+	e.writeBytes(data)
+}
+
+func (e *Encoder) encodeValue(v reflect.Value, depth int) (byte, error) {
+	if depth > maxDepth {
+		return 0, ErrTooDeep
+	}
+	if !v.IsValid() {
+		return TypeNull, nil
+	}
+	switch x := v.Interface().(type) {
+	case time.Time:
+		e.writeInt64(x.Unix()*1000 + int64(x.Nanosecond())/1e6)
+		return TypeDateTime, nil
+	case ObjectID:
+		e.writeBytes(x[:])
+		return TypeObjectID, nil
+	case Decimal128:
+		e.writeBytes(x[:])
+		return TypeDecimal128, nil
+	case Binary:
+		e.writeBinary(x.Subtype, x.Data)
+		return TypeBinary, nil
+	case Timestamp:
+		e.writeInt64(int64(x.T)<<32 | int64(x.I))
+		return TypeTimestamp, nil
+	case Regex:
+		if err := e.writeCString(x.Pattern); err != nil {
+			return 0, err
+		}
+		return TypeRegex, e.writeCString(x.Options)
+	case JavaScript:
+		e.writeString(string(x))
+		return TypeJavaScript, nil
+	case Symbol:
+		e.writeString(string(x))
+		return TypeSymbol, nil
+	case CodeWithScope:
+		pos := e.begin()
+		e.writeString(string(x.Code))
+		if err := e.encodeDocument(reflect.ValueOf(x.Scope), depth); err != nil {
+			return 0, err
+		}
+		binary.PutUint32LE(e.buf.Data[pos:], uint32(e.buf.WritePos-pos))
+		return TypeCodeWithScope, nil
+	case DBPointer:
+		e.writeString(x.Ref)
+		e.writeBytes(x.ID[:])
+		return TypeDBPointer, nil
+	case Undefined:
+		return TypeUndefined, nil
+	case MinKey:
+		return TypeMinKey, nil
+	case MaxKey:
+		return TypeMaxKey, nil
+	case RawValue:
+		e.writeBytes(x.Data)
+		return x.Type, nil
+	case Raw, D:
+		return TypeDocument, e.encodeDocument(v, depth)
+	}
+	switch v.Kind() {
+	case reflect.Bool:
+		if v.Bool() {
+			e.writeByte(1)
+		} else {
+			e.writeByte(0)
+		}
+		return TypeBoolean, nil
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint8, reflect.Uint16:
+		e.writeInt32(int32(toInt64(v)))
+		return TypeInt32, nil
+	case reflect.Int:
+		if n := v.Int(); n >= math.MinInt32 && n <= math.MaxInt32 {
+			e.writeInt32(int32(n))
+			return TypeInt32, nil
+		}
+		e.writeInt64(v.Int())
+		return TypeInt64, nil
+	case reflect.Int64, reflect.Uint32:
+		e.writeInt64(toInt64(v))
+		return TypeInt64, nil
+	case reflect.Uint, reflect.Uint64, reflect.Uintptr:
+		if v.Uint() > math.MaxInt64 {
+			return 0, ErrOverflow
+		}
+		e.writeInt64(int64(v.Uint()))
+		return TypeInt64, nil
+	case reflect.Float32, reflect.Float64:
+		e.grow(8)
+		e.buf.WriteFloat64LE(v.Float())
+		return TypeDouble, nil
+	case reflect.String:
+		e.writeString(v.String())
+		return TypeString, nil
+	case reflect.Slice:
+		if v.IsNil() {
+			return TypeNull, nil
+		}
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			e.writeBinary(BinaryGeneric, v.Bytes())
+			return TypeBinary, nil
+		}
+		return TypeArray, e.encodeArray(v, depth)
+	case reflect.Array:
+		return TypeArray, e.encodeArray(v, depth)
+	case reflect.Map:
+		if v.IsNil() {
+			return TypeNull, nil
+		}
+		return TypeDocument, e.encodeDocument(v, depth)
+	case reflect.Struct:
+		return TypeDocument, e.encodeDocument(v, depth)
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			return TypeNull, nil
+		}
+		return e.encodeValue(v.Elem(), depth+1)
+	}
+	return 0, ErrUnsupportedType
+}
+
+func toInt64(v reflect.Value) int64 {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int()
+	}
+	return int64(v.Uint())
+}
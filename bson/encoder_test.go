@@ -0,0 +1,132 @@
+package bson
+
+import (
+	"testing"
+	"time"
+
+	"github.com/funny/binary"
+	"github.com/funny/utest"
+)
+
+func Test_Encoder_Spec(t *testing.T) {
+	b, err := Marshal(M{"hello": "world"})
+	utest.IsNilNow(t, err)
+	utest.EqualNow(t, b, []byte("\x16\x00\x00\x00\x02hello\x00\x06\x00\x00\x00world\x00\x00"))
+
+	b, err = Marshal(D{{"BSON", A{"awesome", 5.05, 1986}}})
+	utest.IsNilNow(t, err)
+	utest.EqualNow(t, b, []byte("\x31\x00\x00\x00\x04BSON\x00\x26\x00\x00\x00"+
+		"\x020\x00\x08\x00\x00\x00awesome\x00"+
+		"\x011\x00\x33\x33\x33\x33\x33\x33\x14\x40"+
+		"\x102\x00\xc2\x07\x00\x00"+
+		"\x00\x00"))
+}
+
+func Test_Encoder_Types(t *testing.T) {
+	for _, c := range []struct {
+		value interface{}
+		data  string
+	}{
+		{nil, "\x0a"},
+		{true, "\x08\x01"},
+		{int8(-1), "\x10\xff\xff\xff\xff"},
+		{1 << 40, "\x12\x00\x00\x00\x00\x00\x01\x00\x00"},
+		{uint32(1), "\x12\x01\x00\x00\x00\x00\x00\x00\x00"},
+		{float32(1), "\x01\x00\x00\x00\x00\x00\x00\xf0\x3f"},
+		{[]byte{1}, "\x05\x01\x00\x00\x00\x00\x01"},
+		{Binary{BinaryOld, []byte{1}}, "\x05\x05\x00\x00\x00\x02\x01\x00\x00\x00\x01"},
+		{Binary{BinaryUUID, []byte{1}}, "\x05\x01\x00\x00\x00\x04\x01"},
+		{time.Unix(1, 500e6), "\x09\xdc\x05\x00\x00\x00\x00\x00\x00"},
+		{time.Unix(-1, 500e6), "\x09\x0c\xfe\xff\xff\xff\xff\xff\xff"},
+		{ObjectID{1}, "\x07\x01\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00"},
+		{Timestamp{T: 1, I: 2}, "\x11\x02\x00\x00\x00\x01\x00\x00\x00"},
+		{Regex{"a", "i"}, "\x0ba\x00i\x00"},
+		{JavaScript("f"), "\x0d\x02\x00\x00\x00f\x00"},
+		{Symbol("s"), "\x0e\x02\x00\x00\x00s\x00"},
+		{CodeWithScope{"f", D{}}, "\x0f\x0f\x00\x00\x00\x02\x00\x00\x00f\x00\x05\x00\x00\x00\x00"},
+		{DBPointer{"r", ObjectID{}}, "\x0c\x02\x00\x00\x00r\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00"},
+		{Undefined{}, "\x06"},
+		{MinKey{}, "\xff"},
+		{MaxKey{}, "\x7f"},
+		{Decimal128{15: 0x30}, "\x13" + string(make([]byte, 15)) + "\x30"},
+		{[]string(nil), "\x0a"},
+		{[1]int32{7}, "\x04\x0c\x00\x00\x00\x100\x00\x07\x00\x00\x00\x00"},
+		{map[string]bool{}, "\x03\x05\x00\x00\x00\x00"},
+		{RawValue{TypeInt32, []byte{9, 0, 0, 0}}, "\x10\x09\x00\x00\x00"},
+	} {
+		b, err := Marshal(D{{"k", c.value}})
+		utest.IsNilNow(t, err)
+		// Strip the length, the key after the type byte and the terminator.
+		body := string(b[4 : len(b)-1])
+		utest.EqualNow(t, body[:1]+body[3:], c.data)
+	}
+}
+
+func Test_Encoder_Struct(t *testing.T) {
+	type Inner struct {
+		N int64 `bson:"n"`
+	}
+	type Doc struct {
+		ID    ObjectID `bson:"_id"`
+		Name  string
+		Skip  int    `bson:"-"`
+		Empty string `bson:",omitempty"`
+		Inner *Inner
+		Raw   Raw
+	}
+	raw, _ := Marshal(M{"x": int32(1)})
+	b, err := Marshal(&Doc{ID: ObjectID{1}, Name: "n", Inner: &Inner{2}, Raw: raw})
+	utest.IsNilNow(t, err)
+	d, err := Raw(b).Decode()
+	utest.IsNilNow(t, err)
+	utest.EqualNow(t, d, D{
+		{"_id", ObjectID{1}},
+		{"Name", "n"},
+		{"Inner", D{{"n", int64(2)}}},
+		{"Raw", D{{"x", int32(1)}}},
+	})
+}
+
+func Test_Encoder_Backpatch(t *testing.T) {
+	// Documents are appended to the Buffer after what is already there,
+	// which must stay intact as Data grows.
+	buf := binary.Buffer{Data: make([]byte, 2)}
+	buf.WriteUint16LE(0xbeef)
+	e := NewEncoder(&buf)
+	utest.IsNilNow(t, e.Encode(M{"a": make([]byte, 100)}))
+	utest.IsNilNow(t, e.Encode(M{}))
+	utest.EqualNow(t, buf.Data[:2], []byte{0xef, 0xbe})
+	utest.EqualNow(t, binary.GetUint32LE(buf.Data[2:]), uint32(4+1+2+4+1+100+1))
+	utest.EqualNow(t, buf.Data[2+113:buf.WritePos], []byte{5, 0, 0, 0, 0})
+
+	// A failed Encode leaves nothing behind.
+	pos := buf.WritePos
+	utest.EqualNow(t, e.Encode(D{{"a", 1}, {"b\x00", 2}}), ErrInvalidCString)
+	utest.EqualNow(t, buf.WritePos, pos)
+}
+
+func Test_Encoder_Errors(t *testing.T) {
+	for _, c := range []struct {
+		doc interface{}
+		err error
+	}{
+		{nil, ErrUnsupportedType},
+		{1, ErrUnsupportedType},
+		{(*M)(nil), ErrUnsupportedType},
+		{map[int]int{}, ErrUnsupportedType},
+		{M{"a": make(chan int)}, ErrUnsupportedType},
+		{M{"a": uint64(1) << 63}, ErrOverflow},
+		{M{"a": Regex{"a\x00", ""}}, ErrInvalidCString},
+		{M{"a": Raw{1, 2}}, ErrInvalidDocument},
+	} {
+		_, err := Marshal(c.doc)
+		utest.EqualNow(t, err, c.err)
+	}
+
+	var cycle interface{}
+	cycle = &cycle
+	_, err := Marshal(M{"a": cycle})
+	utest.EqualNow(t, err, ErrTooDeep)
+	_, err = Marshal(cycle)
+	utest.EqualNow(t, err, ErrTooDeep)
+}
@@ -0,0 +1,423 @@
+package bson
+
+import (
+	"bytes"
+	"time"
+
+	"github.com/funny/binary"
+)
+
+// Raw is an encoded document. Its methods check the framing of the parts
+// they touch, so a malformed document gives ErrInvalidDocument and not a
+// panic.
+type Raw []byte
+
+// RawValue is an encoded element value, Data aliases the document.
+type RawValue struct {
+	Type byte
+	Data []byte
+}
+
+type RawElement struct {
+	Key   string
+	Value RawValue
+}
+
+// body returns the elements of the document, without length and
+// terminator.
+func (r Raw) body() ([]byte, error) {
+	if len(r) < 5 {
+		return nil, ErrInvalidDocument
+	}
+	n := binary.GetUint32LE(r)
+	if n != uint32(len(r)) || r[n-1] != 0 {
+		return nil, ErrInvalidDocument
+	}
+	return r[4 : n-1], nil
+}
+
+// nextElement splits the first element off body.
+func nextElement(body []byte) (RawElement, []byte, error) {
+	typ := body[0]
+	i := bytes.IndexByte(body[1:], 0)
+	if i < 0 {
+		return RawElement{}, nil, ErrInvalidDocument
+	}
+	key := string(body[1 : 1+i])
+	data := body[2+i:]
+	n, err := valueSize(typ, data)
+	if err != nil {
+		return RawElement{}, nil, err
+	}
+	return RawElement{key, RawValue{typ, data[:n]}}, data[n:], nil
+}
+
+// lenPrefix returns the int32 length at the start of b when it is at least
+// min and fits in b.
+func lenPrefix(b []byte, min int) (int, error) {
+	if len(b) < 4 {
+		return 0, ErrInvalidDocument
+	}
+	n := int32(binary.GetUint32LE(b))
+	if int64(n) < int64(min) || int64(n) > int64(len(b)) {
+		return 0, ErrInvalidDocument
+	}
+	return int(n), nil
+}
+
+func stringSize(b []byte) (int, error) {
+	if len(b) < 4 {
+		return 0, ErrInvalidDocument
+	}
+	n := int32(binary.GetUint32LE(b))
+	if n < 1 || int64(n) > int64(len(b)-4) || b[3+n] != 0 {
+		return 0, ErrInvalidDocument
+	}
+	return 4 + int(n), nil
+}
+
+func cstringSize(b []byte) (int, error) {
+	i := bytes.IndexByte(b, 0)
+	if i < 0 {
+		return 0, ErrInvalidDocument
+	}
+	return i + 1, nil
+}
+
+func valueSize(typ byte, b []byte) (int, error) {
+	n := 0
+	switch typ {
+	case TypeDouble, TypeDateTime, TypeInt64, TypeTimestamp:
+		n = 8
+	case TypeInt32:
+		n = 4
+	case TypeBoolean:
+		n = 1
+	case TypeObjectID:
+		n = 12
+	case TypeDecimal128:
+		n = 16
+	case TypeUndefined, TypeNull, TypeMinKey, TypeMaxKey:
+	case TypeString, TypeJavaScript, TypeSymbol:
+		return stringSize(b)
+	case TypeDocument, TypeArray:
+		return lenPrefix(b, 5)
+	case TypeCodeWithScope:
+		return lenPrefix(b, 14)
+	case TypeBinary:
+		if len(b) < 5 {
+			return 0, ErrInvalidDocument
+		}
+		l := int32(binary.GetUint32LE(b))
+		if l < 0 || int64(l) > int64(len(b)-5) {
+			return 0, ErrInvalidDocument
+		}
+		return 5 + int(l), nil
+	case TypeRegex:
+		p, err := cstringSize(b)
+		if err != nil {
+			return 0, err
+		}
+		o, err := cstringSize(b[p:])
+		return p + o, err
+	case TypeDBPointer:
+		s, err := stringSize(b)
+		if err != nil || len(b) < s+12 {
+			return 0, ErrInvalidDocument
+		}
+		return s + 12, nil
+	default:
+		return 0, ErrInvalidType
+	}
+	if len(b) < n {
+		return 0, ErrInvalidDocument
+	}
+	return n, nil
+}
+
+// Elements splits the document into its elements without decoding them.
+func (r Raw) Elements() ([]RawElement, error) {
+	body, err := r.body()
+	if err != nil {
+		return nil, err
+	}
+	var elems []RawElement
+	for len(body) > 0 {
+		var e RawElement
+		if e, body, err = nextElement(body); err != nil {
+			return nil, err
+		}
+		elems = append(elems, e)
+	}
+	return elems, nil
+}
+
+// Lookup finds the value at path, descending into embedded documents and
+// arrays, whose keys are the decimal indexes. Only the elements before the
+// match are looked at.
+func (r Raw) Lookup(path ...string) (RawValue, error) {
+	if len(path) == 0 {
+		return RawValue{}, ErrNotFound
+	}
+	body, err := r.body()
+	if err != nil {
+		return RawValue{}, err
+	}
+	for len(body) > 0 {
+		var e RawElement
+		if e, body, err = nextElement(body); err != nil {
+			return RawValue{}, err
+		}
+		if e.Key != path[0] {
+			continue
+		}
+		if len(path) == 1 {
+			return e.Value, nil
+		}
+		if e.Value.Type != TypeDocument && e.Value.Type != TypeArray {
+			return RawValue{}, ErrNotFound
+		}
+		return Raw(e.Value.Data).Lookup(path[1:]...)
+	}
+	return RawValue{}, ErrNotFound
+}
+
+// Validate checks the whole document, including embedded ones.
+func (r Raw) Validate() error {
+	return r.validate(0)
+}
+
+func (r Raw) validate(depth int) error {
+	if depth > maxDepth {
+		return ErrTooDeep
+	}
+	body, err := r.body()
+	if err != nil {
+		return err
+	}
+	for len(body) > 0 {
+		var e RawElement
+		if e, body, err = nextElement(body); err != nil {
+			return err
+		}
+		if err = e.Value.validate(depth); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (v RawValue) validate(depth int) error {
+	switch v.Type {
+	case TypeDocument, TypeArray:
+		return Raw(v.Data).validate(depth + 1)
+	case TypeBoolean:
+		if v.Data[0] > 1 {
+			return ErrInvalidDocument
+		}
+	case TypeBinary:
+		if v.Data[4] == BinaryOld && (len(v.Data) < 9 || int(binary.GetUint32LE(v.Data[5:])) != len(v.Data)-9) {
+			return ErrInvalidDocument
+		}
+	case TypeCodeWithScope:
+		s, err := stringSize(v.Data[4:])
+		if err != nil {
+			return err
+		}
+		return Raw(v.Data[4+s:]).validate(depth + 1)
+	}
+	return nil
+}
+
+// Decode validates and decodes the document.
+func (r Raw) Decode() (D, error) {
+	if err := r.Validate(); err != nil {
+		return nil, err
+	}
+	return r.decode()
+}
+
+// decode expects a validated document.
+func (r Raw) decode() (D, error) {
+	elems, err := r.Elements()
+	if err != nil {
+		return nil, err
+	}
+	d := make(D, len(elems))
+	for i, e := range elems {
+		d[i].Key = e.Key
+		if d[i].Value, err = e.Value.decode(); err != nil {
+			return nil, err
+		}
+	}
+	return d, nil
+}
+
+func rawString(b []byte) string {
+	return string(b[4 : len(b)-1])
+}
+
+func copyBytes(b []byte) []byte {
+	return append([]byte(nil), b...)
+}
+
+// Interface validates and decodes the value. Documents become D and arrays
+// A, generic binary data is a []byte. Byte slices do not alias the document.
+func (v RawValue) Interface() (interface{}, error) {
+	if err := v.validate(0); err != nil {
+		return nil, err
+	}
+	return v.decode()
+}
+
+// decode expects a validated value.
+func (v RawValue) decode() (interface{}, error) {
+	b := v.Data
+	switch v.Type {
+	case TypeDouble:
+		return binary.GetFloat64LE(b), nil
+	case TypeString:
+		return rawString(b), nil
+	case TypeDocument:
+		return Raw(b).decode()
+	case TypeArray:
+		d, err := Raw(b).decode()
+		if err != nil {
+			return nil, err
+		}
+		a := make(A, len(d))
+		for i, e := range d {
+			a[i] = e.Value
+		}
+		return a, nil
+	case TypeBinary:
+		switch b[4] {
+		case BinaryGeneric:
+			return copyBytes(b[5:]), nil
+		case BinaryOld:
+			return Binary{BinaryOld, copyBytes(b[9:])}, nil
+		}
+		return Binary{b[4], copyBytes(b[5:])}, nil
+	case TypeUndefined:
+		return Undefined{}, nil
+	case TypeObjectID:
+		var id ObjectID
+		copy(id[:], b)
+		return id, nil
+	case TypeBoolean:
+		return b[0] != 0, nil
+	case TypeDateTime:
+		t, _ := v.Time()
+		return t, nil
+	case TypeNull:
+		return nil, nil
+	case TypeRegex:
+		p, _ := cstringSize(b)
+		return Regex{string(b[:p-1]), string(b[p : len(b)-1])}, nil
+	case TypeDBPointer:
+		var id ObjectID
+		copy(id[:], b[len(b)-12:])
+		return DBPointer{rawString(b[:len(b)-12]), id}, nil
+	case TypeJavaScript:
+		return JavaScript(rawString(b)), nil
+	case TypeSymbol:
+		return Symbol(rawString(b)), nil
+	case TypeCodeWithScope:
+		s, _ := stringSize(b[4:])
+		scope, err := Raw(b[4+s:]).decode()
+		if err != nil {
+			return nil, err
+		}
+		return CodeWithScope{JavaScript(rawString(b[4 : 4+s])), scope}, nil
+	case TypeInt32:
+		return int32(binary.GetUint32LE(b)), nil
+	case TypeTimestamp:
+		return Timestamp{binary.GetUint32LE(b[4:]), binary.GetUint32LE(b)}, nil
+	case TypeInt64:
+		return int64(binary.GetUint64LE(b)), nil
+	case TypeDecimal128:
+		var d Decimal128
+		copy(d[:], b)
+		return d, nil
+	case TypeMinKey:
+		return MinKey{}, nil
+	case TypeMaxKey:
+		return MaxKey{}, nil
+	}
+	return nil, ErrInvalidType
+}
+
+func (v RawValue) Double() (float64, bool) {
+	if v.Type != TypeDouble {
+		return 0, false
+	}
+	return binary.GetFloat64LE(v.Data), true
+}
+
+func (v RawValue) StringValue() (string, bool) {
+	if v.Type != TypeString {
+		return "", false
+	}
+	return rawString(v.Data), true
+}
+
+func (v RawValue) Boolean() (bool, bool) {
+	if v.Type != TypeBoolean {
+		return false, false
+	}
+	return v.Data[0] != 0, true
+}
+
+func (v RawValue) Int32() (int32, bool) {
+	if v.Type != TypeInt32 {
+		return 0, false
+	}
+	return int32(binary.GetUint32LE(v.Data)), true
+}
+
+// Int64 also widens an int32 value.
+func (v RawValue) Int64() (int64, bool) {
+	switch v.Type {
+	case TypeInt64:
+		return int64(binary.GetUint64LE(v.Data)), true
+	case TypeInt32:
+		return int64(int32(binary.GetUint32LE(v.Data))), true
+	}
+	return 0, false
+}
+
+// Time returns a datetime value in UTC.
+func (v RawValue) Time() (time.Time, bool) {
+	if v.Type != TypeDateTime {
+		return time.Time{}, false
+	}
+	ms := int64(binary.GetUint64LE(v.Data))
+	sec, rem := ms/1000, ms%1000
+	if rem < 0 {
+		sec, rem = sec-1, rem+1000
+	}
+	return time.Unix(sec, rem*int64(time.Millisecond)).UTC(), true
+}
+
+func (v RawValue) ObjectID() (ObjectID, bool) {
+	var id ObjectID
+	if v.Type != TypeObjectID {
+		return id, false
+	}
+	copy(id[:], v.Data)
+	return id, true
+}
+
+func (v RawValue) Document() (Raw, bool) {
+	if v.Type != TypeDocument {
+		return nil, false
+	}
+	return Raw(v.Data), true
+}
+
+func (v RawValue) Array() (Raw, bool) {
+	if v.Type != TypeArray {
+		return nil, false
+	}
+	return Raw(v.Data), true
+}
@@ -0,0 +1,128 @@
+package bson
+
+import (
+	"testing"
+	"time"
+
+	"github.com/funny/utest"
+)
+
+func Test_Raw_Lookup(t *testing.T) {
+	now := time.Unix(1700000000, 123e6).UTC()
+	b, err := Marshal(D{
+		{"name", "x"},
+		{"n", int32(7)},
+		{"big", int64(1) << 40},
+		{"f", 1.5},
+		{"ok", true},
+		{"at", now},
+		{"id", ObjectID{9}},
+		{"doc", D{{"tags", A{"a", "b", D{{"deep", int32(1)}}}}}},
+	})
+	utest.IsNilNow(t, err)
+	r := Raw(b)
+
+	v, err := r.Lookup("name")
+	utest.IsNilNow(t, err)
+	s, ok := v.StringValue()
+	utest.EqualNow(t, ok, true)
+	utest.EqualNow(t, s, "x")
+	_, ok = v.Int32()
+	utest.EqualNow(t, ok, false)
+
+	v, _ = r.Lookup("n")
+	i32, _ := v.Int32()
+	utest.EqualNow(t, i32, int32(7))
+	i64, ok := v.Int64()
+	utest.EqualNow(t, ok, true)
+	utest.EqualNow(t, i64, int64(7))
+	v, _ = r.Lookup("big")
+	i64, _ = v.Int64()
+	utest.EqualNow(t, i64, int64(1)<<40)
+	v, _ = r.Lookup("f")
+	f, _ := v.Double()
+	utest.EqualNow(t, f, 1.5)
+	v, _ = r.Lookup("ok")
+	bv, _ := v.Boolean()
+	utest.EqualNow(t, bv, true)
+	v, _ = r.Lookup("at")
+	at, _ := v.Time()
+	utest.EqualNow(t, at, now)
+	v, _ = r.Lookup("id")
+	id, _ := v.ObjectID()
+	utest.EqualNow(t, id, ObjectID{9})
+
+	v, err = r.Lookup("doc", "tags", "1")
+	utest.IsNilNow(t, err)
+	s, _ = v.StringValue()
+	utest.EqualNow(t, s, "b")
+	v, err = r.Lookup("doc", "tags", "2", "deep")
+	utest.IsNilNow(t, err)
+	i32, _ = v.Int32()
+	utest.EqualNow(t, i32, int32(1))
+	v, _ = r.Lookup("doc")
+	doc, ok := v.Document()
+	utest.EqualNow(t, ok, true)
+	v, _ = doc.Lookup("tags")
+	arr, ok := v.Array()
+	utest.EqualNow(t, ok, true)
+	elems, err := arr.Elements()
+	utest.IsNilNow(t, err)
+	utest.EqualNow(t, len(elems), 3)
+
+	for _, path := range [][]string{{}, {"nope"}, {"name", "x"}, {"doc", "tags", "3"}} {
+		_, err = r.Lookup(path...)
+		utest.EqualNow(t, err, ErrNotFound)
+	}
+}
+
+func Test_Raw_LookupPartial(t *testing.T) {
+	// Elements after the match are not looked at, so a lookup succeeds on
+	// a document with damage further on.
+	b, _ := Marshal(D{{"a", int32(1)}, {"b", "x"}})
+	b[len(b)-4] = 0x7e
+	v, err := Raw(b).Lookup("a")
+	utest.IsNilNow(t, err)
+	utest.EqualNow(t, v.Type, byte(TypeInt32))
+	_, err = Raw(b).Lookup("b")
+	utest.EqualNow(t, err, ErrInvalidDocument)
+	utest.EqualNow(t, Raw(b).Validate(), ErrInvalidDocument)
+}
+
+func Test_Raw_Validate(t *testing.T) {
+	for _, c := range []struct {
+		data string
+		err  error
+	}{
+		{"\x05\x00\x00\x00\x00", nil},
+		{"\x05\x00\x00\x00", ErrInvalidDocument},
+		{"\x06\x00\x00\x00\x00", ErrInvalidDocument},
+		{"\x05\x00\x00\x00\x01", ErrInvalidDocument},
+		{"\x08\x00\x00\x00\x0aa\x00\x00", nil},
+		{"\x08\x00\x00\x00\x0aab\x00", ErrInvalidDocument},
+		{"\x08\x00\x00\x00\x20a\x00\x00", ErrInvalidType},
+		{"\x09\x00\x00\x00\x08a\x00\x02\x00", ErrInvalidDocument},
+		{"\x0c\x00\x00\x00\x02a\x00\x01\x00\x00\x00\x01\x00", ErrInvalidDocument},
+		{"\x0c\x00\x00\x00\x02a\x00\xff\xff\xff\xff\x00\x00", ErrInvalidDocument},
+		{"\x0c\x00\x00\x00\x05a\x00\xff\xff\xff\xff\x00\x00", ErrInvalidDocument},
+		{"\x10\x00\x00\x00\x05a\x00\x01\x00\x00\x00\x02\x01\x00\x00\x00\x00", ErrInvalidDocument},
+		{"\x0d\x00\x00\x00\x03a\x00\x05\x00\x00\x00\x01\x00", ErrInvalidDocument},
+	} {
+		utest.EqualNow(t, Raw(c.data).Validate(), c.err)
+	}
+}
+
+func Test_Raw_InterfaceInvalid(t *testing.T) {
+	for _, c := range []struct {
+		data string
+		key  string
+	}{
+		{"\x0d\x00\x00\x00\x05b\x00\x00\x00\x00\x00\x02\x00", "b"},
+		{"\x16\x00\x00\x00\x0fc\x00\x0e\x00\x00\x00\x0a\x00\x00\x00\x0aabc\x00\x00\x00", "c"},
+	} {
+		v, err := Raw(c.data).Lookup(c.key)
+		utest.IsNilNow(t, err)
+		_, err = v.Interface()
+		utest.EqualNow(t, err, ErrInvalidDocument)
+	}
+}
@@ -1,6 +1,9 @@
 package binary
 
-import "errors"
+import (
+	"errors"
+	"net"
+)
 
 var ErrBufferFull = errors.New("funny/binary.Buffer: buffer full")
 
@@ -37,15 +40,111 @@ func (buf *Buffer) ReadString(n int) string {
 	return s
 }
 
+func (buf *Buffer) ReadBCD(nDigits int) uint64 {
+	n := BCDSize(nDigits)
+	v, err := GetBCD(buf.Data[buf.ReadPos:buf.ReadPos+n], nDigits)
+	if err != nil {
+		panic(err)
+	}
+	buf.ReadPos += n
+	return v
+}
+
+func (buf *Buffer) ReadPackedDecimal(nDigits int) int64 {
+	n := PackedDecimalSize(nDigits)
+	v, err := GetPackedDecimal(buf.Data[buf.ReadPos:buf.ReadPos+n], nDigits)
+	if err != nil {
+		panic(err)
+	}
+	buf.ReadPos += n
+	return v
+}
+
+func (buf *Buffer) ReadIPv4() net.IP {
+	return net.IP(buf.ReadBytes(net.IPv4len))
+}
+
+func (buf *Buffer) ReadIPv6() net.IP {
+	return net.IP(buf.ReadBytes(net.IPv6len))
+}
+
+func (buf *Buffer) ReadMAC() net.HardwareAddr {
+	return net.HardwareAddr(buf.ReadBytes(MACLen))
+}
+
+func (buf *Buffer) ReadUUID() UUID {
+	u := GetUUID(buf.Data[buf.ReadPos : buf.ReadPos+UUIDLen])
+	buf.ReadPos += UUIDLen
+	return u
+}
+
+func (buf *Buffer) ReadGUID() UUID {
+	u := GetGUID(buf.Data[buf.ReadPos : buf.ReadPos+UUIDLen])
+	buf.ReadPos += UUIDLen
+	return u
+}
+
+func (buf *Buffer) ReadBools(n int, order BitOrder) []bool {
+	size := BoolsSize(n)
+	v := GetBools(buf.Data[buf.ReadPos:buf.ReadPos+size], n, order)
+	buf.ReadPos += size
+	return v
+}
+
+// skipVarint panics instead of moving ReadPos backwards when a varint
+// could not be decoded.
+func (buf *Buffer) skipVarint(n int) {
+	if n <= 0 {
+		panic(varintError(n))
+	}
+	buf.ReadPos += n
+}
+
 func (buf *Buffer) ReadUvarint() uint64 {
 	v, n := GetUvarint(buf.Data[buf.ReadPos:])
-	buf.ReadPos += n
+	buf.skipVarint(n)
 	return v
 }
 
 func (buf *Buffer) ReadVarint() int64 {
 	v, n := GetVarint(buf.Data[buf.ReadPos:])
-	buf.ReadPos += n
+	buf.skipVarint(n)
+	return v
+}
+
+func (buf *Buffer) ReadQuicVarint() uint64 {
+	v, n := GetQuicVarint(buf.Data[buf.ReadPos:])
+	buf.skipVarint(n)
+	return v
+}
+
+func (buf *Buffer) ReadSQLiteVarint() uint64 {
+	v, n := GetSQLiteVarint(buf.Data[buf.ReadPos:])
+	buf.skipVarint(n)
+	return v
+}
+
+func (buf *Buffer) ReadVLQ() uint64 {
+	v, n := GetVLQ(buf.Data[buf.ReadPos:])
+	buf.skipVarint(n)
+	return v
+}
+
+func (buf *Buffer) ReadGitVarint() uint64 {
+	v, n := GetGitVarint(buf.Data[buf.ReadPos:])
+	buf.skipVarint(n)
+	return v
+}
+
+func (buf *Buffer) ReadUvarint128() Uint128 {
+	v, n := GetUvarint128(buf.Data[buf.ReadPos:])
+	buf.skipVarint(n)
+	return v
+}
+
+func (buf *Buffer) ReadVarint128() Int128 {
+	v, n := GetVarint128(buf.Data[buf.ReadPos:])
+	buf.skipVarint(n)
 	return v
 }
 
@@ -163,6 +262,102 @@ func (buf *Buffer) ReadFloat64LE() (v float64) {
 	return
 }
 
+func (buf *Buffer) ReadComplex64BE() (v complex64) {
+	v = GetComplex64BE(buf.Data[buf.ReadPos:])
+	buf.ReadPos += 8
+	return
+}
+
+func (buf *Buffer) ReadComplex64LE() (v complex64) {
+	v = GetComplex64LE(buf.Data[buf.ReadPos:])
+	buf.ReadPos += 8
+	return
+}
+
+func (buf *Buffer) ReadComplex128BE() (v complex128) {
+	v = GetComplex128BE(buf.Data[buf.ReadPos:])
+	buf.ReadPos += 16
+	return
+}
+
+func (buf *Buffer) ReadComplex128LE() (v complex128) {
+	v = GetComplex128LE(buf.Data[buf.ReadPos:])
+	buf.ReadPos += 16
+	return
+}
+
+func (buf *Buffer) ReadFloat80BE() (v float64) {
+	v = GetFloat80BE(buf.Data[buf.ReadPos:])
+	buf.ReadPos += 10
+	return
+}
+
+func (buf *Buffer) ReadFloat80LE() (v float64) {
+	v = GetFloat80LE(buf.Data[buf.ReadPos:])
+	buf.ReadPos += 10
+	return
+}
+
+func (buf *Buffer) ReadIBMFloat32BE() (v float64) {
+	v = GetIBMFloat32BE(buf.Data[buf.ReadPos:])
+	buf.ReadPos += 4
+	return
+}
+
+func (buf *Buffer) ReadIBMFloat64BE() (v float64) {
+	v = GetIBMFloat64BE(buf.Data[buf.ReadPos:])
+	buf.ReadPos += 8
+	return
+}
+
+func (buf *Buffer) ReadUint128BE() (v Uint128) {
+	v = GetUint128BE(buf.Data[buf.ReadPos:])
+	buf.ReadPos += 16
+	return
+}
+
+func (buf *Buffer) ReadUint128LE() (v Uint128) {
+	v = GetUint128LE(buf.Data[buf.ReadPos:])
+	buf.ReadPos += 16
+	return
+}
+
+func (buf *Buffer) ReadInt128BE() (v Int128) {
+	v = GetInt128BE(buf.Data[buf.ReadPos:])
+	buf.ReadPos += 16
+	return
+}
+
+func (buf *Buffer) ReadInt128LE() (v Int128) {
+	v = GetInt128LE(buf.Data[buf.ReadPos:])
+	buf.ReadPos += 16
+	return
+}
+
+func (buf *Buffer) ReadFloat16BE() (v float32) {
+	v = GetFloat16BE(buf.Data[buf.ReadPos:])
+	buf.ReadPos += 2
+	return
+}
+
+func (buf *Buffer) ReadFloat16LE() (v float32) {
+	v = GetFloat16LE(buf.Data[buf.ReadPos:])
+	buf.ReadPos += 2
+	return
+}
+
+func (buf *Buffer) ReadBFloat16BE() (v float32) {
+	v = GetBFloat16BE(buf.Data[buf.ReadPos:])
+	buf.ReadPos += 2
+	return
+}
+
+func (buf *Buffer) ReadBFloat16LE() (v float32) {
+	v = GetBFloat16LE(buf.Data[buf.ReadPos:])
+	buf.ReadPos += 2
+	return
+}
+
 func (buf *Buffer) ReadInt8() int8     { return int8(buf.ReadUint8()) }
 func (buf *Buffer) ReadInt16BE() int16 { return int16(buf.ReadUint16BE()) }
 func (buf *Buffer) ReadInt16LE() int16 { return int16(buf.ReadUint16LE()) }
@@ -214,6 +409,60 @@ func (buf *Buffer) WriteString(s string) {
 	}
 }
 
+func (buf *Buffer) WriteBCD(nDigits int, v uint64) {
+	n := BCDSize(nDigits)
+	if err := PutBCD(buf.Data[buf.WritePos:buf.WritePos+n], nDigits, v); err != nil {
+		panic(err)
+	}
+	buf.WritePos += n
+}
+
+func (buf *Buffer) WritePackedDecimal(nDigits int, v int64) {
+	n := PackedDecimalSize(nDigits)
+	if err := PutPackedDecimal(buf.Data[buf.WritePos:buf.WritePos+n], nDigits, v); err != nil {
+		panic(err)
+	}
+	buf.WritePos += n
+}
+
+func (buf *Buffer) WriteIPv4(ip net.IP) {
+	ip4 := ip.To4()
+	if ip4 == nil {
+		panic(ErrInvalidAddr)
+	}
+	buf.WriteBytes(ip4)
+}
+
+func (buf *Buffer) WriteIPv6(ip net.IP) {
+	ip16 := ip.To16()
+	if ip16 == nil {
+		panic(ErrInvalidAddr)
+	}
+	buf.WriteBytes(ip16)
+}
+
+func (buf *Buffer) WriteMAC(mac net.HardwareAddr) {
+	if len(mac) != MACLen {
+		panic(ErrInvalidAddr)
+	}
+	buf.WriteBytes(mac)
+}
+
+func (buf *Buffer) WriteUUID(u UUID) {
+	buf.WriteBytes(u[:])
+}
+
+func (buf *Buffer) WriteGUID(u UUID) {
+	PutGUID(buf.Data[buf.WritePos:buf.WritePos+UUIDLen], u)
+	buf.WritePos += UUIDLen
+}
+
+func (buf *Buffer) WriteBools(v []bool, order BitOrder) {
+	size := BoolsSize(len(v))
+	PutBools(buf.Data[buf.WritePos:buf.WritePos+size], v, order)
+	buf.WritePos += size
+}
+
 func (buf *Buffer) WriteUvarint(v uint64) {
 	buf.WritePos += PutUvarint(buf.Data[buf.WritePos:], v)
 }
@@ -222,6 +471,30 @@ func (buf *Buffer) WriteVarint(v int64) {
 	buf.WritePos += PutVarint(buf.Data[buf.WritePos:], v)
 }
 
+func (buf *Buffer) WriteQuicVarint(v uint64) {
+	buf.WritePos += PutQuicVarint(buf.Data[buf.WritePos:], v)
+}
+
+func (buf *Buffer) WriteSQLiteVarint(v uint64) {
+	buf.WritePos += PutSQLiteVarint(buf.Data[buf.WritePos:], v)
+}
+
+func (buf *Buffer) WriteVLQ(v uint64) {
+	buf.WritePos += PutVLQ(buf.Data[buf.WritePos:], v)
+}
+
+func (buf *Buffer) WriteGitVarint(v uint64) {
+	buf.WritePos += PutGitVarint(buf.Data[buf.WritePos:], v)
+}
+
+func (buf *Buffer) WriteUvarint128(v Uint128) {
+	buf.WritePos += PutUvarint128(buf.Data[buf.WritePos:], v)
+}
+
+func (buf *Buffer) WriteVarint128(v Int128) {
+	buf.WritePos += PutVarint128(buf.Data[buf.WritePos:], v)
+}
+
 func (buf *Buffer) WriteUint8(v uint8) {
 	buf.Data[buf.WritePos] = byte(v)
 	buf.WritePos += 1
@@ -316,6 +589,86 @@ func (buf *Buffer) WriteFloat64LE(v float64) {
 	buf.WritePos += 8
 }
 
+func (buf *Buffer) WriteComplex64BE(v complex64) {
+	PutComplex64BE(buf.Data[buf.WritePos:], v)
+	buf.WritePos += 8
+}
+
+func (buf *Buffer) WriteComplex64LE(v complex64) {
+	PutComplex64LE(buf.Data[buf.WritePos:], v)
+	buf.WritePos += 8
+}
+
+func (buf *Buffer) WriteComplex128BE(v complex128) {
+	PutComplex128BE(buf.Data[buf.WritePos:], v)
+	buf.WritePos += 16
+}
+
+func (buf *Buffer) WriteComplex128LE(v complex128) {
+	PutComplex128LE(buf.Data[buf.WritePos:], v)
+	buf.WritePos += 16
+}
+
+func (buf *Buffer) WriteFloat80BE(v float64) {
+	PutFloat80BE(buf.Data[buf.WritePos:], v)
+	buf.WritePos += 10
+}
+
+func (buf *Buffer) WriteFloat80LE(v float64) {
+	PutFloat80LE(buf.Data[buf.WritePos:], v)
+	buf.WritePos += 10
+}
+
+func (buf *Buffer) WriteIBMFloat32BE(v float64) {
+	PutIBMFloat32BE(buf.Data[buf.WritePos:], v)
+	buf.WritePos += 4
+}
+
+func (buf *Buffer) WriteIBMFloat64BE(v float64) {
+	PutIBMFloat64BE(buf.Data[buf.WritePos:], v)
+	buf.WritePos += 8
+}
+
+func (buf *Buffer) WriteUint128BE(v Uint128) {
+	PutUint128BE(buf.Data[buf.WritePos:], v)
+	buf.WritePos += 16
+}
+
+func (buf *Buffer) WriteUint128LE(v Uint128) {
+	PutUint128LE(buf.Data[buf.WritePos:], v)
+	buf.WritePos += 16
+}
+
+func (buf *Buffer) WriteInt128BE(v Int128) {
+	PutInt128BE(buf.Data[buf.WritePos:], v)
+	buf.WritePos += 16
+}
+
+func (buf *Buffer) WriteInt128LE(v Int128) {
+	PutInt128LE(buf.Data[buf.WritePos:], v)
+	buf.WritePos += 16
+}
+
+func (buf *Buffer) WriteFloat16BE(v float32) {
+	PutFloat16BE(buf.Data[buf.WritePos:], v)
+	buf.WritePos += 2
+}
+
+func (buf *Buffer) WriteFloat16LE(v float32) {
+	PutFloat16LE(buf.Data[buf.WritePos:], v)
+	buf.WritePos += 2
+}
+
+func (buf *Buffer) WriteBFloat16BE(v float32) {
+	PutBFloat16BE(buf.Data[buf.WritePos:], v)
+	buf.WritePos += 2
+}
+
+func (buf *Buffer) WriteBFloat16LE(v float32) {
+	PutBFloat16LE(buf.Data[buf.WritePos:], v)
+	buf.WritePos += 2
+}
+
 func (buf *Buffer) WriteInt8(v int8)     { buf.WriteUint8(uint8(v)) }
 func (buf *Buffer) WriteInt16BE(v int16) { buf.WriteUint16BE(uint16(v)) }
 func (buf *Buffer) WriteInt16LE(v int16) { buf.WriteUint16LE(uint16(v)) }
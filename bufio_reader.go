@@ -5,6 +5,7 @@ package binary
 import (
 	"bufio"
 	"io"
+	"net"
 )
 
 var _ BinaryReader = (*bufioReader)(nil)
@@ -113,6 +114,50 @@ func (br *bufioReader) ReadString(n int) string {
 	return ""
 }
 
+func (br *bufioReader) ReadBCD(nDigits int) (v uint64) {
+	if br.err == nil {
+		b := br.ReadBytes(BCDSize(nDigits))
+		if br.err == nil {
+			v, br.err = GetBCD(b, nDigits)
+		}
+	}
+	return
+}
+
+func (br *bufioReader) ReadPackedDecimal(nDigits int) (v int64) {
+	if br.err == nil {
+		b := br.ReadBytes(PackedDecimalSize(nDigits))
+		if br.err == nil {
+			v, br.err = GetPackedDecimal(b, nDigits)
+		}
+	}
+	return
+}
+
+func (br *bufioReader) ReadIPv4() net.IP {
+	return net.IP(br.ReadBytes(net.IPv4len))
+}
+
+func (br *bufioReader) ReadIPv6() net.IP {
+	return net.IP(br.ReadBytes(net.IPv6len))
+}
+
+func (br *bufioReader) ReadMAC() net.HardwareAddr {
+	return net.HardwareAddr(br.ReadBytes(MACLen))
+}
+
+func (br *bufioReader) ReadUUID() UUID {
+	return GetUUID(br.ReadBytes(UUIDLen))
+}
+
+func (br *bufioReader) ReadGUID() UUID {
+	return GetGUID(br.ReadBytes(UUIDLen))
+}
+
+func (br *bufioReader) ReadBools(n int, order BitOrder) []bool {
+	return GetBools(br.ReadBytes(BoolsSize(n)), n, order)
+}
+
 func (br *bufioReader) ReadUvarint() uint64 {
 	v, err := ReadUvarint(br)
 	if err != nil {
@@ -129,6 +174,54 @@ func (br *bufioReader) ReadVarint() int64 {
 	return v
 }
 
+func (br *bufioReader) ReadQuicVarint() uint64 {
+	v, err := ReadQuicVarint(br)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+func (br *bufioReader) ReadSQLiteVarint() uint64 {
+	v, err := ReadSQLiteVarint(br)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+func (br *bufioReader) ReadVLQ() uint64 {
+	v, err := ReadVLQ(br)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+func (br *bufioReader) ReadGitVarint() uint64 {
+	v, err := ReadGitVarint(br)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+func (br *bufioReader) ReadUvarint128() Uint128 {
+	v, err := ReadUvarint128(br)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+func (br *bufioReader) ReadVarint128() Int128 {
+	v, err := ReadVarint128(br)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
 func (br *bufioReader) ReadUint8() uint8 {
 	return uint8(br.readForward(1)[0])
 }
@@ -205,6 +298,70 @@ func (br *bufioReader) ReadFloat64LE() float64 {
 	return GetFloat64LE(br.readForward(8))
 }
 
+func (br *bufioReader) ReadComplex64BE() complex64 {
+	return GetComplex64BE(br.readForward(8))
+}
+
+func (br *bufioReader) ReadComplex64LE() complex64 {
+	return GetComplex64LE(br.readForward(8))
+}
+
+func (br *bufioReader) ReadComplex128BE() complex128 {
+	return GetComplex128BE(br.readForward(16))
+}
+
+func (br *bufioReader) ReadComplex128LE() complex128 {
+	return GetComplex128LE(br.readForward(16))
+}
+
+func (br *bufioReader) ReadFloat80BE() float64 {
+	return GetFloat80BE(br.readForward(10))
+}
+
+func (br *bufioReader) ReadFloat80LE() float64 {
+	return GetFloat80LE(br.readForward(10))
+}
+
+func (br *bufioReader) ReadIBMFloat32BE() float64 {
+	return GetIBMFloat32BE(br.readForward(4))
+}
+
+func (br *bufioReader) ReadIBMFloat64BE() float64 {
+	return GetIBMFloat64BE(br.readForward(8))
+}
+
+func (br *bufioReader) ReadUint128BE() Uint128 {
+	return GetUint128BE(br.readForward(16))
+}
+
+func (br *bufioReader) ReadUint128LE() Uint128 {
+	return GetUint128LE(br.readForward(16))
+}
+
+func (br *bufioReader) ReadInt128BE() Int128 {
+	return GetInt128BE(br.readForward(16))
+}
+
+func (br *bufioReader) ReadInt128LE() Int128 {
+	return GetInt128LE(br.readForward(16))
+}
+
+func (br *bufioReader) ReadFloat16BE() float32 {
+	return GetFloat16BE(br.readForward(2))
+}
+
+func (br *bufioReader) ReadFloat16LE() float32 {
+	return GetFloat16LE(br.readForward(2))
+}
+
+func (br *bufioReader) ReadBFloat16BE() float32 {
+	return GetBFloat16BE(br.readForward(2))
+}
+
+func (br *bufioReader) ReadBFloat16LE() float32 {
+	return GetBFloat16LE(br.readForward(2))
+}
+
 func (br *bufioReader) ReadInt8() int8     { return int8(br.ReadUint8()) }
 func (br *bufioReader) ReadInt16BE() int16 { return int16(br.ReadUint16BE()) }
 func (br *bufioReader) ReadInt16LE() int16 { return int16(br.ReadUint16LE()) }
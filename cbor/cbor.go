@@ -0,0 +1,85 @@
+// Package cbor implements CBOR (RFC 8949) on top of the BinaryReader and
+// BinaryWriter interfaces of github.com/funny/binary.
+//
+// Structs are encoded as maps keyed by field name, the "cbor" struct tag
+// renames a field, "-" skips it and the "omitempty" option drops empty
+// values like encoding/json does.
+package cbor
+
+import (
+	"errors"
+	"math/big"
+	"reflect"
+	"time"
+)
+
+var (
+	ErrUnsupportedType = errors.New("funny/binary/cbor: unsupported type")
+	ErrInvalidCode     = errors.New("funny/binary/cbor: malformed data item")
+	ErrInvalidUTF8     = errors.New("funny/binary/cbor: text string is not valid UTF-8")
+	ErrTypeMismatch    = errors.New("funny/binary/cbor: value does not match target type")
+	ErrOverflow        = errors.New("funny/binary/cbor: value overflows target type")
+	ErrInvalidMapKey   = errors.New("funny/binary/cbor: map key is not comparable")
+	ErrTooLarge        = errors.New("funny/binary/cbor: length exceeds limit")
+	ErrTooDeep         = errors.New("funny/binary/cbor: nesting exceeds limit")
+	ErrNotPointer      = errors.New("funny/binary/cbor: decode target must be a non-nil pointer")
+)
+
+// Major types.
+const (
+	majorUint   = 0
+	majorNeg    = 1
+	majorBytes  = 2
+	majorText   = 3
+	majorArray  = 4
+	majorMap    = 5
+	majorTag    = 6
+	majorSimple = 7
+)
+
+// Additional information values.
+const (
+	info8          = 24
+	info16         = 25
+	info32         = 26
+	info64         = 27
+	infoIndefinite = 31
+)
+
+const (
+	codeBreak = 0xff
+	nanHalf   = 0x7e00
+)
+
+// Tag numbers with built in support.
+const (
+	TagDateTime     = 0
+	TagEpochTime    = 1
+	TagPosBignum    = 2
+	TagNegBignum    = 3
+	TagSelfDescribe = 55799
+)
+
+// DefaultMaxLen limits strings and the element count of arrays and maps
+// when Decoder.MaxLen is zero.
+const DefaultMaxLen = 16 << 20
+
+const maxDepth = 1000
+
+// Tag is a tagged data item without built in support.
+type Tag struct {
+	Number  uint64
+	Content interface{}
+}
+
+// Simple is a simple value other than false, true and null.
+type Simple uint8
+
+const Undefined Simple = 23
+
+var (
+	timeType   = reflect.TypeOf(time.Time{})
+	bigIntType = reflect.TypeOf(big.Int{})
+	tagType    = reflect.TypeOf(Tag{})
+	simpleType = reflect.TypeOf(Simple(0))
+)
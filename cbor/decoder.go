@@ -0,0 +1,760 @@
+package cbor
+
+import (
+	"math"
+	"math/big"
+	"reflect"
+	"time"
+	"unicode/utf8"
+
+	"github.com/funny/binary"
+	"github.com/funny/binary/internal/structs"
+)
+
+// Decoder reads CBOR data items. Errors of the BinaryReader are returned
+// as they are, the decoder must not be used after an error.
+type Decoder struct {
+	r      binary.BinaryReader
+	peeked bool
+	peek   byte
+	depth  int
+
+	// MaxLen limits string lengths and the element count of definite
+	// length arrays and maps, zero means DefaultMaxLen.
+	MaxLen int
+}
+
+func NewDecoder(r binary.BinaryReader) *Decoder {
+	return &Decoder{r: r}
+}
+
+// head is the initial byte of a data item and its argument. For floats
+// the argument holds their bits.
+type head struct {
+	major byte
+	info  byte
+	arg   uint64
+}
+
+func (h head) isBreak() bool {
+	return h.major == majorSimple && h.info == infoIndefinite
+}
+
+func (h head) isNull() bool {
+	return h.major == majorSimple && (h.info == 22 || h.info == 23)
+}
+
+func (d *Decoder) readByte() (byte, error) {
+	if d.peeked {
+		d.peeked = false
+		return d.peek, nil
+	}
+	return d.r.ReadByte()
+}
+
+func (d *Decoder) readHead() (h head, err error) {
+	b, err := d.readByte()
+	if err != nil {
+		return
+	}
+	h.major, h.info = b>>5, b&0x1f
+	switch {
+	case h.info < info8:
+		h.arg = uint64(h.info)
+	case h.info == info8:
+		h.arg = uint64(d.r.ReadUint8())
+	case h.info == info16:
+		h.arg = uint64(d.r.ReadUint16BE())
+	case h.info == info32:
+		h.arg = uint64(d.r.ReadUint32BE())
+	case h.info == info64:
+		h.arg = d.r.ReadUint64BE()
+	case h.info == infoIndefinite:
+		if h.major == majorUint || h.major == majorNeg || h.major == majorTag {
+			return h, ErrInvalidCode
+		}
+	default:
+		return h, ErrInvalidCode
+	}
+	return h, d.r.Error()
+}
+
+func (d *Decoder) length(arg uint64) (int, error) {
+	max := DefaultMaxLen
+	if d.MaxLen > 0 {
+		max = d.MaxLen
+	}
+	if arg > uint64(max) {
+		return 0, ErrTooLarge
+	}
+	return int(arg), nil
+}
+
+// count returns -1 for indefinite length items.
+func (d *Decoder) count(h head) (int, error) {
+	if h.info == infoIndefinite {
+		return -1, nil
+	}
+	return d.length(h.arg)
+}
+
+func (d *Decoder) enter() error {
+	d.depth++
+	if d.depth > maxDepth {
+		return ErrTooDeep
+	}
+	return nil
+}
+
+// initialCap keeps a forged element count from allocating up front.
+func initialCap(n int) int {
+	if n > 1024 {
+		return 1024
+	}
+	if n < 0 {
+		return 0
+	}
+	return n
+}
+
+// DecodeArrayLen reads the header of an array. It returns -1 for an
+// indefinite length array, whose items are read until More reports false,
+// so large arrays can be decoded one item at a time.
+func (d *Decoder) DecodeArrayLen() (int, error) {
+	h, err := d.readHead()
+	if err != nil {
+		return 0, err
+	}
+	if h.major != majorArray {
+		return 0, ErrTypeMismatch
+	}
+	return d.count(h)
+}
+
+// DecodeMapLen reads the header of a map, like DecodeArrayLen.
+func (d *Decoder) DecodeMapLen() (int, error) {
+	h, err := d.readHead()
+	if err != nil {
+		return 0, err
+	}
+	if h.major != majorMap {
+		return 0, ErrTypeMismatch
+	}
+	return d.count(h)
+}
+
+// More reports whether an indefinite length item has more items, it
+// consumes the break that ends the item.
+func (d *Decoder) More() (bool, error) {
+	b, err := d.readByte()
+	if err != nil || b == codeBreak {
+		return false, err
+	}
+	d.peeked, d.peek = true, b
+	return true, nil
+}
+
+// DecodeInterface reads the next data item as nil, bool, int64, uint64
+// (only for values above math.MaxInt64), *big.Int (for bignums and
+// integers below math.MinInt64), float32 (for half and single precision),
+// float64, string, []byte, time.Time, Tag, Simple, []interface{}, or a
+// map[string]interface{} when every key is a string and
+// map[interface{}]interface{} otherwise. Indefinite length strings are
+// joined.
+func (d *Decoder) DecodeInterface() (interface{}, error) {
+	h, err := d.readHead()
+	if err != nil {
+		return nil, err
+	}
+	return d.decodeInterface(h)
+}
+
+func (d *Decoder) decodeInterface(h head) (interface{}, error) {
+	switch h.major {
+	case majorUint:
+		if h.arg > math.MaxInt64 {
+			return h.arg, nil
+		}
+		return int64(h.arg), nil
+	case majorNeg:
+		if h.arg > math.MaxInt64 {
+			return new(big.Int).Not(new(big.Int).SetUint64(h.arg)), nil
+		}
+		return -1 - int64(h.arg), nil
+	case majorBytes:
+		return d.readString(h)
+	case majorText:
+		b, err := d.readString(h)
+		if err != nil {
+			return nil, err
+		}
+		if !utf8.Valid(b) {
+			return nil, ErrInvalidUTF8
+		}
+		return string(b), nil
+	case majorArray:
+		return d.decodeArray(h)
+	case majorMap:
+		return d.decodeMap(h)
+	case majorTag:
+		return d.decodeTag(h.arg)
+	}
+	switch h.info {
+	case 20:
+		return false, nil
+	case 21:
+		return true, nil
+	case 22:
+		return nil, nil
+	case info16:
+		return binary.Float16ToFloat32(uint16(h.arg)), nil
+	case info32:
+		return math.Float32frombits(uint32(h.arg)), nil
+	case info64:
+		return math.Float64frombits(h.arg), nil
+	case infoIndefinite:
+		return nil, ErrInvalidCode
+	}
+	if h.info == info8 && h.arg < 32 {
+		return nil, ErrInvalidCode
+	}
+	return Simple(h.arg), nil
+}
+
+func (d *Decoder) readString(h head) ([]byte, error) {
+	if h.info != infoIndefinite {
+		n, err := d.length(h.arg)
+		if err != nil {
+			return nil, err
+		}
+		return d.r.ReadBytes(n), d.r.Error()
+	}
+	b := []byte{}
+	for {
+		c, err := d.readHead()
+		if err != nil {
+			return nil, err
+		}
+		if c.isBreak() {
+			return b, nil
+		}
+		if c.major != h.major || c.info == infoIndefinite {
+			return nil, ErrInvalidCode
+		}
+		n, err := d.length(c.arg)
+		if err == nil {
+			_, err = d.length(uint64(len(b) + n))
+		}
+		if err != nil {
+			return nil, err
+		}
+		b = append(b, d.r.ReadBytes(n)...)
+		if err := d.r.Error(); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// next reads the head of item i of an item with n items, it reports false
+// at the break of an indefinite length item.
+func (d *Decoder) next(i, n int) (head, bool, error) {
+	if n >= 0 && i >= n {
+		return head{}, false, nil
+	}
+	h, err := d.readHead()
+	if err != nil {
+		return h, false, err
+	}
+	if n < 0 && h.isBreak() {
+		return h, false, nil
+	}
+	return h, true, nil
+}
+
+func (d *Decoder) decodeArray(h head) (interface{}, error) {
+	n, err := d.count(h)
+	if err != nil {
+		return nil, err
+	}
+	if err := d.enter(); err != nil {
+		return nil, err
+	}
+	defer func() { d.depth-- }()
+	a := make([]interface{}, 0, initialCap(n))
+	for i := 0; ; i++ {
+		h, ok, err := d.next(i, n)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return a, nil
+		}
+		v, err := d.decodeInterface(h)
+		if err != nil {
+			return nil, err
+		}
+		a = append(a, v)
+	}
+}
+
+func (d *Decoder) decodeMap(h head) (interface{}, error) {
+	n, err := d.count(h)
+	if err != nil {
+		return nil, err
+	}
+	if err := d.enter(); err != nil {
+		return nil, err
+	}
+	defer func() { d.depth-- }()
+	m := make(map[string]interface{}, initialCap(n))
+	var mi map[interface{}]interface{}
+	for i := 0; ; i++ {
+		h, ok, err := d.next(i, n)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			break
+		}
+		k, err := d.decodeInterface(h)
+		if err != nil {
+			return nil, err
+		}
+		v, err := d.DecodeInterface()
+		if err != nil {
+			return nil, err
+		}
+		if s, ok := k.(string); ok && mi == nil {
+			m[s] = v
+			continue
+		}
+		if !hashable(reflect.ValueOf(k)) {
+			return nil, ErrInvalidMapKey
+		}
+		if mi == nil {
+			mi = make(map[interface{}]interface{}, len(m)+1)
+			for s, v := range m {
+				mi[s] = v
+			}
+		}
+		mi[k] = v
+	}
+	if mi != nil {
+		return mi, nil
+	}
+	return m, nil
+}
+
+func (d *Decoder) decodeTag(number uint64) (interface{}, error) {
+	if err := d.enter(); err != nil {
+		return nil, err
+	}
+	defer func() { d.depth-- }()
+	content, err := d.DecodeInterface()
+	if err != nil {
+		return nil, err
+	}
+	switch number {
+	case TagDateTime:
+		s, ok := content.(string)
+		if !ok {
+			return nil, ErrInvalidCode
+		}
+		t, err := time.Parse(time.RFC3339Nano, s)
+		if err != nil {
+			return nil, ErrInvalidCode
+		}
+		return t, nil
+	case TagEpochTime:
+		return epochTime(content)
+	case TagPosBignum, TagNegBignum:
+		b, ok := content.([]byte)
+		if !ok {
+			return nil, ErrInvalidCode
+		}
+		x := new(big.Int).SetBytes(b)
+		if number == TagNegBignum {
+			x.Not(x)
+		}
+		return x, nil
+	}
+	return Tag{number, content}, nil
+}
+
+func epochTime(content interface{}) (time.Time, error) {
+	var f float64
+	switch v := content.(type) {
+	case int64:
+		return time.Unix(v, 0).UTC(), nil
+	case float32:
+		f = float64(v)
+	case float64:
+		f = v
+	default:
+		return time.Time{}, ErrInvalidCode
+	}
+	if !(math.Abs(f) < 1<<63) {
+		return time.Time{}, ErrInvalidCode
+	}
+	sec := math.Floor(f)
+	nsec := math.Round((f - sec) * 1e9)
+	return time.Unix(int64(sec), int64(nsec)).UTC(), nil
+}
+
+// Decode reads the next data item into the value pointed to by v. Struct
+// fields are matched by their encoded name, unknown keys are skipped. Tags
+// are ignored unless v is a time.Time, big.Int or Tag.
+func (d *Decoder) Decode(v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return ErrNotPointer
+	}
+	return d.decodeNext(rv.Elem())
+}
+
+func (d *Decoder) decodeNext(v reflect.Value) error {
+	h, err := d.readHead()
+	if err != nil {
+		return err
+	}
+	return d.decodeValue(h, v)
+}
+
+func (d *Decoder) decodeValue(h head, v reflect.Value) error {
+	if h.isNull() {
+		v.Set(reflect.Zero(v.Type()))
+		return nil
+	}
+	switch v.Type() {
+	case tagType:
+		if h.major != majorTag {
+			return ErrTypeMismatch
+		}
+		content, err := d.DecodeInterface()
+		v.Set(reflect.ValueOf(Tag{h.arg, content}))
+		return err
+	case timeType:
+		x, err := d.decodeInterface(h)
+		if err != nil {
+			return err
+		}
+		if t, ok := x.(time.Time); ok {
+			v.Set(reflect.ValueOf(t))
+			return nil
+		}
+		return ErrTypeMismatch
+	case bigIntType:
+		x, err := d.decodeInterface(h)
+		if err != nil {
+			return err
+		}
+		switch x := x.(type) {
+		case *big.Int:
+			v.Set(reflect.ValueOf(x).Elem())
+		case int64:
+			v.Set(reflect.ValueOf(big.NewInt(x)).Elem())
+		case uint64:
+			v.Set(reflect.ValueOf(new(big.Int).SetUint64(x)).Elem())
+		default:
+			return ErrTypeMismatch
+		}
+		return nil
+	}
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		return d.decodeValue(h, v.Elem())
+	case reflect.Interface:
+		if v.NumMethod() == 0 {
+			x, err := d.decodeInterface(h)
+			if err != nil {
+				return err
+			}
+			if x == nil {
+				v.Set(reflect.Zero(v.Type()))
+			} else {
+				v.Set(reflect.ValueOf(x))
+			}
+			return nil
+		}
+		if !v.IsNil() && v.Elem().Kind() == reflect.Ptr {
+			return d.decodeValue(h, v.Elem())
+		}
+		return ErrTypeMismatch
+	}
+	if h.major == majorTag {
+		if err := d.enter(); err != nil {
+			return err
+		}
+		defer func() { d.depth-- }()
+		return d.decodeNext(v)
+	}
+	switch v.Kind() {
+	case reflect.Slice, reflect.Array:
+		if v.Type().Elem().Kind() != reflect.Uint8 {
+			return d.decodeSlice(h, v)
+		}
+	case reflect.Map:
+		return d.decodeMapValue(h, v)
+	case reflect.Struct:
+		return d.decodeStruct(h, v)
+	}
+	if h.major == majorArray || h.major == majorMap {
+		return ErrTypeMismatch
+	}
+	x, err := d.decodeInterface(h)
+	if err != nil {
+		return err
+	}
+	return setScalar(v, x)
+}
+
+func setScalar(v reflect.Value, x interface{}) error {
+	switch v.Kind() {
+	case reflect.Bool:
+		if b, ok := x.(bool); ok {
+			v.SetBool(b)
+			return nil
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		switch n := x.(type) {
+		case int64:
+			if v.OverflowInt(n) {
+				return ErrOverflow
+			}
+			v.SetInt(n)
+			return nil
+		case uint64, *big.Int:
+			return ErrOverflow
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		switch n := x.(type) {
+		case int64:
+			if n < 0 || v.OverflowUint(uint64(n)) {
+				return ErrOverflow
+			}
+			v.SetUint(uint64(n))
+			return nil
+		case uint64:
+			if v.OverflowUint(n) {
+				return ErrOverflow
+			}
+			v.SetUint(n)
+			return nil
+		case Simple:
+			v.SetUint(uint64(n))
+			return nil
+		case *big.Int:
+			return ErrOverflow
+		}
+	case reflect.Float32, reflect.Float64:
+		switch n := x.(type) {
+		case float32:
+			v.SetFloat(float64(n))
+			return nil
+		case float64:
+			v.SetFloat(n)
+			return nil
+		case int64:
+			v.SetFloat(float64(n))
+			return nil
+		case uint64:
+			v.SetFloat(float64(n))
+			return nil
+		}
+	case reflect.String:
+		switch s := x.(type) {
+		case string:
+			v.SetString(s)
+			return nil
+		case []byte:
+			v.SetString(string(s))
+			return nil
+		}
+	case reflect.Slice:
+		switch s := x.(type) {
+		case string:
+			v.SetBytes([]byte(s))
+			return nil
+		case []byte:
+			v.SetBytes(s)
+			return nil
+		}
+	case reflect.Array:
+		var b []byte
+		switch s := x.(type) {
+		case string:
+			b = []byte(s)
+		case []byte:
+			b = s
+		default:
+			return ErrTypeMismatch
+		}
+		if len(b) != v.Len() {
+			return ErrTypeMismatch
+		}
+		reflect.Copy(v, reflect.ValueOf(b))
+		return nil
+	}
+	return ErrTypeMismatch
+}
+
+func (d *Decoder) decodeSlice(h head, v reflect.Value) error {
+	if h.major != majorArray {
+		return ErrTypeMismatch
+	}
+	n, err := d.count(h)
+	if err != nil {
+		return err
+	}
+	if err := d.enter(); err != nil {
+		return err
+	}
+	defer func() { d.depth-- }()
+	t := v.Type()
+	if v.Kind() == reflect.Array {
+		i := 0
+		for ; ; i++ {
+			h, ok, err := d.next(i, n)
+			if err != nil {
+				return err
+			}
+			if !ok {
+				break
+			}
+			if i >= v.Len() {
+				return ErrTypeMismatch
+			}
+			if err := d.decodeValue(h, v.Index(i)); err != nil {
+				return err
+			}
+		}
+		for ; i < v.Len(); i++ {
+			v.Index(i).Set(reflect.Zero(t.Elem()))
+		}
+		return nil
+	}
+	s := reflect.MakeSlice(t, 0, initialCap(n))
+	for i := 0; ; i++ {
+		h, ok, err := d.next(i, n)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			break
+		}
+		elem := reflect.New(t.Elem()).Elem()
+		if err := d.decodeValue(h, elem); err != nil {
+			return err
+		}
+		s = reflect.Append(s, elem)
+	}
+	v.Set(s)
+	return nil
+}
+
+func (d *Decoder) decodeMapValue(h head, v reflect.Value) error {
+	if h.major != majorMap {
+		return ErrTypeMismatch
+	}
+	n, err := d.count(h)
+	if err != nil {
+		return err
+	}
+	if err := d.enter(); err != nil {
+		return err
+	}
+	defer func() { d.depth-- }()
+	t := v.Type()
+	if v.IsNil() {
+		v.Set(reflect.MakeMapWithSize(t, initialCap(n)))
+	}
+	for i := 0; ; i++ {
+		h, ok, err := d.next(i, n)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return nil
+		}
+		key := reflect.New(t.Key()).Elem()
+		if err := d.decodeValue(h, key); err != nil {
+			return err
+		}
+		if !hashable(key) {
+			return ErrInvalidMapKey
+		}
+		elem := reflect.New(t.Elem()).Elem()
+		if err := d.decodeNext(elem); err != nil {
+			return err
+		}
+		v.SetMapIndex(key, elem)
+	}
+}
+
+// hashable reports whether v can be a map key. Unlike Type.Comparable it
+// looks at the dynamic values inside interfaces, such as the Content of a
+// Tag.
+func hashable(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Invalid:
+		return true
+	case reflect.Interface:
+		return v.IsNil() || hashable(v.Elem())
+	case reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			if !hashable(v.Index(i)) {
+				return false
+			}
+		}
+		return true
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if !hashable(v.Field(i)) {
+				return false
+			}
+		}
+		return true
+	}
+	return v.Type().Comparable()
+}
+
+func (d *Decoder) decodeStruct(h head, v reflect.Value) error {
+	if h.major != majorMap {
+		return ErrTypeMismatch
+	}
+	n, err := d.count(h)
+	if err != nil {
+		return err
+	}
+	if err := d.enter(); err != nil {
+		return err
+	}
+	defer func() { d.depth-- }()
+	fields := structs.Fields(v.Type(), "cbor")
+	for i := 0; ; i++ {
+		h, ok, err := d.next(i, n)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return nil
+		}
+		var name string
+		if err := d.decodeValue(h, reflect.ValueOf(&name).Elem()); err != nil {
+			return err
+		}
+		f := structs.Find(fields, name)
+		if f == nil {
+			if _, err := d.DecodeInterface(); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := d.decodeNext(v.FieldByIndex(f.Index)); err != nil {
+			return err
+		}
+	}
+}
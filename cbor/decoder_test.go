@@ -0,0 +1,202 @@
+package cbor
+
+import (
+	"bytes"
+	"math"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/funny/binary"
+	"github.com/funny/utest"
+)
+
+func newDecoder(b []byte) *Decoder {
+	return NewDecoder(binary.NewReader(bytes.NewReader(b)))
+}
+
+func decodeHex(t *testing.T, s string) interface{} {
+	v, err := newDecoder(unhex(s)).DecodeInterface()
+	utest.IsNilNow(t, err)
+	return v
+}
+
+// Examples from RFC 8949 appendix A.
+func Test_Decoder_Vectors(t *testing.T) {
+	for _, c := range []struct {
+		hex string
+		v   interface{}
+	}{
+		{"00", int64(0)},
+		{"1bffffffffffffffff", uint64(math.MaxUint64)},
+		{"3bffffffffffffffff", bigInt("-18446744073709551616")},
+		{"c249010000000000000000", bigInt("18446744073709551616")},
+		{"c349010000000000000000", bigInt("-18446744073709551617")},
+		{"3903e7", int64(-1000)},
+		{"f90000", float32(0)},
+		{"f93c00", float32(1)},
+		{"f97bff", float32(65504)},
+		{"f90001", float32(5.960464477539063e-8)},
+		{"f90400", float32(0.00006103515625)},
+		{"f9c400", float32(-4)},
+		{"f97c00", float32(math.Inf(1))},
+		{"fa47c35000", float32(100000)},
+		{"fb7e37e43c8800759c", 1.0e+300},
+		{"f4", false},
+		{"f6", nil},
+		{"f7", Undefined},
+		{"f0", Simple(16)},
+		{"f8ff", Simple(255)},
+		{"c074323031332d30332d32315432303a30343a30305a", time.Date(2013, 3, 21, 20, 4, 0, 0, time.UTC)},
+		{"c11a514b67b0", time.Unix(1363896240, 0).UTC()},
+		{"c1fb41d452d9ec200000", time.Unix(1363896240, 5e8).UTC()},
+		{"d74401020304", Tag{23, []byte{1, 2, 3, 4}}},
+		{"d818456449455446", Tag{24, []byte("dIETF")}},
+		{"4401020304", []byte{1, 2, 3, 4}},
+		{"62225c", "\"\\"},
+		{"64f0908591", "\U00010151"},
+		{"8301820203820405", []interface{}{int64(1), []interface{}{int64(2), int64(3)}, []interface{}{int64(4), int64(5)}}},
+		{"a201020304", map[interface{}]interface{}{int64(1): int64(2), int64(3): int64(4)}},
+		{"a26161016162820203", map[string]interface{}{"a": int64(1), "b": []interface{}{int64(2), int64(3)}}},
+		{"5f42010243030405ff", []byte{1, 2, 3, 4, 5}},
+		{"7f657374726561646d696e67ff", "streaming"},
+		{"9f018202039f0405ffff", []interface{}{int64(1), []interface{}{int64(2), int64(3)}, []interface{}{int64(4), int64(5)}}},
+		{"83018202039f0405ff", []interface{}{int64(1), []interface{}{int64(2), int64(3)}, []interface{}{int64(4), int64(5)}}},
+		{"bf61610161629f0203ffff", map[string]interface{}{"a": int64(1), "b": []interface{}{int64(2), int64(3)}}},
+		{"bf6346756ef563416d7421ff", map[string]interface{}{"Fun": true, "Amt": int64(-2)}},
+	} {
+		v := decodeHex(t, c.hex)
+		if x, ok := c.v.(*big.Int); ok {
+			utest.EqualNow(t, v.(*big.Int).Cmp(x), 0)
+			continue
+		}
+		utest.EqualNow(t, v, c.v)
+	}
+	f := decodeHex(t, "f97e00").(float32)
+	utest.EqualNow(t, f != f, true)
+}
+
+type inner struct {
+	X float64
+	T time.Time
+}
+
+type embedded struct {
+	E int `cbor:"e"`
+}
+
+type record struct {
+	embedded
+	ID     uint16            `cbor:"id"`
+	Name   string            `cbor:"name,omitempty"`
+	Tags   []string          `cbor:"tags"`
+	Attrs  map[string]int    `cbor:"attrs"`
+	Inner  *inner            `cbor:"inner"`
+	Raw    [4]byte           `cbor:"raw"`
+	Big    *big.Int          `cbor:"big"`
+	Any    interface{}       `cbor:"any"`
+	Nested map[int][]float32 `cbor:"nested"`
+	Tag    Tag               `cbor:"tag"`
+}
+
+func Test_Decoder_Struct(t *testing.T) {
+	v := record{
+		embedded: embedded{42},
+		ID:       7,
+		Name:     "n",
+		Tags:     []string{"a", "b"},
+		Attrs:    map[string]int{"x": -1},
+		Inner:    &inner{2.5, time.Unix(5, 0).UTC()},
+		Raw:      [4]byte{1, 2, 3, 4},
+		Big:      bigInt("-100000000000000000000000"),
+		Any:      "any",
+		Nested:   map[int][]float32{1: {0.5}},
+		Tag:      Tag{100, "x"},
+	}
+	for _, canonical := range []bool{false, true} {
+		var v2 record
+		utest.IsNilNow(t, newDecoder(encode(t, &v, canonical)).Decode(&v2))
+		utest.EqualNow(t, v2.Big.Cmp(v.Big), 0)
+		v2.Big = v.Big
+		utest.EqualNow(t, v2, v)
+	}
+
+	// Unknown keys are skipped, unknown tags are transparent and nil
+	// clears pointers.
+	var v2 record
+	b := unhex("bf626964d82007647461677381d820616167756e6b6e6f776e9f01ff65696e6e6572f6ff")
+	utest.IsNilNow(t, newDecoder(b).Decode(&v2))
+	utest.EqualNow(t, v2.ID, uint16(7))
+	utest.EqualNow(t, v2.Tags, []string{"a"})
+	utest.IsNilNow(t, v2.Inner)
+}
+
+func Test_Decoder_Stream(t *testing.T) {
+	var buf bytes.Buffer
+	e := NewEncoder(binary.NewWriter(&buf))
+	e.EncodeArrayStart()
+	for i := 0; i < 1000; i++ {
+		e.EncodeUint(uint64(i))
+	}
+	e.EncodeBreak()
+	e.EncodeString("end")
+
+	d := newDecoder(buf.Bytes())
+	n, err := d.DecodeArrayLen()
+	utest.IsNilNow(t, err)
+	utest.EqualNow(t, n, -1)
+	var sum int
+	for {
+		more, err := d.More()
+		utest.IsNilNow(t, err)
+		if !more {
+			break
+		}
+		var i int
+		utest.IsNilNow(t, d.Decode(&i))
+		sum += i
+	}
+	utest.EqualNow(t, sum, 999*1000/2)
+	var s string
+	utest.IsNilNow(t, d.Decode(&s))
+	utest.EqualNow(t, s, "end")
+}
+
+func Test_Decoder_Errors(t *testing.T) {
+	var i8 int8
+	utest.EqualNow(t, newDecoder(unhex("1880")).Decode(&i8), ErrOverflow)
+	var u uint
+	utest.EqualNow(t, newDecoder(unhex("20")).Decode(&u), ErrOverflow)
+	var s string
+	utest.EqualNow(t, newDecoder(unhex("01")).Decode(&s), ErrTypeMismatch)
+	utest.EqualNow(t, newDecoder(unhex("8101")).Decode(&s), ErrTypeMismatch)
+	utest.EqualNow(t, newDecoder(unhex("01")).Decode(s), ErrNotPointer)
+
+	for _, c := range []string{"1c", "1f", "ff", "f818", "5f01ff", "5f5f4100ffff", "c16161", "c26161"} {
+		_, err := newDecoder(unhex(c)).DecodeInterface()
+		utest.EqualNow(t, err, ErrInvalidCode)
+	}
+	_, err := newDecoder(unhex("62c328")).DecodeInterface()
+	utest.EqualNow(t, err, ErrInvalidUTF8)
+	_, err = newDecoder(unhex("a1810101")).DecodeInterface()
+	utest.EqualNow(t, err, ErrInvalidMapKey)
+	_, err = newDecoder(unhex("a1d863410101")).DecodeInterface()
+	utest.EqualNow(t, err, ErrInvalidMapKey)
+	utest.EqualNow(t, newDecoder(unhex("a1d863410101")).Decode(&map[interface{}]int{}), ErrInvalidMapKey)
+	_, err = newDecoder(unhex("1a00")).DecodeInterface()
+	utest.NotNilNow(t, err)
+
+	d := newDecoder(unhex("5a00001000"))
+	d.MaxLen = 1024
+	_, err = d.DecodeInterface()
+	utest.EqualNow(t, err, ErrTooLarge)
+	d = newDecoder(unhex("5f590300" + string(bytes.Repeat([]byte("00"), 0x300)) + "590300"))
+	d.MaxLen = 1024
+	_, err = d.DecodeInterface()
+	utest.EqualNow(t, err, ErrTooLarge)
+
+	_, err = newDecoder(bytes.Repeat([]byte{0x81}, maxDepth+1)).DecodeInterface()
+	utest.EqualNow(t, err, ErrTooDeep)
+	_, err = newDecoder(bytes.Repeat([]byte{0xc6}, maxDepth+1)).DecodeInterface()
+	utest.EqualNow(t, err, ErrTooDeep)
+}
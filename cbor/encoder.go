@@ -0,0 +1,333 @@
+package cbor
+
+import (
+	"bytes"
+	"math"
+	"math/big"
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/funny/binary"
+	"github.com/funny/binary/internal/structs"
+)
+
+// Encoder writes CBOR data items. Integers and lengths always take their
+// shortest form, write errors are left in the BinaryWriter.
+type Encoder struct {
+	w binary.BinaryWriter
+
+	// Canonical selects the core deterministic encoding of RFC 8949
+	// section 4.2.1: map keys are sorted by their encoded bytes and floats
+	// take the shortest form that keeps their value.
+	Canonical bool
+}
+
+func NewEncoder(w binary.BinaryWriter) *Encoder {
+	return &Encoder{w: w}
+}
+
+func (e *Encoder) writeHead(major byte, arg uint64) {
+	major <<= 5
+	switch {
+	case arg < info8:
+		e.w.WriteUint8(major | uint8(arg))
+	case arg <= math.MaxUint8:
+		e.w.WriteUint8(major | info8)
+		e.w.WriteUint8(uint8(arg))
+	case arg <= math.MaxUint16:
+		e.w.WriteUint8(major | info16)
+		e.w.WriteUint16BE(uint16(arg))
+	case arg <= math.MaxUint32:
+		e.w.WriteUint8(major | info32)
+		e.w.WriteUint32BE(uint32(arg))
+	default:
+		e.w.WriteUint8(major | info64)
+		e.w.WriteUint64BE(arg)
+	}
+}
+
+func (e *Encoder) EncodeUint(v uint64) {
+	e.writeHead(majorUint, v)
+}
+
+func (e *Encoder) EncodeInt(v int64) {
+	if v < 0 {
+		e.writeHead(majorNeg, uint64(-1-v))
+	} else {
+		e.writeHead(majorUint, uint64(v))
+	}
+}
+
+// EncodeBigInt writes v as an integer when it fits in 64 bits and as a
+// bignum tag otherwise.
+func (e *Encoder) EncodeBigInt(v *big.Int) {
+	if v.Sign() >= 0 {
+		if v.IsUint64() {
+			e.writeHead(majorUint, v.Uint64())
+			return
+		}
+		e.writeHead(majorTag, TagPosBignum)
+		e.EncodeBytes(v.Bytes())
+		return
+	}
+	m := new(big.Int).Not(v)
+	if m.IsUint64() {
+		e.writeHead(majorNeg, m.Uint64())
+		return
+	}
+	e.writeHead(majorTag, TagNegBignum)
+	e.EncodeBytes(m.Bytes())
+}
+
+func (e *Encoder) EncodeBytes(v []byte) {
+	e.writeHead(majorBytes, uint64(len(v)))
+	e.w.WriteBytes(v)
+}
+
+func (e *Encoder) EncodeString(v string) {
+	e.writeHead(majorText, uint64(len(v)))
+	e.w.WriteString(v)
+}
+
+// EncodeArrayLen starts an array, the n items follow.
+func (e *Encoder) EncodeArrayLen(n int) {
+	e.writeHead(majorArray, uint64(n))
+}
+
+// EncodeMapLen starts a map, the n key and value pairs follow.
+func (e *Encoder) EncodeMapLen(n int) {
+	e.writeHead(majorMap, uint64(n))
+}
+
+// The Start methods begin indefinite length items closed by EncodeBreak.
+// Byte and text strings are continued by EncodeBytes and EncodeString
+// chunks. Deterministic encoding does not allow them.
+
+func (e *Encoder) EncodeBytesStart()  { e.w.WriteUint8(majorBytes<<5 | infoIndefinite) }
+func (e *Encoder) EncodeStringStart() { e.w.WriteUint8(majorText<<5 | infoIndefinite) }
+func (e *Encoder) EncodeArrayStart()  { e.w.WriteUint8(majorArray<<5 | infoIndefinite) }
+func (e *Encoder) EncodeMapStart()    { e.w.WriteUint8(majorMap<<5 | infoIndefinite) }
+func (e *Encoder) EncodeBreak()       { e.w.WriteUint8(codeBreak) }
+
+// EncodeTag starts a tagged item, the tag content follows.
+func (e *Encoder) EncodeTag(n uint64) {
+	e.writeHead(majorTag, n)
+}
+
+func (e *Encoder) EncodeBool(v bool) {
+	if v {
+		e.writeHead(majorSimple, 21)
+	} else {
+		e.writeHead(majorSimple, 20)
+	}
+}
+
+func (e *Encoder) EncodeNil() {
+	e.writeHead(majorSimple, 22)
+}
+
+func (e *Encoder) EncodeSimple(v Simple) {
+	e.writeHead(majorSimple, uint64(v))
+}
+
+func (e *Encoder) EncodeFloat32(v float32) {
+	if e.Canonical {
+		if v != v {
+			e.w.WriteUint8(majorSimple<<5 | info16)
+			e.w.WriteUint16BE(nanHalf)
+			return
+		}
+		if binary.Float16ToFloat32(binary.Float32ToFloat16(v)) == v {
+			e.w.WriteUint8(majorSimple<<5 | info16)
+			e.w.WriteFloat16BE(v)
+			return
+		}
+	}
+	e.w.WriteUint8(majorSimple<<5 | info32)
+	e.w.WriteFloat32BE(v)
+}
+
+func (e *Encoder) EncodeFloat64(v float64) {
+	if e.Canonical && (v != v || float64(float32(v)) == v) {
+		e.EncodeFloat32(float32(v))
+		return
+	}
+	e.w.WriteUint8(majorSimple<<5 | info64)
+	e.w.WriteFloat64BE(v)
+}
+
+// EncodeTime writes an epoch based date/time, a float when t has a
+// fraction of a second.
+func (e *Encoder) EncodeTime(t time.Time) {
+	e.EncodeTag(TagEpochTime)
+	if t.Nanosecond() == 0 {
+		e.EncodeInt(t.Unix())
+	} else {
+		e.EncodeFloat64(float64(t.Unix()) + float64(t.Nanosecond())/1e9)
+	}
+}
+
+// Encode writes v by reflection. Maps are written in iteration order
+// unless the encoder is Canonical.
+func (e *Encoder) Encode(v interface{}) error {
+	return e.encodeValue(reflect.ValueOf(v), 0)
+}
+
+func (e *Encoder) encodeValue(v reflect.Value, depth int) error {
+	if depth > maxDepth {
+		return ErrTooDeep
+	}
+	if !v.IsValid() {
+		e.EncodeNil()
+		return nil
+	}
+	switch v.Type() {
+	case timeType:
+		e.EncodeTime(v.Interface().(time.Time))
+		return nil
+	case bigIntType:
+		x := v.Interface().(big.Int)
+		e.EncodeBigInt(&x)
+		return nil
+	case tagType:
+		tag := v.Interface().(Tag)
+		e.EncodeTag(tag.Number)
+		return e.encodeValue(reflect.ValueOf(tag.Content), depth+1)
+	case simpleType:
+		e.EncodeSimple(Simple(v.Uint()))
+		return nil
+	}
+	switch v.Kind() {
+	case reflect.Bool:
+		e.EncodeBool(v.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		e.EncodeInt(v.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		e.EncodeUint(v.Uint())
+	case reflect.Float32:
+		e.EncodeFloat32(float32(v.Float()))
+	case reflect.Float64:
+		e.EncodeFloat64(v.Float())
+	case reflect.String:
+		e.EncodeString(v.String())
+	case reflect.Slice:
+		if v.IsNil() {
+			e.EncodeNil()
+			return nil
+		}
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			e.EncodeBytes(v.Bytes())
+			return nil
+		}
+		return e.encodeArray(v, depth)
+	case reflect.Array:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			b := make([]byte, v.Len())
+			reflect.Copy(reflect.ValueOf(b), v)
+			e.EncodeBytes(b)
+			return nil
+		}
+		return e.encodeArray(v, depth)
+	case reflect.Map:
+		if v.IsNil() {
+			e.EncodeNil()
+			return nil
+		}
+		return e.encodeMap(v, depth)
+	case reflect.Struct:
+		return e.encodeStruct(v, depth)
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			e.EncodeNil()
+			return nil
+		}
+		return e.encodeValue(v.Elem(), depth+1)
+	default:
+		return ErrUnsupportedType
+	}
+	return nil
+}
+
+func (e *Encoder) encodeArray(v reflect.Value, depth int) error {
+	e.EncodeArrayLen(v.Len())
+	for i := 0; i < v.Len(); i++ {
+		if err := e.encodeValue(v.Index(i), depth+1); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type mapEntry struct {
+	key   []byte
+	value reflect.Value
+}
+
+// encodeKey returns the encoding of key for sorting.
+func (e *Encoder) encodeKey(key reflect.Value, depth int) ([]byte, error) {
+	var buf bytes.Buffer
+	w := binary.NewWriter(&buf)
+	if err := (&Encoder{w, e.Canonical}).encodeValue(key, depth+1); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), w.Error()
+}
+
+func (e *Encoder) writeEntries(entries []mapEntry, depth int) error {
+	if e.Canonical {
+		sort.Slice(entries, func(i, j int) bool {
+			return bytes.Compare(entries[i].key, entries[j].key) < 0
+		})
+	}
+	e.EncodeMapLen(len(entries))
+	for _, entry := range entries {
+		e.w.WriteBytes(entry.key)
+		if err := e.encodeValue(entry.value, depth+1); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (e *Encoder) encodeMap(v reflect.Value, depth int) error {
+	iter := v.MapRange()
+	if !e.Canonical {
+		e.EncodeMapLen(v.Len())
+		for iter.Next() {
+			if err := e.encodeValue(iter.Key(), depth+1); err != nil {
+				return err
+			}
+			if err := e.encodeValue(iter.Value(), depth+1); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	entries := make([]mapEntry, 0, v.Len())
+	for iter.Next() {
+		key, err := e.encodeKey(iter.Key(), depth)
+		if err != nil {
+			return err
+		}
+		entries = append(entries, mapEntry{key, iter.Value()})
+	}
+	return e.writeEntries(entries, depth)
+}
+
+func (e *Encoder) encodeStruct(v reflect.Value, depth int) error {
+	fields := structs.Fields(v.Type(), "cbor")
+	entries := make([]mapEntry, 0, len(fields))
+	for _, f := range fields {
+		fv := v.FieldByIndex(f.Index)
+		if f.OmitEmpty && structs.IsEmpty(fv) {
+			continue
+		}
+		key, err := e.encodeKey(reflect.ValueOf(f.Name), depth)
+		if err != nil {
+			return err
+		}
+		entries = append(entries, mapEntry{key, fv})
+	}
+	return e.writeEntries(entries, depth)
+}
@@ -0,0 +1,141 @@
+package cbor
+
+import (
+	"bytes"
+	"encoding/hex"
+	"math"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/funny/binary"
+	"github.com/funny/utest"
+)
+
+func encode(t *testing.T, v interface{}, canonical bool) []byte {
+	var buf bytes.Buffer
+	w := binary.NewWriter(&buf)
+	e := NewEncoder(w)
+	e.Canonical = canonical
+	utest.IsNilNow(t, e.Encode(v))
+	utest.IsNilNow(t, w.Error())
+	return buf.Bytes()
+}
+
+func unhex(s string) []byte {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+func bigInt(s string) *big.Int {
+	x, _ := new(big.Int).SetString(s, 10)
+	return x
+}
+
+// Examples from RFC 8949 appendix A.
+func Test_Encoder_Vectors(t *testing.T) {
+	for _, c := range []struct {
+		v   interface{}
+		hex string
+	}{
+		{0, "00"},
+		{23, "17"},
+		{24, "1818"},
+		{1000, "1903e8"},
+		{1000000, "1a000f4240"},
+		{uint64(1000000000000), "1b000000e8d4a51000"},
+		{uint64(math.MaxUint64), "1bffffffffffffffff"},
+		{bigInt("18446744073709551616"), "c249010000000000000000"},
+		{bigInt("-18446744073709551616"), "3bffffffffffffffff"},
+		{bigInt("-18446744073709551617"), "c349010000000000000000"},
+		{-1, "20"},
+		{-1000, "3903e7"},
+		{false, "f4"},
+		{true, "f5"},
+		{nil, "f6"},
+		{Undefined, "f7"},
+		{Simple(16), "f0"},
+		{Simple(255), "f8ff"},
+		{time.Unix(1363896240, 0), "c11a514b67b0"},
+		{time.Unix(1363896240, 5e8), "c1fb41d452d9ec200000"},
+		{Tag{32, "http://www.example.com"}, "d82076687474703a2f2f7777772e6578616d706c652e636f6d"},
+		{[]byte{1, 2, 3, 4}, "4401020304"},
+		{"", "60"},
+		{"ü", "62c3bc"},
+		{"水", "63e6b0b4"},
+		{[]int{}, "80"},
+		{[]interface{}{1, []int{2, 3}, []int{4, 5}}, "8301820203820405"},
+		{map[string]interface{}{"a": 1, "b": []int{2, 3}}, "a26161016162820203"},
+		{1.1, "fb3ff199999999999a"},
+		{float32(100000.0), "fa47c35000"},
+	} {
+		utest.EqualNow(t, hex.EncodeToString(encode(t, c.v, true)), c.hex)
+	}
+
+	for _, c := range []struct {
+		v   interface{}
+		hex string
+	}{
+		{0.0, "f90000"},
+		{math.Copysign(0, -1), "f98000"},
+		{1.0, "f93c00"},
+		{1.5, "f93e00"},
+		{65504.0, "f97bff"},
+		{100000.0, "fa47c35000"},
+		{3.4028234663852886e+38, "fa7f7fffff"},
+		{1.0e+300, "fb7e37e43c8800759c"},
+		{5.960464477539063e-8, "f90001"},
+		{-4.0, "f9c400"},
+		{math.Inf(1), "f97c00"},
+		{math.NaN(), "f97e00"},
+		{float32(math.Inf(-1)), "f9fc00"},
+	} {
+		utest.EqualNow(t, hex.EncodeToString(encode(t, c.v, true)), c.hex)
+	}
+
+	utest.EqualNow(t, hex.EncodeToString(encode(t, 1.5, false)), "fb3ff8000000000000")
+	utest.EqualNow(t, hex.EncodeToString(encode(t, float32(1.5), false)), "fa3fc00000")
+}
+
+func Test_Encoder_Canonical(t *testing.T) {
+	m := map[interface{}]interface{}{
+		"aa": 1, "b": 2, 10: 3, -1: 4, 100: 5, false: 6, [1]int{1}: 7,
+	}
+	utest.EqualNow(t, hex.EncodeToString(encode(t, m, true)), "a70a03186405200461620262616101810107f406")
+
+	type s struct {
+		Zeta  int `cbor:"z"`
+		Alpha int `cbor:"a"`
+		Empty int `cbor:",omitempty"`
+	}
+	utest.EqualNow(t, hex.EncodeToString(encode(t, s{1, 2, 0}, true)), "a2616102617a01")
+	utest.EqualNow(t, hex.EncodeToString(encode(t, s{1, 2, 0}, false)), "a2617a01616102")
+}
+
+func Test_Encoder_Indefinite(t *testing.T) {
+	var buf bytes.Buffer
+	e := NewEncoder(binary.NewWriter(&buf))
+	e.EncodeMapStart()
+	e.EncodeString("Fun")
+	e.EncodeBool(true)
+	e.EncodeString("Amt")
+	e.EncodeInt(-2)
+	e.EncodeBreak()
+	e.EncodeStringStart()
+	e.EncodeString("strea")
+	e.EncodeString("ming")
+	e.EncodeBreak()
+	utest.EqualNow(t, hex.EncodeToString(buf.Bytes()), "bf6346756ef563416d7421ff7f657374726561646d696e67ff")
+
+	utest.EqualNow(t, NewEncoder(binary.NewWriter(&buf)).Encode(func() {}), ErrUnsupportedType)
+
+	var cycle interface{}
+	cycle = &cycle
+	utest.EqualNow(t, NewEncoder(binary.NewWriter(&buf)).Encode(cycle), ErrTooDeep)
+	tag := &Tag{Number: 1}
+	tag.Content = tag
+	utest.EqualNow(t, NewEncoder(binary.NewWriter(&buf)).Encode(tag), ErrTooDeep)
+}
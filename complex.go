@@ -0,0 +1,40 @@
+package binary
+
+// Complex numbers are stored as the real part followed by the imaginary
+// part, each in the byte order of the codec.
+
+func GetComplex64BE(b []byte) complex64 {
+	return complex(GetFloat32BE(b), GetFloat32BE(b[4:]))
+}
+
+func PutComplex64BE(b []byte, v complex64) {
+	PutFloat32BE(b, real(v))
+	PutFloat32BE(b[4:], imag(v))
+}
+
+func GetComplex64LE(b []byte) complex64 {
+	return complex(GetFloat32LE(b), GetFloat32LE(b[4:]))
+}
+
+func PutComplex64LE(b []byte, v complex64) {
+	PutFloat32LE(b, real(v))
+	PutFloat32LE(b[4:], imag(v))
+}
+
+func GetComplex128BE(b []byte) complex128 {
+	return complex(GetFloat64BE(b), GetFloat64BE(b[8:]))
+}
+
+func PutComplex128BE(b []byte, v complex128) {
+	PutFloat64BE(b, real(v))
+	PutFloat64BE(b[8:], imag(v))
+}
+
+func GetComplex128LE(b []byte) complex128 {
+	return complex(GetFloat64LE(b), GetFloat64LE(b[8:]))
+}
+
+func PutComplex128LE(b []byte, v complex128) {
+	PutFloat64LE(b, real(v))
+	PutFloat64LE(b[8:], imag(v))
+}
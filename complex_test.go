@@ -0,0 +1,42 @@
+package binary
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/funny/utest"
+)
+
+func Test_Complex_GetPut(t *testing.T) {
+	b := make([]byte, 16)
+	PutComplex64BE(b, complex(1, -2))
+	utest.EqualNow(t, b[:8], []byte{0x3F, 0x80, 0, 0, 0xC0, 0, 0, 0})
+	PutComplex64LE(b, complex(1, -2))
+	utest.EqualNow(t, b[:8], []byte{0, 0, 0x80, 0x3F, 0, 0, 0, 0xC0})
+	PutComplex128BE(b, complex(1, -2))
+	utest.EqualNow(t, b, []byte{0x3F, 0xF0, 0, 0, 0, 0, 0, 0, 0xC0, 0, 0, 0, 0, 0, 0, 0})
+	utest.EqualNow(t, GetComplex128BE(b), complex(1, -2))
+}
+
+func Test_Complex_ReadWrite(t *testing.T) {
+	ReadWriteTest(t, 10000, func(r *Reader, w *Writer) {
+		v1 := complex(rand.Float32(), -rand.Float32())
+		v2 := complex(rand.Float32(), rand.Float32())
+		v3 := complex(rand.NormFloat64(), rand.NormFloat64())
+		v4 := complex(rand.NormFloat64(), rand.NormFloat64())
+
+		w.WriteComplex64BE(v1)
+		w.WriteComplex64LE(v2)
+		w.WriteComplex128BE(v3)
+		w.WriteComplex128LE(v4)
+		NewOrderedWriter(w, LittleEndian).WriteComplex128(v3)
+		utest.IsNilNow(t, w.Error())
+
+		utest.EqualNow(t, r.ReadComplex64BE(), v1)
+		utest.EqualNow(t, r.ReadComplex64LE(), v2)
+		utest.EqualNow(t, r.ReadComplex128BE(), v3)
+		utest.EqualNow(t, r.ReadComplex128LE(), v4)
+		utest.EqualNow(t, r.ReadComplex128LE(), v3)
+		utest.IsNilNow(t, r.Error())
+	})
+}
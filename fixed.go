@@ -0,0 +1,190 @@
+package binary
+
+import (
+	"errors"
+	"math"
+	"strconv"
+)
+
+var (
+	ErrFixedFormat = errors.New("funny/binary: invalid fixed-point format")
+	ErrFixedRange  = errors.New("funny/binary: value out of fixed-point range")
+)
+
+// RoundingMode selects how FixedFromFloat64 drops the bits that do not fit.
+type RoundingMode int
+
+const (
+	RoundNearestEven RoundingMode = iota
+	RoundNearestAway
+	RoundTowardZero
+	RoundDown
+	RoundUp
+)
+
+func (mode RoundingMode) round(x float64) float64 {
+	switch mode {
+	case RoundNearestAway:
+		return math.Round(x)
+	case RoundTowardZero:
+		return math.Trunc(x)
+	case RoundDown:
+		return math.Floor(x)
+	case RoundUp:
+		return math.Ceil(x)
+	}
+	return math.RoundToEven(x)
+}
+
+// FixedFormat describes a fixed-point layout: an integer of Bits width
+// (8, 16, 24, 32, 40, 48, 56 or 64) scaled by 2^Frac, or by 10^Frac for
+// Decimal formats such as prices kept in ten-thousandths.
+type FixedFormat struct {
+	Bits    uint
+	Frac    uint
+	Signed  bool
+	Decimal bool
+}
+
+var (
+	Q8_8    = FixedFormat{Bits: 16, Frac: 8, Signed: true}
+	Q16_16  = FixedFormat{Bits: 32, Frac: 16, Signed: true}
+	Q32_32  = FixedFormat{Bits: 64, Frac: 32, Signed: true}
+	UQ8_8   = FixedFormat{Bits: 16, Frac: 8}
+	UQ16_16 = FixedFormat{Bits: 32, Frac: 16}
+)
+
+func (format FixedFormat) valid() bool {
+	return format.Bits >= 8 && format.Bits <= 64 && format.Bits%8 == 0 &&
+		((!format.Decimal && format.Frac <= format.Bits) || (format.Decimal && format.Frac <= 19))
+}
+
+func (format FixedFormat) scale() float64 {
+	if format.Decimal {
+		return math.Pow10(int(format.Frac))
+	}
+	return math.Ldexp(1, int(format.Frac))
+}
+
+// Fixed is a fixed-point number. Raw holds the stored integer, sign
+// extended for signed formats.
+type Fixed struct {
+	Raw    int64
+	Format FixedFormat
+}
+
+// FixedFromFloat64 scales v into format using mode. It returns
+// ErrFixedRange when the result does not fit in the format width.
+func FixedFromFloat64(v float64, format FixedFormat, mode RoundingMode) (Fixed, error) {
+	if !format.valid() {
+		return Fixed{}, ErrFixedFormat
+	}
+	x := mode.round(v * format.scale())
+	lo, hi := 0.0, math.Ldexp(1, int(format.Bits))
+	if format.Signed {
+		lo, hi = -hi/2, hi/2
+	}
+	if !(x >= lo && x < hi) {
+		return Fixed{}, ErrFixedRange
+	}
+	if !format.Signed && x >= 1<<63 {
+		return Fixed{int64(uint64(x)), format}, nil
+	}
+	return Fixed{int64(x), format}, nil
+}
+
+func (f Fixed) Float64() float64 {
+	if !f.Format.Signed && f.Raw < 0 {
+		return float64(uint64(f.Raw)) / f.Format.scale()
+	}
+	return float64(f.Raw) / f.Format.scale()
+}
+
+// String formats decimal formats exactly, binary formats through float64.
+func (f Fixed) String() string {
+	if !f.Format.Decimal || f.Format.Frac == 0 {
+		return strconv.FormatFloat(f.Float64(), 'f', -1, 64)
+	}
+	var s string
+	if f.Format.Signed || f.Raw >= 0 {
+		s = strconv.FormatInt(f.Raw, 10)
+	} else {
+		s = strconv.FormatUint(uint64(f.Raw), 10)
+	}
+	var neg string
+	if s[0] == '-' {
+		neg, s = "-", s[1:]
+	}
+	for len(s) <= int(f.Format.Frac) {
+		s = "0" + s
+	}
+	i := len(s) - int(f.Format.Frac)
+	return neg + s[:i] + "." + s[i:]
+}
+
+// fromRaw keeps the low Bits of raw and sign extends signed formats.
+func (format FixedFormat) fromRaw(raw uint64) Fixed {
+	shift := 64 - format.Bits
+	if format.Signed {
+		return Fixed{int64(raw<<shift) >> shift, format}
+	}
+	return Fixed{int64(raw << shift >> shift), format}
+}
+
+// ReadFixed reads a fixed-point number of the given format with the
+// integer reader of matching width. An invalid format reads nothing, sets
+// ErrFixedFormat and returns a zero Fixed.
+func (r *OrderedReader) ReadFixed(format FixedFormat) Fixed {
+	if !format.valid() {
+		r.fail(ErrFixedFormat)
+		return Fixed{}
+	}
+	var raw uint64
+	switch format.Bits {
+	case 8:
+		raw = uint64(r.ReadUint8())
+	case 16:
+		raw = uint64(r.ReadUint16())
+	case 24:
+		raw = uint64(r.ReadUint24())
+	case 32:
+		raw = uint64(r.ReadUint32())
+	case 40:
+		raw = r.ReadUint40()
+	case 48:
+		raw = r.ReadUint48()
+	case 56:
+		raw = r.ReadUint56()
+	default:
+		raw = r.ReadUint64()
+	}
+	return format.fromRaw(raw)
+}
+
+// WriteFixed writes v with the integer writer of matching width. An invalid
+// format writes nothing and sets ErrFixedFormat.
+func (w *OrderedWriter) WriteFixed(v Fixed) {
+	if !v.Format.valid() {
+		w.fail(ErrFixedFormat)
+		return
+	}
+	raw := uint64(v.Raw)
+	switch v.Format.Bits {
+	case 8:
+		w.WriteUint8(uint8(raw))
+	case 16:
+		w.WriteUint16(uint16(raw))
+	case 24:
+		w.WriteUint24(uint32(raw))
+	case 32:
+		w.WriteUint32(uint32(raw))
+	case 40:
+		w.WriteUint40(raw)
+	case 48:
+		w.WriteUint48(raw)
+	case 56:
+		w.WriteUint56(raw)
+	default:
+		w.WriteUint64(raw)
+	}
+}
@@ -0,0 +1,125 @@
+package binary
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/funny/utest"
+)
+
+func Test_Fixed_FromFloat64(t *testing.T) {
+	f, err := FixedFromFloat64(1.5, Q16_16, RoundNearestEven)
+	utest.IsNilNow(t, err)
+	utest.EqualNow(t, f.Raw, int64(0x18000))
+	utest.EqualNow(t, f.Float64(), 1.5)
+
+	f, err = FixedFromFloat64(-1.25, Q8_8, RoundNearestEven)
+	utest.IsNilNow(t, err)
+	utest.EqualNow(t, f.Raw, int64(-320))
+	utest.EqualNow(t, f.String(), "-1.25")
+
+	_, err = FixedFromFloat64(128, Q8_8, RoundNearestEven)
+	utest.EqualNow(t, err, ErrFixedRange)
+	_, err = FixedFromFloat64(-128, Q8_8, RoundNearestEven)
+	utest.IsNilNow(t, err)
+	_, err = FixedFromFloat64(-0.5, UQ8_8, RoundNearestEven)
+	utest.EqualNow(t, err, ErrFixedRange)
+	_, err = FixedFromFloat64(math.NaN(), Q8_8, RoundNearestEven)
+	utest.EqualNow(t, err, ErrFixedRange)
+	_, err = FixedFromFloat64(1, FixedFormat{Bits: 12}, RoundNearestEven)
+	utest.EqualNow(t, err, ErrFixedFormat)
+
+	f, err = FixedFromFloat64(1<<64-1<<11, FixedFormat{Bits: 64}, RoundNearestEven)
+	utest.IsNilNow(t, err)
+	utest.EqualNow(t, uint64(f.Raw), uint64(1<<64-1<<11))
+	utest.EqualNow(t, f.Float64(), float64(1<<64-1<<11))
+}
+
+func Test_Fixed_Rounding(t *testing.T) {
+	q2 := FixedFormat{Bits: 16, Frac: 2, Signed: true}
+	for _, c := range []struct {
+		v    float64
+		mode RoundingMode
+		raw  int64
+	}{
+		{0.125, RoundNearestEven, 0},
+		{0.375, RoundNearestEven, 2},
+		{0.125, RoundNearestAway, 1},
+		{-0.125, RoundNearestAway, -1},
+		{0.3, RoundTowardZero, 1},
+		{-0.3, RoundTowardZero, -1},
+		{-0.3, RoundDown, -2},
+		{0.3, RoundUp, 2},
+		{-0.3, RoundUp, -1},
+	} {
+		f, err := FixedFromFloat64(c.v, q2, c.mode)
+		utest.IsNilNow(t, err)
+		utest.EqualNow(t, f.Raw, c.raw)
+	}
+}
+
+func Test_Fixed_Decimal(t *testing.T) {
+	price := FixedFormat{Bits: 64, Frac: 4, Signed: true, Decimal: true}
+	f, err := FixedFromFloat64(12.3456, price, RoundNearestEven)
+	utest.IsNilNow(t, err)
+	utest.EqualNow(t, f.Raw, int64(123456))
+	utest.EqualNow(t, f.String(), "12.3456")
+
+	f, _ = FixedFromFloat64(-0.0012, price, RoundNearestEven)
+	utest.EqualNow(t, f.String(), "-0.0012")
+	f, _ = FixedFromFloat64(7, FixedFormat{Bits: 32, Frac: 2, Decimal: true}, RoundNearestEven)
+	utest.EqualNow(t, f.String(), "7.00")
+}
+
+func Test_Fixed_ReadWrite(t *testing.T) {
+	formats := []FixedFormat{
+		Q8_8, Q16_16, Q32_32, UQ8_8, UQ16_16,
+		{Bits: 8, Frac: 4, Signed: true},
+		{Bits: 24, Frac: 8, Signed: true},
+		{Bits: 40, Frac: 10},
+		{Bits: 48, Frac: 16, Signed: true},
+		{Bits: 56, Frac: 6, Signed: true, Decimal: true},
+	}
+	for _, order := range []ByteOrder{BigEndian, LittleEndian} {
+		ReadWriteTest(t, 1000, func(r *Reader, w *Writer) {
+			var values []Fixed
+			ow := NewOrderedWriter(w, order)
+			for _, format := range formats {
+				raw := rand.Uint64()
+				f := format.fromRaw(raw)
+				values = append(values, f)
+				ow.WriteFixed(f)
+			}
+			utest.IsNilNow(t, w.Error())
+
+			or := NewOrderedReader(r, order)
+			for i, format := range formats {
+				utest.EqualNow(t, or.ReadFixed(format), values[i])
+			}
+			utest.IsNilNow(t, r.Error())
+		})
+	}
+
+	var buf = Buffer{Data: make([]byte, 4)}
+	f, _ := FixedFromFloat64(-1.5, Q16_16, RoundNearestEven)
+	NewOrderedWriter(&buf, BigEndian).WriteFixed(f)
+	utest.EqualNow(t, buf.Data, []byte{0xFF, 0xFE, 0x80, 0x00})
+	utest.EqualNow(t, NewOrderedReader(&buf, BigEndian).ReadFixed(Q16_16).Float64(), -1.5)
+}
+
+func Test_Fixed_InvalidFormat(t *testing.T) {
+	buf := Buffer{Data: make([]byte, 8)}
+	bad := FixedFormat{Bits: 12}
+	ow := NewOrderedWriter(&buf, BigEndian)
+	ow.WriteFixed(Fixed{Raw: 1, Format: bad})
+	utest.EqualNow(t, ow.Error(), ErrFixedFormat)
+	utest.EqualNow(t, buf.WritePos, 0)
+	ow.WriteUint16(1)
+	utest.EqualNow(t, ow.Error(), ErrFixedFormat)
+
+	or := NewOrderedReader(&buf, BigEndian)
+	utest.EqualNow(t, or.ReadFixed(bad), Fixed{})
+	utest.EqualNow(t, or.Error(), ErrFixedFormat)
+	utest.EqualNow(t, buf.ReadPos, 0)
+}
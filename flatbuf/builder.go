@@ -0,0 +1,258 @@
+package flatbuf
+
+import (
+	"bytes"
+	"math"
+
+	"github.com/funny/binary"
+)
+
+// Builder writes a message into a Buffer and grows its Data as needed.
+// Strings, vectors and child tables have to be created before the table
+// that refers to them, and only one table can be open at a time. Tables
+// with the same vtable share it. The first error is kept and later calls
+// do nothing.
+type Builder struct {
+	buf     *binary.Buffer
+	start   int
+	table   []byte
+	slots   []int
+	open    bool
+	vtables []int
+	err     error
+}
+
+func NewBuilder(buf *binary.Buffer) *Builder {
+	b := &Builder{buf: buf}
+	b.Reset()
+	return b
+}
+
+// Reset starts a new message at the write position of the Buffer.
+func (b *Builder) Reset() {
+	b.start = b.buf.WritePos
+	b.open = false
+	b.vtables = b.vtables[:0]
+	b.err = nil
+	b.grow(offsetSize)
+	b.buf.WriteUint32LE(0)
+}
+
+// Error returns the first error.
+func (b *Builder) Error() error {
+	return b.err
+}
+
+func (b *Builder) grow(n int) {
+	if len(b.buf.Data)-b.buf.WritePos >= n {
+		return
+	}
+	size := 2 * len(b.buf.Data)
+	if size < b.buf.WritePos+n {
+		size = b.buf.WritePos + n
+	}
+	if size < 64 {
+		size = 64
+	}
+	data := make([]byte, size)
+	copy(data, b.buf.Data)
+	b.buf.Data = data
+}
+
+// reserve makes room for an object of n bytes outside of any table and
+// returns its offset, or false after an error.
+func (b *Builder) reserve(n int) (Offset, bool) {
+	if b.err == nil && b.open {
+		b.err = ErrNested
+	}
+	if b.err == nil && uint64(b.buf.WritePos-b.start)+uint64(n) > math.MaxUint32 {
+		b.err = ErrTooLarge
+	}
+	if b.err != nil {
+		return 0, false
+	}
+	b.grow(n)
+	return Offset(b.buf.WritePos - b.start), true
+}
+
+// CreateString writes s and returns its offset.
+func (b *Builder) CreateString(s string) Offset {
+	off, ok := b.reserve(offsetSize + len(s))
+	if ok {
+		b.buf.WriteUint32LE(uint32(len(s)))
+		b.buf.WriteString(s)
+	}
+	return off
+}
+
+// CreateBytes writes v and returns its offset.
+func (b *Builder) CreateBytes(v []byte) Offset {
+	off, ok := b.reserve(offsetSize + len(v))
+	if ok {
+		b.buf.WriteUint32LE(uint32(len(v)))
+		b.buf.WriteBytes(v)
+	}
+	return off
+}
+
+// CreateVector writes a vector of n elements of elemSize bytes and returns
+// its offset and the zeroed elements, to be filled in place with
+// binary.PutUint32LE and friends before the next call to the Builder. After
+// an error the elements are scratch space, or nil when the vector is too
+// large.
+func (b *Builder) CreateVector(elemSize, n int) (Offset, []byte) {
+	size := uint64(elemSize) * uint64(n)
+	if elemSize <= 0 || n < 0 || size > math.MaxUint32 {
+		if b.err == nil {
+			b.err = ErrTooLarge
+		}
+		return 0, nil
+	}
+	off, ok := b.reserve(offsetSize + int(size))
+	if !ok {
+		return 0, make([]byte, size)
+	}
+	b.buf.WriteUint32LE(uint32(n))
+	elems := b.buf.Data[b.buf.WritePos : b.buf.WritePos+int(size)]
+	for i := range elems {
+		elems[i] = 0
+	}
+	b.buf.WritePos += int(size)
+	return off, elems
+}
+
+// CreateOffsetVector writes a vector of offsets, as read by Vector.Table,
+// Vector.String and Vector.Bytes.
+func (b *Builder) CreateOffsetVector(offs []Offset) Offset {
+	off, elems := b.CreateVector(offsetSize, len(offs))
+	for i, o := range offs {
+		binary.PutUint32LE(elems[i*offsetSize:], uint32(o))
+	}
+	return off
+}
+
+// StartTable opens a table with numFields slots.
+func (b *Builder) StartTable(numFields int) {
+	if b.err != nil {
+		return
+	}
+	if b.open {
+		b.err = ErrNested
+		return
+	}
+	if numFields < 0 || numFields > maxSlots {
+		b.err = ErrSlot
+		return
+	}
+	b.open = true
+	b.table = append(b.table[:0], 0, 0, 0, 0)
+	b.slots = b.slots[:0]
+	for i := 0; i < numFields; i++ {
+		b.slots = append(b.slots, 0)
+	}
+}
+
+// field appends n bytes for slot to the open table and returns them. A slot
+// given twice keeps the last value.
+func (b *Builder) field(slot, n int) []byte {
+	if b.err == nil && !b.open {
+		b.err = ErrNotInTable
+	}
+	if b.err == nil && (slot < 0 || slot >= len(b.slots)) {
+		b.err = ErrSlot
+	}
+	if b.err == nil && len(b.table)+n > maxTable {
+		b.err = ErrTooLarge
+	}
+	if b.err != nil {
+		return make([]byte, n)
+	}
+	b.slots[slot] = len(b.table)
+	for i := 0; i < n; i++ {
+		b.table = append(b.table, 0)
+	}
+	return b.table[len(b.table)-n:]
+}
+
+func (b *Builder) AddBool(slot int, v bool) {
+	if v {
+		b.AddUint8(slot, 1)
+	} else {
+		b.AddUint8(slot, 0)
+	}
+}
+
+func (b *Builder) AddUint8(slot int, v uint8)     { b.field(slot, 1)[0] = v }
+func (b *Builder) AddUint16(slot int, v uint16)   { binary.PutUint16LE(b.field(slot, 2), v) }
+func (b *Builder) AddUint32(slot int, v uint32)   { binary.PutUint32LE(b.field(slot, 4), v) }
+func (b *Builder) AddUint64(slot int, v uint64)   { binary.PutUint64LE(b.field(slot, 8), v) }
+func (b *Builder) AddInt8(slot int, v int8)       { b.AddUint8(slot, uint8(v)) }
+func (b *Builder) AddInt16(slot int, v int16)     { b.AddUint16(slot, uint16(v)) }
+func (b *Builder) AddInt32(slot int, v int32)     { b.AddUint32(slot, uint32(v)) }
+func (b *Builder) AddInt64(slot int, v int64)     { b.AddUint64(slot, uint64(v)) }
+func (b *Builder) AddFloat32(slot int, v float32) { binary.PutFloat32LE(b.field(slot, 4), v) }
+func (b *Builder) AddFloat64(slot int, v float64) { binary.PutFloat64LE(b.field(slot, 8), v) }
+
+// AddOffset sets slot to an object created earlier in the same message.
+func (b *Builder) AddOffset(slot int, off Offset) { b.AddUint32(slot, uint32(off)) }
+
+// EndTable writes the open table, after its vtable unless an identical one
+// was written before, and returns its offset.
+func (b *Builder) EndTable() Offset {
+	if b.err == nil && !b.open {
+		b.err = ErrNotInTable
+	}
+	if b.err != nil {
+		return 0
+	}
+	b.open = false
+
+	n := len(b.slots)
+	for n > 0 && b.slots[n-1] == 0 {
+		n--
+	}
+	vtable := make([]byte, vtableStart+2*n)
+	binary.PutUint16LE(vtable, uint16(len(vtable)))
+	binary.PutUint16LE(vtable[2:], uint16(len(b.table)))
+	for i := 0; i < n; i++ {
+		binary.PutUint16LE(vtable[vtableStart+2*i:], uint16(b.slots[i]))
+	}
+
+	vt := -1
+	for _, pos := range b.vtables {
+		if bytes.HasPrefix(b.buf.Data[b.start+pos:b.buf.WritePos], vtable) {
+			vt = pos
+			break
+		}
+	}
+	if vt < 0 {
+		pos, ok := b.reserve(len(vtable))
+		if !ok {
+			return 0
+		}
+		vt = int(pos)
+		b.vtables = append(b.vtables, vt)
+		b.buf.WriteBytes(vtable)
+	}
+
+	off, ok := b.reserve(len(b.table))
+	if !ok {
+		return 0
+	}
+	binary.PutUint32LE(b.table, uint32(int(off)-vt))
+	b.buf.WriteBytes(b.table)
+	return off
+}
+
+// Finish sets the root table and returns the message, which is the part of
+// the Buffer written since the last Reset.
+func (b *Builder) Finish(root Offset) ([]byte, error) {
+	if b.err == nil && b.open {
+		b.err = ErrNested
+	}
+	if b.err != nil {
+		return nil, b.err
+	}
+	binary.PutUint32LE(b.buf.Data[b.start:], uint32(root))
+	return b.buf.Data[b.start:b.buf.WritePos], nil
+}
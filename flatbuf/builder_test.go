@@ -0,0 +1,94 @@
+package flatbuf
+
+import (
+	"testing"
+
+	"github.com/funny/binary"
+	"github.com/funny/utest"
+)
+
+func Test_Builder_Layout(t *testing.T) {
+	var buf binary.Buffer
+	b := NewBuilder(&buf)
+	s := b.CreateString("hi")
+	b.StartTable(4)
+	b.AddUint16(0, 7)
+	b.AddOffset(2, s)
+	root := b.EndTable()
+	msg, err := b.Finish(root)
+	utest.IsNilNow(t, err)
+	utest.EqualNow(t, msg, []byte{
+		20, 0, 0, 0,
+		2, 0, 0, 0, 'h', 'i',
+		10, 0, 10, 0, 4, 0, 0, 0, 6, 0,
+		10, 0, 0, 0, 7, 0, 4, 0, 0, 0,
+	})
+}
+
+func Test_Builder_SharedVTable(t *testing.T) {
+	var buf binary.Buffer
+	b := NewBuilder(&buf)
+	var offs []Offset
+	for i := 0; i < 3; i++ {
+		b.StartTable(1)
+		b.AddUint32(0, uint32(i))
+		offs = append(offs, b.EndTable())
+	}
+	utest.EqualNow(t, offs, []Offset{10, 18, 26})
+	utest.EqualNow(t, int(offs[1]-offs[0]), 8)
+
+	b.StartTable(1)
+	b.AddOffset(0, b.CreateOffsetVector(offs))
+	utest.EqualNow(t, b.Error(), ErrNested)
+}
+
+func Test_Builder_Reset(t *testing.T) {
+	buf := binary.Buffer{Data: make([]byte, 0)}
+	b := NewBuilder(&buf)
+	b.StartTable(1)
+	b.AddBool(0, true)
+	first, err := b.Finish(b.EndTable())
+	utest.IsNilNow(t, err)
+	first = append([]byte(nil), first...)
+
+	b.Reset()
+	b.StartTable(1)
+	b.AddBool(0, true)
+	second, err := b.Finish(b.EndTable())
+	utest.IsNilNow(t, err)
+	utest.EqualNow(t, second, first)
+	utest.EqualNow(t, buf.WritePos, 2*len(first))
+}
+
+func Test_Builder_Errors(t *testing.T) {
+	var buf binary.Buffer
+	b := NewBuilder(&buf)
+	b.AddUint8(0, 1)
+	utest.EqualNow(t, b.Error(), ErrNotInTable)
+
+	b = NewBuilder(&buf)
+	b.StartTable(1)
+	b.AddUint8(1, 1)
+	utest.EqualNow(t, b.Error(), ErrSlot)
+
+	b = NewBuilder(&buf)
+	b.StartTable(1)
+	b.StartTable(1)
+	utest.EqualNow(t, b.Error(), ErrNested)
+
+	b = NewBuilder(&buf)
+	b.StartTable(1)
+	_, err := b.Finish(0)
+	utest.EqualNow(t, err, ErrNested)
+
+	b = NewBuilder(&buf)
+	b.EndTable()
+	utest.EqualNow(t, b.Error(), ErrNotInTable)
+
+	b = NewBuilder(&buf)
+	b.StartTable(1)
+	for i := 0; i <= maxTable/8; i++ {
+		b.AddUint64(0, 0)
+	}
+	utest.EqualNow(t, b.Error(), ErrTooLarge)
+}
@@ -0,0 +1,35 @@
+// Package flatbuf implements FlatBuffers style tables that are read in place,
+// on top of the Buffer type of github.com/funny/binary.
+//
+// A message starts with the offset of its root table. Every offset is a
+// little endian uint32 counted from the start of the message. A table starts
+// with the distance back to its vtable, a list of uint16 field positions
+// preceded by the vtable and table sizes, and a field whose position is zero
+// is absent. Strings, byte slices and vectors start with their element
+// count. Nothing needs to be aligned, so accessors read fields directly with
+// binary.GetUint32LE and friends without decoding the rest of the message.
+//
+// Accessors check every offset they follow against the message, so a
+// corrupted message gives ErrOutOfRange instead of a panic.
+package flatbuf
+
+import "errors"
+
+var (
+	ErrOutOfRange = errors.New("funny/binary/flatbuf: offset out of range")
+	ErrBadVTable  = errors.New("funny/binary/flatbuf: malformed vtable")
+	ErrNested     = errors.New("funny/binary/flatbuf: table is already open")
+	ErrNotInTable = errors.New("funny/binary/flatbuf: no table is open")
+	ErrSlot       = errors.New("funny/binary/flatbuf: field slot out of range")
+	ErrTooLarge   = errors.New("funny/binary/flatbuf: table or message too large")
+)
+
+// Offset is the position of an object from the start of its message.
+type Offset uint32
+
+const (
+	offsetSize  = 4
+	vtableStart = 4
+	maxTable    = 0xFFFF
+	maxSlots    = (maxTable - vtableStart) / 2
+)
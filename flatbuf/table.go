@@ -0,0 +1,205 @@
+package flatbuf
+
+import (
+	"github.com/funny/binary"
+)
+
+// Table is a view of a table inside a message. The zero Table has no
+// fields.
+type Table struct {
+	data   []byte
+	pos    int
+	vtable []byte
+	size   int
+}
+
+// GetRoot returns the root table of a message.
+func GetRoot(data []byte) (Table, error) {
+	if len(data) < offsetSize {
+		return Table{}, ErrOutOfRange
+	}
+	return GetTable(data, Offset(binary.GetUint32LE(data)))
+}
+
+// GetTable returns the table at off after checking that it and its vtable
+// lie inside data.
+func GetTable(data []byte, off Offset) (Table, error) {
+	pos := uint64(off)
+	if pos < offsetSize || pos+offsetSize > uint64(len(data)) {
+		return Table{}, ErrOutOfRange
+	}
+	back := uint64(binary.GetUint32LE(data[pos:]))
+	if back == 0 || back > pos {
+		return Table{}, ErrOutOfRange
+	}
+	vt := pos - back
+	if vt+vtableStart > pos {
+		return Table{}, ErrBadVTable
+	}
+	vsize := uint64(binary.GetUint16LE(data[vt:]))
+	size := uint64(binary.GetUint16LE(data[vt+2:]))
+	if vsize < vtableStart || vsize%2 != 0 || vt+vsize > pos || size < offsetSize {
+		return Table{}, ErrBadVTable
+	}
+	if pos+size > uint64(len(data)) {
+		return Table{}, ErrOutOfRange
+	}
+	return Table{
+		data:   data,
+		pos:    int(pos),
+		vtable: data[vt+vtableStart : vt+vsize],
+		size:   int(size),
+	}, nil
+}
+
+// Offset returns the position of the table in its message.
+func (t Table) Offset() Offset {
+	return Offset(t.pos)
+}
+
+// NumFields returns the number of slots in the vtable, which leaves out
+// trailing absent fields.
+func (t Table) NumFields() int {
+	return len(t.vtable) / 2
+}
+
+func (t Table) slot(slot int) int {
+	if slot < 0 || slot >= t.NumFields() {
+		return 0
+	}
+	return int(binary.GetUint16LE(t.vtable[2*slot:]))
+}
+
+// Has reports whether slot is present.
+func (t Table) Has(slot int) bool {
+	return t.slot(slot) != 0
+}
+
+// field returns the n bytes of slot, or nil when the slot is absent or does
+// not fit in the table.
+func (t Table) field(slot, n int) []byte {
+	off := t.slot(slot)
+	if off < offsetSize || off+n > t.size {
+		return nil
+	}
+	return t.data[t.pos+off : t.pos+off+n]
+}
+
+// The scalar accessors return def when the field is absent or does not fit
+// in the table.
+
+func (t Table) Bool(slot int, def bool) bool {
+	if b := t.field(slot, 1); b != nil {
+		return b[0] != 0
+	}
+	return def
+}
+
+func (t Table) Uint8(slot int, def uint8) uint8 {
+	if b := t.field(slot, 1); b != nil {
+		return b[0]
+	}
+	return def
+}
+
+func (t Table) Uint16(slot int, def uint16) uint16 {
+	if b := t.field(slot, 2); b != nil {
+		return binary.GetUint16LE(b)
+	}
+	return def
+}
+
+func (t Table) Uint32(slot int, def uint32) uint32 {
+	if b := t.field(slot, 4); b != nil {
+		return binary.GetUint32LE(b)
+	}
+	return def
+}
+
+func (t Table) Uint64(slot int, def uint64) uint64 {
+	if b := t.field(slot, 8); b != nil {
+		return binary.GetUint64LE(b)
+	}
+	return def
+}
+
+func (t Table) Int8(slot int, def int8) int8    { return int8(t.Uint8(slot, uint8(def))) }
+func (t Table) Int16(slot int, def int16) int16 { return int16(t.Uint16(slot, uint16(def))) }
+func (t Table) Int32(slot int, def int32) int32 { return int32(t.Uint32(slot, uint32(def))) }
+func (t Table) Int64(slot int, def int64) int64 { return int64(t.Uint64(slot, uint64(def))) }
+
+func (t Table) Float32(slot int, def float32) float32 {
+	if b := t.field(slot, 4); b != nil {
+		return binary.GetFloat32LE(b)
+	}
+	return def
+}
+
+func (t Table) Float64(slot int, def float64) float64 {
+	if b := t.field(slot, 8); b != nil {
+		return binary.GetFloat64LE(b)
+	}
+	return def
+}
+
+// ref returns the offset stored in slot. An absent slot gives 0 and no
+// error.
+func (t Table) ref(slot int) (Offset, error) {
+	if !t.Has(slot) {
+		return 0, nil
+	}
+	b := t.field(slot, offsetSize)
+	if b == nil {
+		return 0, ErrOutOfRange
+	}
+	return Offset(binary.GetUint32LE(b)), nil
+}
+
+// Bytes returns the bytes in slot without copying them, or nil when the
+// slot is absent.
+func (t Table) Bytes(slot int) ([]byte, error) {
+	off, err := t.ref(slot)
+	if err != nil || off == 0 {
+		return nil, err
+	}
+	return getBytes(t.data, off)
+}
+
+// String returns the string in slot, or "" when the slot is absent.
+func (t Table) String(slot int) (string, error) {
+	b, err := t.Bytes(slot)
+	return string(b), err
+}
+
+// Table returns the table in slot, or the zero Table when the slot is
+// absent.
+func (t Table) Table(slot int) (Table, error) {
+	off, err := t.ref(slot)
+	if err != nil || off == 0 {
+		return Table{}, err
+	}
+	return GetTable(t.data, off)
+}
+
+// Vector returns the vector of elemSize byte elements in slot, or an empty
+// Vector when the slot is absent.
+func (t Table) Vector(slot, elemSize int) (Vector, error) {
+	off, err := t.ref(slot)
+	if err != nil || off == 0 {
+		return Vector{}, err
+	}
+	return GetVector(t.data, off, elemSize)
+}
+
+func getBytes(data []byte, off Offset) ([]byte, error) {
+	pos := uint64(off)
+	if pos < offsetSize || pos+offsetSize > uint64(len(data)) {
+		return nil, ErrOutOfRange
+	}
+	n := uint64(binary.GetUint32LE(data[pos:]))
+	pos += offsetSize
+	if pos+n > uint64(len(data)) {
+		return nil, ErrOutOfRange
+	}
+	return data[pos : pos+n : pos+n], nil
+}
@@ -0,0 +1,138 @@
+package flatbuf
+
+import (
+	"testing"
+
+	"github.com/funny/binary"
+	"github.com/funny/utest"
+)
+
+func buildScalars(t *testing.T) []byte {
+	var buf binary.Buffer
+	b := NewBuilder(&buf)
+	name := b.CreateString("gopher")
+	raw := b.CreateBytes([]byte{1, 2, 3})
+	b.StartTable(1)
+	b.AddInt32(0, -5)
+	child := b.EndTable()
+
+	b.StartTable(14)
+	b.AddBool(0, true)
+	b.AddUint8(1, 0xfe)
+	b.AddUint16(2, 0xbeef)
+	b.AddUint32(3, 0xdeadbeef)
+	b.AddUint64(4, 1<<60)
+	b.AddInt8(5, -1)
+	b.AddInt16(6, -2)
+	b.AddInt32(7, -3)
+	b.AddInt64(8, -4)
+	b.AddFloat32(9, 1.5)
+	b.AddFloat64(10, -2.25)
+	b.AddOffset(11, name)
+	b.AddOffset(12, raw)
+	b.AddOffset(13, child)
+	msg, err := b.Finish(b.EndTable())
+	utest.IsNilNow(t, err)
+	return msg
+}
+
+func Test_Table_Fields(t *testing.T) {
+	root, err := GetRoot(buildScalars(t))
+	utest.IsNilNow(t, err)
+	utest.EqualNow(t, root.NumFields(), 14)
+	utest.EqualNow(t, root.Bool(0, false), true)
+	utest.EqualNow(t, root.Uint8(1, 0), uint8(0xfe))
+	utest.EqualNow(t, root.Uint16(2, 0), uint16(0xbeef))
+	utest.EqualNow(t, root.Uint32(3, 0), uint32(0xdeadbeef))
+	utest.EqualNow(t, root.Uint64(4, 0), uint64(1<<60))
+	utest.EqualNow(t, root.Int8(5, 0), int8(-1))
+	utest.EqualNow(t, root.Int16(6, 0), int16(-2))
+	utest.EqualNow(t, root.Int32(7, 0), int32(-3))
+	utest.EqualNow(t, root.Int64(8, 0), int64(-4))
+	utest.EqualNow(t, root.Float32(9, 0), float32(1.5))
+	utest.EqualNow(t, root.Float64(10, 0), -2.25)
+
+	s, err := root.String(11)
+	utest.IsNilNow(t, err)
+	utest.EqualNow(t, s, "gopher")
+	raw, err := root.Bytes(12)
+	utest.IsNilNow(t, err)
+	utest.EqualNow(t, raw, []byte{1, 2, 3})
+	child, err := root.Table(13)
+	utest.IsNilNow(t, err)
+	utest.EqualNow(t, child.Int32(0, 0), int32(-5))
+}
+
+func Test_Table_Absent(t *testing.T) {
+	var buf binary.Buffer
+	b := NewBuilder(&buf)
+	b.StartTable(4)
+	b.AddUint8(1, 9)
+	msg, err := b.Finish(b.EndTable())
+	utest.IsNilNow(t, err)
+
+	root, err := GetRoot(msg)
+	utest.IsNilNow(t, err)
+	utest.EqualNow(t, root.NumFields(), 2)
+	utest.EqualNow(t, root.Has(0), false)
+	utest.EqualNow(t, root.Has(1), true)
+	utest.EqualNow(t, root.Has(3), false)
+	utest.EqualNow(t, root.Uint32(0, 42), uint32(42))
+	utest.EqualNow(t, root.Uint32(3, 42), uint32(42))
+	utest.EqualNow(t, root.Uint32(1, 42), uint32(42))
+
+	s, err := root.String(0)
+	utest.IsNilNow(t, err)
+	utest.EqualNow(t, s, "")
+	child, err := root.Table(3)
+	utest.IsNilNow(t, err)
+	utest.EqualNow(t, child.NumFields(), 0)
+	utest.EqualNow(t, child.Bool(0, true), true)
+}
+
+func Test_Table_Corrupt(t *testing.T) {
+	msg := buildScalars(t)
+
+	_, err := GetRoot(msg[:3])
+	utest.EqualNow(t, err, ErrOutOfRange)
+
+	bad := append([]byte(nil), msg...)
+	binary.PutUint32LE(bad, uint32(len(bad)))
+	_, err = GetRoot(bad)
+	utest.EqualNow(t, err, ErrOutOfRange)
+
+	root, err := GetRoot(msg)
+	utest.IsNilNow(t, err)
+	_, err = GetRoot(msg[:int(root.Offset())+8])
+	utest.EqualNow(t, err, ErrOutOfRange)
+
+	bad = append([]byte(nil), msg...)
+	back := binary.GetUint32LE(bad[root.Offset():])
+	binary.PutUint32LE(bad[root.Offset():], back+uint32(root.Offset()))
+	_, err = GetRoot(bad)
+	utest.EqualNow(t, err, ErrOutOfRange)
+
+	bad = append([]byte(nil), msg...)
+	binary.PutUint16LE(bad[int(root.Offset())-int(back):], 3)
+	_, err = GetRoot(bad)
+	utest.EqualNow(t, err, ErrBadVTable)
+
+	bad = append([]byte(nil), msg...)
+	name := binary.GetUint32LE(msg[root.Offset()+root.vtableField(11):])
+	binary.PutUint32LE(bad[name:], 1000)
+	root, err = GetRoot(bad)
+	utest.IsNilNow(t, err)
+	_, err = root.String(11)
+	utest.EqualNow(t, err, ErrOutOfRange)
+
+	bad = append([]byte(nil), msg...)
+	binary.PutUint32LE(bad[root.Offset()+root.vtableField(13):], 1<<31)
+	root, err = GetRoot(bad)
+	utest.IsNilNow(t, err)
+	_, err = root.Table(13)
+	utest.EqualNow(t, err, ErrOutOfRange)
+}
+
+func (t Table) vtableField(slot int) Offset {
+	return Offset(t.slot(slot))
+}
@@ -0,0 +1,81 @@
+package flatbuf
+
+import (
+	"github.com/funny/binary"
+)
+
+// Vector is a view of a vector inside a message. Its elements were checked
+// to lie inside the message, an index out of range panics like it does for
+// a slice.
+type Vector struct {
+	data     []byte
+	elems    []byte
+	elemSize int
+}
+
+// GetVector returns the vector of elemSize byte elements at off after
+// checking that all of its elements lie inside data.
+func GetVector(data []byte, off Offset, elemSize int) (Vector, error) {
+	pos := uint64(off)
+	if elemSize <= 0 || pos < offsetSize || pos+offsetSize > uint64(len(data)) {
+		return Vector{}, ErrOutOfRange
+	}
+	size := uint64(binary.GetUint32LE(data[pos:])) * uint64(elemSize)
+	pos += offsetSize
+	if pos+size > uint64(len(data)) {
+		return Vector{}, ErrOutOfRange
+	}
+	return Vector{
+		data:     data,
+		elems:    data[pos : pos+size : pos+size],
+		elemSize: elemSize,
+	}, nil
+}
+
+// Len returns the number of elements.
+func (v Vector) Len() int {
+	if v.elemSize == 0 {
+		return 0
+	}
+	return len(v.elems) / v.elemSize
+}
+
+// Data returns the elements without copying them.
+func (v Vector) Data() []byte {
+	return v.elems
+}
+
+func (v Vector) elem(i int) []byte {
+	if i < 0 || i >= v.Len() {
+		panic("funny/binary/flatbuf: vector index out of range")
+	}
+	return v.elems[i*v.elemSize : (i+1)*v.elemSize]
+}
+
+func (v Vector) Uint8(i int) uint8     { return v.elem(i)[0] }
+func (v Vector) Uint16(i int) uint16   { return binary.GetUint16LE(v.elem(i)) }
+func (v Vector) Uint32(i int) uint32   { return binary.GetUint32LE(v.elem(i)) }
+func (v Vector) Uint64(i int) uint64   { return binary.GetUint64LE(v.elem(i)) }
+func (v Vector) Int8(i int) int8       { return int8(v.Uint8(i)) }
+func (v Vector) Int16(i int) int16     { return int16(v.Uint16(i)) }
+func (v Vector) Int32(i int) int32     { return int32(v.Uint32(i)) }
+func (v Vector) Int64(i int) int64     { return int64(v.Uint64(i)) }
+func (v Vector) Float32(i int) float32 { return binary.GetFloat32LE(v.elem(i)) }
+func (v Vector) Float64(i int) float64 { return binary.GetFloat64LE(v.elem(i)) }
+
+// Table returns the table at element i of a vector of offsets.
+func (v Vector) Table(i int) (Table, error) {
+	return GetTable(v.data, Offset(v.Uint32(i)))
+}
+
+// Bytes returns the bytes at element i of a vector of offsets without
+// copying them.
+func (v Vector) Bytes(i int) ([]byte, error) {
+	return getBytes(v.data, Offset(v.Uint32(i)))
+}
+
+// String returns the string at element i of a vector of offsets.
+func (v Vector) String(i int) (string, error) {
+	b, err := v.Bytes(i)
+	return string(b), err
+}
@@ -0,0 +1,81 @@
+package flatbuf
+
+import (
+	"testing"
+
+	"github.com/funny/binary"
+	"github.com/funny/utest"
+)
+
+func Test_Vector_Scalars(t *testing.T) {
+	var buf binary.Buffer
+	b := NewBuilder(&buf)
+	off, elems := b.CreateVector(4, 3)
+	for i := 0; i < 3; i++ {
+		binary.PutUint32LE(elems[i*4:], uint32(i*100))
+	}
+	f64, elems := b.CreateVector(8, 1)
+	binary.PutFloat64LE(elems, 0.5)
+	b.StartTable(3)
+	b.AddOffset(0, off)
+	b.AddOffset(2, f64)
+	msg, err := b.Finish(b.EndTable())
+	utest.IsNilNow(t, err)
+
+	root, err := GetRoot(msg)
+	utest.IsNilNow(t, err)
+	v, err := root.Vector(0, 4)
+	utest.IsNilNow(t, err)
+	utest.EqualNow(t, v.Len(), 3)
+	utest.EqualNow(t, v.Uint32(2), uint32(200))
+	utest.EqualNow(t, v.Int32(1), int32(100))
+	utest.EqualNow(t, len(v.Data()), 12)
+
+	v, err = root.Vector(2, 8)
+	utest.IsNilNow(t, err)
+	utest.EqualNow(t, v.Float64(0), 0.5)
+
+	v, err = root.Vector(1, 4)
+	utest.IsNilNow(t, err)
+	utest.EqualNow(t, v.Len(), 0)
+
+	_, err = root.Vector(0, 1<<20)
+	utest.EqualNow(t, err, ErrOutOfRange)
+}
+
+func Test_Vector_Offsets(t *testing.T) {
+	var buf binary.Buffer
+	b := NewBuilder(&buf)
+	names := []Offset{b.CreateString("a"), b.CreateString("bc")}
+	var items []Offset
+	for i := 0; i < 2; i++ {
+		b.StartTable(2)
+		b.AddOffset(0, names[i])
+		b.AddUint8(1, uint8(i))
+		items = append(items, b.EndTable())
+	}
+	list := b.CreateOffsetVector(items)
+	b.StartTable(1)
+	b.AddOffset(0, list)
+	msg, err := b.Finish(b.EndTable())
+	utest.IsNilNow(t, err)
+
+	root, err := GetRoot(msg)
+	utest.IsNilNow(t, err)
+	v, err := root.Vector(0, 4)
+	utest.IsNilNow(t, err)
+	utest.EqualNow(t, v.Len(), 2)
+	for i, want := range []string{"a", "bc"} {
+		item, err := v.Table(i)
+		utest.IsNilNow(t, err)
+		utest.EqualNow(t, item.Uint8(1, 9), uint8(i))
+		s, err := item.String(0)
+		utest.IsNilNow(t, err)
+		utest.EqualNow(t, s, want)
+	}
+
+	func() {
+		defer func() { utest.NotNilNow(t, recover()) }()
+		v.Uint32(2)
+	}()
+}
@@ -0,0 +1,118 @@
+package binary
+
+import "math"
+
+// Float32ToFloat16 converts f to IEEE 754 binary16 bits, rounding to
+// nearest even. Values too large become Inf, values too small become
+// subnormals or zero, NaNs stay NaNs.
+func Float32ToFloat16(f float32) uint16 {
+	b := math.Float32bits(f)
+	sign := uint16(b>>16) & 0x8000
+	exp := int(b>>23) & 0xFF
+	mant := b & 0x7FFFFF
+
+	if exp == 0xFF {
+		if mant != 0 {
+			return sign | 0x7E00 | uint16(mant>>13)
+		}
+		return sign | 0x7C00
+	}
+
+	e := exp - 127 + 15
+	if e >= 0x1F {
+		return sign | 0x7C00
+	}
+	if e <= 0 {
+		if e < -10 {
+			return sign
+		}
+		mant |= 0x800000
+		shift := uint(14 - e)
+		h := mant >> shift
+		rem := mant & (1<<shift - 1)
+		half := uint32(1) << (shift - 1)
+		if rem > half || (rem == half && h&1 == 1) {
+			h++
+		}
+		return sign | uint16(h)
+	}
+
+	// A carry out of the mantissa bumps the exponent, up to Inf.
+	h := uint32(e)<<10 | mant>>13
+	rem := mant & 0x1FFF
+	if rem > 0x1000 || (rem == 0x1000 && h&1 == 1) {
+		h++
+	}
+	return sign | uint16(h)
+}
+
+// Float16ToFloat32 converts IEEE 754 binary16 bits to float32, which is
+// always exact.
+func Float16ToFloat32(h uint16) float32 {
+	sign := uint32(h&0x8000) << 16
+	exp := uint32(h>>10) & 0x1F
+	mant := uint32(h & 0x3FF)
+
+	switch exp {
+	case 0x1F:
+		return math.Float32frombits(sign | 0x7F800000 | mant<<13)
+	case 0:
+		if mant == 0 {
+			return math.Float32frombits(sign)
+		}
+		e := uint32(127 - 15 + 1)
+		for mant&0x400 == 0 {
+			mant <<= 1
+			e--
+		}
+		return math.Float32frombits(sign | e<<23 | (mant&0x3FF)<<13)
+	}
+	return math.Float32frombits(sign | (exp+127-15)<<23 | mant<<13)
+}
+
+// Float32ToBFloat16 converts f to bfloat16 bits, the high half of a
+// float32, rounding to nearest even.
+func Float32ToBFloat16(f float32) uint16 {
+	b := math.Float32bits(f)
+	if b&0x7FFFFFFF > 0x7F800000 {
+		return uint16(b>>16) | 0x0040
+	}
+	b += 0x7FFF + (b>>16)&1
+	return uint16(b >> 16)
+}
+
+func BFloat16ToFloat32(h uint16) float32 {
+	return math.Float32frombits(uint32(h) << 16)
+}
+
+func GetFloat16BE(b []byte) float32 {
+	return Float16ToFloat32(GetUint16BE(b))
+}
+
+func PutFloat16BE(b []byte, v float32) {
+	PutUint16BE(b, Float32ToFloat16(v))
+}
+
+func GetFloat16LE(b []byte) float32 {
+	return Float16ToFloat32(GetUint16LE(b))
+}
+
+func PutFloat16LE(b []byte, v float32) {
+	PutUint16LE(b, Float32ToFloat16(v))
+}
+
+func GetBFloat16BE(b []byte) float32 {
+	return BFloat16ToFloat32(GetUint16BE(b))
+}
+
+func PutBFloat16BE(b []byte, v float32) {
+	PutUint16BE(b, Float32ToBFloat16(v))
+}
+
+func GetBFloat16LE(b []byte) float32 {
+	return BFloat16ToFloat32(GetUint16LE(b))
+}
+
+func PutBFloat16LE(b []byte, v float32) {
+	PutUint16LE(b, Float32ToBFloat16(v))
+}
@@ -0,0 +1,103 @@
+package binary
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/funny/utest"
+)
+
+func Test_Float16_Vectors(t *testing.T) {
+	for _, c := range []struct {
+		h uint16
+		f float32
+	}{
+		{0x0000, 0},
+		{0x3C00, 1},
+		{0xC000, -2},
+		{0x3555, 0.333251953125},
+		{0x7BFF, 65504},
+		{0x0400, float32(math.Ldexp(1, -14))},
+		{0x03FF, float32(math.Ldexp(1023, -24))},
+		{0x0001, float32(math.Ldexp(1, -24))},
+		{0x7C00, float32(math.Inf(1))},
+		{0xFC00, float32(math.Inf(-1))},
+	} {
+		utest.EqualNow(t, Float16ToFloat32(c.h), c.f)
+		utest.EqualNow(t, Float32ToFloat16(c.f), c.h)
+	}
+
+	utest.EqualNow(t, Float32ToFloat16(float32(math.Copysign(0, -1))), 0x8000)
+	utest.EqualNow(t, math.Signbit(float64(Float16ToFloat32(0x8000))), true)
+	utest.EqualNow(t, math.IsNaN(float64(Float16ToFloat32(0x7E00))), true)
+	utest.EqualNow(t, Float32ToFloat16(float32(math.NaN()))&0x7E00, 0x7E00)
+	utest.EqualNow(t, Float32ToFloat16(math.Float32frombits(0x7F800001))&0x7E00, 0x7E00)
+}
+
+func Test_Float16_Rounding(t *testing.T) {
+	utest.EqualNow(t, Float32ToFloat16(65519), 0x7BFF)
+	utest.EqualNow(t, Float32ToFloat16(65520), 0x7C00)
+	utest.EqualNow(t, Float32ToFloat16(1e10), 0x7C00)
+	utest.EqualNow(t, Float32ToFloat16(1+1.0/2048), 0x3C00)
+	utest.EqualNow(t, Float32ToFloat16(1+3.0/2048), 0x3C02)
+	utest.EqualNow(t, Float32ToFloat16(1+1.0/2048+1.0/65536), 0x3C01)
+	utest.EqualNow(t, Float32ToFloat16(float32(math.Ldexp(1, -25))), 0x0000)
+	utest.EqualNow(t, Float32ToFloat16(float32(math.Ldexp(1.5, -25))), 0x0001)
+	utest.EqualNow(t, Float32ToFloat16(float32(math.Ldexp(3, -25))), 0x0002)
+	utest.EqualNow(t, Float32ToFloat16(float32(math.Ldexp(1, -30))), 0x0000)
+	utest.EqualNow(t, Float32ToFloat16(float32(math.Ldexp(2047, -25))), 0x0400)
+}
+
+func Test_Float16_Exhaustive(t *testing.T) {
+	for i := 0; i <= 0xFFFF; i++ {
+		h := uint16(i)
+		f := Float16ToFloat32(h)
+		if math.IsNaN(float64(f)) {
+			utest.EqualNow(t, h&0x7C00, 0x7C00)
+			continue
+		}
+		utest.EqualNow(t, Float32ToFloat16(f), h)
+		utest.EqualNow(t, Float32ToBFloat16(BFloat16ToFloat32(h)), h)
+	}
+}
+
+func Test_BFloat16_Rounding(t *testing.T) {
+	utest.EqualNow(t, Float32ToBFloat16(1), 0x3F80)
+	utest.EqualNow(t, Float32ToBFloat16(-2), 0xC000)
+	utest.EqualNow(t, Float32ToBFloat16(1+1.0/256), 0x3F80)
+	utest.EqualNow(t, Float32ToBFloat16(1+3.0/256), 0x3F82)
+	utest.EqualNow(t, Float32ToBFloat16(math.MaxFloat32), 0x7F80)
+	utest.EqualNow(t, Float32ToBFloat16(math.Float32frombits(0x7F800001))&0x7FC0, 0x7FC0)
+}
+
+func Test_Float16_ReadWrite(t *testing.T) {
+	ReadWriteTest(t, 10000, func(r *Reader, w *Writer) {
+		v1 := Float16ToFloat32(uint16(rand.Intn(0x7C00)))
+		v2 := BFloat16ToFloat32(uint16(rand.Intn(0x7F80)))
+		w.WriteFloat16BE(v1)
+		w.WriteFloat16LE(v1)
+		w.WriteBFloat16BE(v2)
+		w.WriteBFloat16LE(v2)
+		utest.IsNilNow(t, w.Error())
+
+		utest.EqualNow(t, r.ReadFloat16BE(), v1)
+		utest.EqualNow(t, r.ReadFloat16LE(), v1)
+		utest.EqualNow(t, r.ReadBFloat16BE(), v2)
+		utest.EqualNow(t, r.ReadBFloat16LE(), v2)
+		utest.IsNilNow(t, r.Error())
+	})
+}
+
+func Test_Buffer_Float16(t *testing.T) {
+	var buf = Buffer{Data: make([]byte, 8)}
+	buf.WriteFloat16BE(1)
+	buf.WriteFloat16LE(-2)
+	buf.WriteBFloat16BE(1)
+	buf.WriteBFloat16LE(-2)
+	utest.EqualNow(t, buf.Data, []byte{0x3C, 0x00, 0x00, 0xC0, 0x3F, 0x80, 0x00, 0xC0})
+	utest.EqualNow(t, buf.ReadFloat16BE(), float32(1))
+	utest.EqualNow(t, buf.ReadFloat16LE(), float32(-2))
+	utest.EqualNow(t, buf.ReadBFloat16BE(), float32(1))
+	utest.EqualNow(t, buf.ReadBFloat16LE(), float32(-2))
+}
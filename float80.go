@@ -0,0 +1,116 @@
+package binary
+
+import (
+	"math"
+	"math/bits"
+)
+
+// roundShift returns m >> s rounded to nearest even.
+func roundShift(m uint64, s uint) uint64 {
+	switch {
+	case s == 0:
+		return m
+	case s > 64:
+		return 0
+	case s == 64:
+		if m > 1<<63 {
+			return 1
+		}
+		return 0
+	}
+	q := m >> s
+	rem := m & (1<<s - 1)
+	half := uint64(1) << (s - 1)
+	if rem > half || (rem == half && q&1 == 1) {
+		q++
+	}
+	return q
+}
+
+// Float80ToFloat64 converts an x87 80-bit extended precision number, given
+// as the 16-bit sign and exponent field and the 64-bit significand with its
+// explicit integer bit, to the nearest float64.
+func Float80ToFloat64(se uint16, mant uint64) float64 {
+	sign := uint64(se&0x8000) << 48
+	exp := int(se & 0x7FFF)
+
+	if exp == 0x7FFF {
+		if mant<<1 == 0 {
+			return math.Float64frombits(sign | 0x7FF0000000000000)
+		}
+		return math.Float64frombits(sign | 0x7FF8000000000000 | (mant<<1)>>12)
+	}
+	if mant == 0 {
+		return math.Float64frombits(sign)
+	}
+	if exp == 0 {
+		exp = 1
+	}
+
+	lz := bits.LeadingZeros64(mant)
+	mant <<= uint(lz)
+	e := exp - 16383 - lz
+
+	if e < -1022 {
+		return math.Float64frombits(sign | roundShift(mant, uint(11-1022-e)))
+	}
+	m := roundShift(mant, 11)
+	if m == 1<<53 {
+		m >>= 1
+		e++
+	}
+	if e > 1023 {
+		return math.Float64frombits(sign | 0x7FF0000000000000)
+	}
+	return math.Float64frombits(sign | uint64(e+1023)<<52 | m&(1<<52-1))
+}
+
+// Float64ToFloat80 converts v to x87 80-bit extended precision, which is
+// always exact.
+func Float64ToFloat80(v float64) (se uint16, mant uint64) {
+	b := math.Float64bits(v)
+	se = uint16(b>>48) & 0x8000
+	exp := int(b>>52) & 0x7FF
+	frac := b & (1<<52 - 1)
+
+	switch exp {
+	case 0x7FF:
+		se |= 0x7FFF
+		mant = 1 << 63
+		if frac != 0 {
+			mant |= 1<<62 | frac<<11
+		}
+		return
+	case 0:
+		if frac == 0 {
+			return
+		}
+		lz := bits.LeadingZeros64(frac)
+		se |= uint16(-1022 - (lz - 11) + 16383)
+		mant = frac << uint(lz)
+		return
+	}
+	se |= uint16(exp - 1023 + 16383)
+	mant = 1<<63 | frac<<11
+	return
+}
+
+func GetFloat80BE(b []byte) float64 {
+	return Float80ToFloat64(GetUint16BE(b), GetUint64BE(b[2:]))
+}
+
+func PutFloat80BE(b []byte, v float64) {
+	se, mant := Float64ToFloat80(v)
+	PutUint16BE(b, se)
+	PutUint64BE(b[2:], mant)
+}
+
+func GetFloat80LE(b []byte) float64 {
+	return Float80ToFloat64(GetUint16LE(b[8:]), GetUint64LE(b))
+}
+
+func PutFloat80LE(b []byte, v float64) {
+	se, mant := Float64ToFloat80(v)
+	PutUint64LE(b, mant)
+	PutUint16LE(b[8:], se)
+}
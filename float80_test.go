@@ -0,0 +1,145 @@
+package binary
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/funny/utest"
+)
+
+func Test_Float80_Vectors(t *testing.T) {
+	for _, c := range []struct {
+		f float64
+		b []byte
+	}{
+		{0, []byte{0x00, 0x00, 0, 0, 0, 0, 0, 0, 0, 0}},
+		{1, []byte{0x3F, 0xFF, 0x80, 0, 0, 0, 0, 0, 0, 0}},
+		{-2, []byte{0xC0, 0x00, 0x80, 0, 0, 0, 0, 0, 0, 0}},
+		{44100, []byte{0x40, 0x0E, 0xAC, 0x44, 0, 0, 0, 0, 0, 0}},
+		{48000, []byte{0x40, 0x0E, 0xBB, 0x80, 0, 0, 0, 0, 0, 0}},
+		{math.Pi, []byte{0x40, 0x00, 0xC9, 0x0F, 0xDA, 0xA2, 0x21, 0x68, 0xC0, 0x00}},
+		{math.Inf(1), []byte{0x7F, 0xFF, 0x80, 0, 0, 0, 0, 0, 0, 0}},
+		{math.Inf(-1), []byte{0xFF, 0xFF, 0x80, 0, 0, 0, 0, 0, 0, 0}},
+		{math.SmallestNonzeroFloat64, []byte{0x3B, 0xCD, 0x80, 0, 0, 0, 0, 0, 0, 0}},
+	} {
+		b := make([]byte, 10)
+		PutFloat80BE(b, c.f)
+		utest.EqualNow(t, b, c.b)
+		utest.EqualNow(t, GetFloat80BE(c.b), c.f)
+
+		PutFloat80LE(b, c.f)
+		for i := 0; i < 10; i++ {
+			utest.EqualNow(t, b[i], c.b[9-i])
+		}
+		utest.EqualNow(t, GetFloat80LE(b), c.f)
+	}
+
+	// Extended pi rounds to float64 pi.
+	utest.EqualNow(t, GetFloat80BE([]byte{0x40, 0x00, 0xC9, 0x0F, 0xDA, 0xA2, 0x21, 0x68, 0xC2, 0x35}), math.Pi)
+	utest.EqualNow(t, math.IsNaN(GetFloat80BE([]byte{0x7F, 0xFF, 0xC0, 0, 0, 0, 0, 0, 0, 0})), true)
+	utest.EqualNow(t, math.IsNaN(GetFloat80BE([]byte{0x7F, 0xFF, 0x80, 0, 0, 0, 0, 0, 0, 1})), true)
+}
+
+func Test_Float80_Range(t *testing.T) {
+	// Largest extended value overflows, tiny values underflow to zero.
+	utest.EqualNow(t, Float80ToFloat64(0x7FFE, 1<<64-1), math.Inf(1))
+	utest.EqualNow(t, Float80ToFloat64(0x8001, 1<<63), math.Copysign(0, -1))
+	// Half of the smallest subnormal ties to even zero, above it rounds up.
+	utest.EqualNow(t, Float80ToFloat64(16383-1075, 1<<63), 0)
+	utest.EqualNow(t, Float80ToFloat64(16383-1075, 1<<63|1), math.SmallestNonzeroFloat64)
+	// Rounding carries into the exponent.
+	utest.EqualNow(t, Float80ToFloat64(16383, 1<<64-1), 2)
+	utest.EqualNow(t, Float80ToFloat64(16383+1023, 1<<64-1), math.Inf(1))
+	// Pseudo-denormals keep their value.
+	utest.EqualNow(t, Float80ToFloat64(0, 1<<62), math.Ldexp(1, -16383))
+}
+
+func Test_Float80_RoundTrip(t *testing.T) {
+	for i := 0; i < 1000000; i++ {
+		v := math.Float64frombits(rand.Uint64())
+		se, mant := Float64ToFloat80(v)
+		v2 := Float80ToFloat64(se, mant)
+		if math.IsNaN(v) {
+			utest.EqualNow(t, math.IsNaN(v2), true)
+			continue
+		}
+		utest.EqualNow(t, math.Float64bits(v2), math.Float64bits(v))
+	}
+}
+
+func Test_IBMFloat_Vectors(t *testing.T) {
+	for _, c := range []struct {
+		f float64
+		b uint32
+	}{
+		{0, 0x00000000},
+		{1, 0x41100000},
+		{-1, 0xC1100000},
+		{-118.625, 0xC276A000},
+		{0.1, 0x4019999A},
+		{0.5, 0x40800000},
+		{1.0 / 16, 0x40100000},
+		{math.Ldexp(1, -260), 0x00100000},
+		{math.Ldexp(1<<24-1, 252-24), 0x7FFFFFFF},
+	} {
+		utest.EqualNow(t, Float64ToIBMFloat32(c.f), c.b)
+		if c.f != 0.1 {
+			utest.EqualNow(t, IBMFloat32ToFloat64(c.b), c.f)
+		}
+	}
+
+	utest.EqualNow(t, Float64ToIBMFloat64(1), uint64(0x4110000000000000))
+	utest.EqualNow(t, Float64ToIBMFloat64(0.1), uint64(0x401999999999999A))
+	utest.EqualNow(t, Float64ToIBMFloat64(-118.625), uint64(0xC276A00000000000))
+	utest.EqualNow(t, IBMFloat64ToFloat64(0xC276A00000000000), -118.625)
+
+	utest.EqualNow(t, Float64ToIBMFloat32(1e100), 0x7FFFFFFF)
+	utest.EqualNow(t, Float64ToIBMFloat32(math.Inf(-1)), 0xFFFFFFFF)
+	utest.EqualNow(t, Float64ToIBMFloat32(math.NaN()), 0)
+	utest.EqualNow(t, Float64ToIBMFloat32(1e-100), 0)
+	// Below 16^-65 values are stored unnormalized.
+	utest.EqualNow(t, Float64ToIBMFloat32(math.Ldexp(1, -264)), 0x00010000)
+	// Rounding up carries into the next hex digit.
+	utest.EqualNow(t, Float64ToIBMFloat32(1-math.Ldexp(1, -30)), 0x41100000)
+}
+
+func Test_IBMFloat_RoundTrip(t *testing.T) {
+	// Every normalized single converts to float64 and back unchanged.
+	for exp := uint32(0); exp < 0x80; exp++ {
+		for i := 0; i < 20000; i++ {
+			frac := uint32(rand.Intn(0xF00000)) + 0x100000
+			b := rand.Uint32()&0x80000000 | exp<<24 | frac
+			utest.EqualNow(t, Float64ToIBMFloat32(IBMFloat32ToFloat64(b)), b)
+		}
+	}
+
+	// Every float64 within range survives the 56-bit double format.
+	for i := 0; i < 1000000; i++ {
+		v := math.Ldexp(rand.Float64()+0.5, rand.Intn(500)-250)
+		if rand.Intn(2) == 0 {
+			v = -v
+		}
+		utest.EqualNow(t, IBMFloat64ToFloat64(Float64ToIBMFloat64(v)), v)
+	}
+}
+
+func Test_Float80_ReadWrite(t *testing.T) {
+	ReadWriteTest(t, 10000, func(r *Reader, w *Writer) {
+		v1 := math.Float64frombits(rand.Uint64()&^(0x7FF<<52) | uint64(rand.Intn(0x7FF))<<52)
+		v2 := IBMFloat32ToFloat64(rand.Uint32())
+		v3 := math.Ldexp(rand.Float64(), rand.Intn(400)-200)
+
+		w.WriteFloat80BE(v1)
+		w.WriteFloat80LE(v1)
+		w.WriteIBMFloat32BE(v2)
+		w.WriteIBMFloat64BE(v3)
+		utest.IsNilNow(t, w.Error())
+
+		utest.EqualNow(t, r.ReadFloat80BE(), v1)
+		utest.EqualNow(t, r.ReadFloat80LE(), v1)
+		utest.EqualNow(t, r.ReadIBMFloat32BE(), v2)
+		utest.EqualNow(t, r.ReadIBMFloat64BE(), v3)
+		utest.IsNilNow(t, r.Error())
+	})
+}
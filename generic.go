@@ -0,0 +1,96 @@
+//go:build go1.18
+// +build go1.18
+
+package binary
+
+// Numeric is the set of types handled by Read, Write, ReadSlice and
+// WriteSlice. int and uint always take 8 bytes, like ReadIntBE.
+type Numeric interface {
+	int8 | int16 | int32 | int64 | int |
+		uint8 | uint16 | uint32 | uint64 | uint |
+		float32 | float64
+}
+
+func Read[T Numeric](r BinaryReader, order ByteOrder) T {
+	var v T
+	or := OrderedReader{BinaryReader: r, Order: order}
+	switch p := any(&v).(type) {
+	case *int8:
+		*p = r.ReadInt8()
+	case *uint8:
+		*p = r.ReadUint8()
+	case *int16:
+		*p = or.ReadInt16()
+	case *uint16:
+		*p = or.ReadUint16()
+	case *int32:
+		*p = or.ReadInt32()
+	case *uint32:
+		*p = or.ReadUint32()
+	case *int64:
+		*p = or.ReadInt64()
+	case *uint64:
+		*p = or.ReadUint64()
+	case *int:
+		*p = or.ReadInt()
+	case *uint:
+		*p = or.ReadUint()
+	case *float32:
+		*p = or.ReadFloat32()
+	case *float64:
+		*p = or.ReadFloat64()
+	}
+	return v
+}
+
+func Write[T Numeric](w BinaryWriter, order ByteOrder, v T) {
+	ow := OrderedWriter{BinaryWriter: w, Order: order}
+	switch x := any(v).(type) {
+	case int8:
+		w.WriteInt8(x)
+	case uint8:
+		w.WriteUint8(x)
+	case int16:
+		ow.WriteInt16(x)
+	case uint16:
+		ow.WriteUint16(x)
+	case int32:
+		ow.WriteInt32(x)
+	case uint32:
+		ow.WriteUint32(x)
+	case int64:
+		ow.WriteInt64(x)
+	case uint64:
+		ow.WriteUint64(x)
+	case int:
+		ow.WriteInt(x)
+	case uint:
+		ow.WriteUint(x)
+	case float32:
+		ow.WriteFloat32(x)
+	case float64:
+		ow.WriteFloat64(x)
+	}
+}
+
+// ReadSlice reads n values, it stops early when r reports an error and
+// leaves the rest of the slice zeroed.
+func ReadSlice[T Numeric](r BinaryReader, order ByteOrder, n int) []T {
+	s := make([]T, n)
+	for i := range s {
+		if r.Error() != nil {
+			break
+		}
+		s[i] = Read[T](r, order)
+	}
+	return s
+}
+
+func WriteSlice[T Numeric](w BinaryWriter, order ByteOrder, s []T) {
+	for _, v := range s {
+		if w.Error() != nil {
+			break
+		}
+		Write(w, order, v)
+	}
+}
@@ -0,0 +1,58 @@
+//go:build go1.18
+// +build go1.18
+
+package binary
+
+import (
+	"bytes"
+	"math/rand"
+	"testing"
+
+	"github.com/funny/utest"
+)
+
+func GenericTest[T Numeric](t *testing.T, order ByteOrder, rnd func() T) {
+	var buf bytes.Buffer
+	w := NewWriter(&buf)
+	r := NewReader(&buf)
+	for i := 0; i < 1000; i++ {
+		s := make([]T, rand.Intn(16))
+		for j := range s {
+			s[j] = rnd()
+		}
+		v := rnd()
+		Write(w, order, v)
+		WriteSlice(w, order, s)
+		utest.IsNilNow(t, w.Error())
+
+		utest.EqualNow(t, Read[T](r, order), v)
+		utest.EqualNow(t, ReadSlice[T](r, order, len(s)), s)
+		utest.IsNilNow(t, r.Error())
+	}
+}
+
+func Test_Generic_ReadWrite(t *testing.T) {
+	for _, order := range []ByteOrder{BigEndian, LittleEndian} {
+		GenericTest(t, order, func() int8 { return int8(rand.Intn(256)) })
+		GenericTest(t, order, func() uint8 { return uint8(rand.Intn(256)) })
+		GenericTest(t, order, func() int16 { return int16(rand.Intn(0xFFFF)) })
+		GenericTest(t, order, func() uint16 { return uint16(rand.Intn(0xFFFF)) })
+		GenericTest(t, order, func() int32 { return int32(rand.Uint32()) })
+		GenericTest(t, order, func() uint32 { return rand.Uint32() })
+		GenericTest(t, order, func() int64 { return int64(rand.Uint64()) })
+		GenericTest(t, order, func() uint64 { return rand.Uint64() })
+		GenericTest(t, order, func() int { return int(rand.Uint64()) })
+		GenericTest(t, order, func() uint { return uint(rand.Uint64()) })
+		GenericTest(t, order, func() float32 { return rand.Float32() })
+		GenericTest(t, order, func() float64 { return rand.Float64() })
+	}
+}
+
+func Test_Generic_Order(t *testing.T) {
+	var buf = Buffer{Data: make([]byte, 8)}
+	Write(&buf, BigEndian, uint32(0x01020304))
+	Write(&buf, LittleEndian, uint32(0x01020304))
+	utest.EqualNow(t, buf.Data, []byte{1, 2, 3, 4, 4, 3, 2, 1})
+	utest.EqualNow(t, ReadSlice[uint16](&buf, BigEndian, 2), []uint16{0x0102, 0x0304})
+	utest.EqualNow(t, Read[uint32](&buf, LittleEndian), uint32(0x01020304))
+}
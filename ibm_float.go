@@ -0,0 +1,92 @@
+package binary
+
+import "math"
+
+// IBM System/360 hexadecimal floating point: a sign bit, a 7-bit base 16
+// exponent biased by 64 and a 24 or 56-bit fraction. The format has no
+// Inf or NaN, values too large saturate to the largest magnitude, values
+// too small lose precision down to zero, NaN is written as zero.
+
+func ibmToFloat64(b uint64, fracBits uint) float64 {
+	sign := b >> (fracBits + 7)
+	exp := int(b>>fracBits) & 0x7F
+	frac := b & (1<<fracBits - 1)
+	v := math.Ldexp(float64(frac), 4*(exp-64)-int(fracBits))
+	if sign != 0 {
+		v = -v
+	}
+	return v
+}
+
+func float64ToIBM(v float64, fracBits uint) uint64 {
+	var sign uint64
+	if math.Signbit(v) {
+		sign = 1 << (fracBits + 7)
+		v = -v
+	}
+	if v == 0 || math.IsNaN(v) {
+		return sign
+	}
+	if math.IsInf(v, 0) {
+		return sign | 0x7F<<fracBits | (1<<fracBits - 1)
+	}
+
+	frac, e := math.Frexp(v)
+	q := (e + 3) >> 2
+	r := uint(4*q - e)
+	m := uint64(math.Ldexp(frac, 53))
+	if fracBits-r >= 53 {
+		m <<= fracBits - r - 53
+	} else {
+		m = roundShift(m, 53-(fracBits-r))
+		if m == 1<<fracBits {
+			m >>= 4
+			q++
+		}
+	}
+
+	exp := q + 64
+	if exp > 0x7F {
+		return sign | 0x7F<<fracBits | (1<<fracBits - 1)
+	}
+	if exp < 0 {
+		if -exp > 16 {
+			return sign
+		}
+		m = roundShift(m, uint(-4*exp))
+		exp = 0
+	}
+	return sign | uint64(exp)<<fracBits | m
+}
+
+func IBMFloat32ToFloat64(b uint32) float64 {
+	return ibmToFloat64(uint64(b), 24)
+}
+
+func Float64ToIBMFloat32(v float64) uint32 {
+	return uint32(float64ToIBM(v, 24))
+}
+
+func IBMFloat64ToFloat64(b uint64) float64 {
+	return ibmToFloat64(b, 56)
+}
+
+func Float64ToIBMFloat64(v float64) uint64 {
+	return float64ToIBM(v, 56)
+}
+
+func GetIBMFloat32BE(b []byte) float64 {
+	return IBMFloat32ToFloat64(GetUint32BE(b))
+}
+
+func PutIBMFloat32BE(b []byte, v float64) {
+	PutUint32BE(b, Float64ToIBMFloat32(v))
+}
+
+func GetIBMFloat64BE(b []byte) float64 {
+	return IBMFloat64ToFloat64(GetUint64BE(b))
+}
+
+func PutIBMFloat64BE(b []byte, v float64) {
+	PutUint64BE(b, Float64ToIBMFloat64(v))
+}
@@ -0,0 +1,95 @@
+// Package structs holds the struct tag handling shared by the codec
+// subpackages.
+package structs
+
+import (
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// Field is an encoded struct field. Untagged anonymous struct fields are
+// flattened into their parent, so Index may be a path.
+type Field struct {
+	Name      string
+	Index     []int
+	OmitEmpty bool
+}
+
+type cacheKey struct {
+	t   reflect.Type
+	tag string
+}
+
+var cache sync.Map
+
+// Fields lists the exported fields of t under the struct tag key tag. The
+// tag renames a field, "-" skips it and the "omitempty" option is recorded.
+func Fields(t reflect.Type, tag string) []Field {
+	key := cacheKey{t, tag}
+	if f, ok := cache.Load(key); ok {
+		return f.([]Field)
+	}
+	var fields []Field
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		tv := sf.Tag.Get(tag)
+		if tv == "-" {
+			continue
+		}
+		name, opts := tv, ""
+		if i := strings.IndexByte(tv, ','); i >= 0 {
+			name, opts = tv[:i], tv[i+1:]
+		}
+		if sf.Anonymous && name == "" && sf.Type.Kind() == reflect.Struct {
+			for _, f := range Fields(sf.Type, tag) {
+				f.Index = append([]int{i}, f.Index...)
+				fields = append(fields, f)
+			}
+			continue
+		}
+		if sf.PkgPath != "" {
+			continue
+		}
+		if name == "" {
+			name = sf.Name
+		}
+		fields = append(fields, Field{
+			Name:      name,
+			Index:     []int{i},
+			OmitEmpty: strings.Contains(","+opts+",", ",omitempty,"),
+		})
+	}
+	cache.Store(key, fields)
+	return fields
+}
+
+// Find returns the field encoded as name, or nil.
+func Find(fields []Field, name string) *Field {
+	for i := range fields {
+		if fields[i].Name == name {
+			return &fields[i]
+		}
+	}
+	return nil
+}
+
+// IsEmpty reports whether v is empty in the sense of omitempty, the same
+// rule as encoding/json.
+func IsEmpty(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool:
+		return !v.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int() == 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return v.Uint() == 0
+	case reflect.Float32, reflect.Float64:
+		return v.Float() == 0
+	case reflect.Interface, reflect.Ptr:
+		return v.IsNil()
+	}
+	return false
+}
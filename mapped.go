@@ -0,0 +1,560 @@
+package binary
+
+import (
+	"errors"
+	"io"
+	"net"
+)
+
+var ErrMappedClosed = errors.New("funny/binary.MappedFile: file closed")
+
+// MappedFile is a read-only view of a memory mapped file. Reads are bounds
+// checked, the first failure is kept and returned by Error, later reads
+// return zero values.
+type MappedFile struct {
+	Data    []byte
+	ReadPos int
+	err     error
+}
+
+func (m *MappedFile) Error() error {
+	return m.err
+}
+
+func (m *MappedFile) Size() int64 {
+	return int64(len(m.Data))
+}
+
+func (m *MappedFile) Offset() int64 {
+	return int64(m.ReadPos)
+}
+
+// Seek implements io.Seeker.
+func (m *MappedFile) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	case io.SeekStart:
+	case io.SeekCurrent:
+		offset += int64(m.ReadPos)
+	case io.SeekEnd:
+		offset += int64(len(m.Data))
+	default:
+		return int64(m.ReadPos), ErrInvalidWhence
+	}
+	if offset < 0 || offset > int64(len(m.Data)) {
+		return int64(m.ReadPos), ErrInvalidOffset
+	}
+	m.ReadPos = int(offset)
+	return offset, nil
+}
+
+func (m *MappedFile) check(off int64, n int) bool {
+	if m.err != nil {
+		return false
+	}
+	if off < 0 || off > int64(len(m.Data)) {
+		m.err = ErrInvalidOffset
+		return false
+	}
+	if remain := len(m.Data) - int(off); n > remain {
+		m.err = shortRead(remain, nil)
+		return false
+	}
+	return true
+}
+
+func (m *MappedFile) at(off int64, n int) []byte {
+	if m.check(off, n) {
+		return m.Data[off : int(off)+n]
+	}
+	return zero[:n]
+}
+
+func (m *MappedFile) seek(n int) (b []byte) {
+	b = m.at(int64(m.ReadPos), n)
+	if m.err == nil {
+		m.ReadPos += n
+	}
+	return
+}
+
+func (m *MappedFile) Read(b []byte) (int, error) {
+	if m.err != nil {
+		return 0, m.err
+	}
+	if m.ReadPos >= len(m.Data) {
+		return 0, io.EOF
+	}
+	n := copy(b, m.Data[m.ReadPos:])
+	m.ReadPos += n
+	return n, nil
+}
+
+// ReadAt implements io.ReaderAt, it does not touch the sticky error.
+func (m *MappedFile) ReadAt(b []byte, off int64) (int, error) {
+	if m.err == ErrMappedClosed {
+		return 0, m.err
+	}
+	if off < 0 {
+		return 0, ErrInvalidOffset
+	}
+	if off >= int64(len(m.Data)) {
+		return 0, io.EOF
+	}
+	n := copy(b, m.Data[off:])
+	if n < len(b) {
+		return n, io.EOF
+	}
+	return n, nil
+}
+
+func (m *MappedFile) ReadByte() (byte, error) {
+	return m.ReadUint8(), m.err
+}
+
+// ReadBytesAt returns a copy of n bytes at off, the copy stays valid after Close.
+func (m *MappedFile) ReadBytesAt(off int64, n int) (b []byte) {
+	b = make([]byte, n)
+	if m.check(off, n) {
+		copy(b, m.Data[off:])
+	}
+	return
+}
+
+func (m *MappedFile) ReadBytes(n int) (b []byte) {
+	b = m.ReadBytesAt(int64(m.ReadPos), n)
+	if m.err == nil {
+		m.ReadPos += n
+	}
+	return
+}
+
+func (m *MappedFile) ReadStringAt(off int64, n int) string {
+	if m.check(off, n) {
+		return string(m.Data[off : int(off)+n])
+	}
+	return ""
+}
+
+func (m *MappedFile) ReadString(n int) (s string) {
+	s = m.ReadStringAt(int64(m.ReadPos), n)
+	if m.err == nil {
+		m.ReadPos += n
+	}
+	return
+}
+
+func (m *MappedFile) ReadBCD(nDigits int) (v uint64) {
+	if m.err == nil {
+		b := m.ReadBytes(BCDSize(nDigits))
+		if m.err == nil {
+			v, m.err = GetBCD(b, nDigits)
+		}
+	}
+	return
+}
+
+func (m *MappedFile) ReadPackedDecimal(nDigits int) (v int64) {
+	if m.err == nil {
+		b := m.ReadBytes(PackedDecimalSize(nDigits))
+		if m.err == nil {
+			v, m.err = GetPackedDecimal(b, nDigits)
+		}
+	}
+	return
+}
+
+func (m *MappedFile) ReadIPv4() net.IP {
+	return net.IP(m.ReadBytes(net.IPv4len))
+}
+
+func (m *MappedFile) ReadIPv6() net.IP {
+	return net.IP(m.ReadBytes(net.IPv6len))
+}
+
+func (m *MappedFile) ReadMAC() net.HardwareAddr {
+	return net.HardwareAddr(m.ReadBytes(MACLen))
+}
+
+func (m *MappedFile) ReadUUID() UUID {
+	return GetUUID(m.ReadBytes(UUIDLen))
+}
+
+func (m *MappedFile) ReadGUID() UUID {
+	return GetGUID(m.ReadBytes(UUIDLen))
+}
+
+func (m *MappedFile) ReadBools(n int, order BitOrder) []bool {
+	return GetBools(m.ReadBytes(BoolsSize(n)), n, order)
+}
+
+func (m *MappedFile) ReadUvarint() (v uint64) {
+	if m.err == nil {
+		v, m.err = ReadUvarint(m)
+	}
+	return
+}
+
+func (m *MappedFile) ReadVarint() (v int64) {
+	if m.err == nil {
+		v, m.err = ReadVarint(m)
+	}
+	return
+}
+
+func (m *MappedFile) ReadQuicVarint() (v uint64) {
+	if m.err == nil {
+		v, m.err = ReadQuicVarint(m)
+	}
+	return
+}
+
+func (m *MappedFile) ReadSQLiteVarint() (v uint64) {
+	if m.err == nil {
+		v, m.err = ReadSQLiteVarint(m)
+	}
+	return
+}
+
+func (m *MappedFile) ReadVLQ() (v uint64) {
+	if m.err == nil {
+		v, m.err = ReadVLQ(m)
+	}
+	return
+}
+
+func (m *MappedFile) ReadGitVarint() (v uint64) {
+	if m.err == nil {
+		v, m.err = ReadGitVarint(m)
+	}
+	return
+}
+
+func (m *MappedFile) ReadUvarint128() (v Uint128) {
+	if m.err == nil {
+		v, m.err = ReadUvarint128(m)
+	}
+	return
+}
+
+func (m *MappedFile) ReadVarint128() (v Int128) {
+	if m.err == nil {
+		v, m.err = ReadVarint128(m)
+	}
+	return
+}
+
+func (m *MappedFile) ReadUint8() uint8 {
+	return uint8(m.seek(1)[0])
+}
+
+func (m *MappedFile) ReadUint16BE() uint16 {
+	return GetUint16BE(m.seek(2))
+}
+
+func (m *MappedFile) ReadUint16LE() uint16 {
+	return GetUint16LE(m.seek(2))
+}
+
+func (m *MappedFile) ReadUint24BE() uint32 {
+	return GetUint24BE(m.seek(3))
+}
+
+func (m *MappedFile) ReadUint24LE() uint32 {
+	return GetUint24LE(m.seek(3))
+}
+
+func (m *MappedFile) ReadUint32BE() uint32 {
+	return GetUint32BE(m.seek(4))
+}
+
+func (m *MappedFile) ReadUint32LE() uint32 {
+	return GetUint32LE(m.seek(4))
+}
+
+func (m *MappedFile) ReadUint40BE() uint64 {
+	return GetUint40BE(m.seek(5))
+}
+
+func (m *MappedFile) ReadUint40LE() uint64 {
+	return GetUint40LE(m.seek(5))
+}
+
+func (m *MappedFile) ReadUint48BE() uint64 {
+	return GetUint48BE(m.seek(6))
+}
+
+func (m *MappedFile) ReadUint48LE() uint64 {
+	return GetUint48LE(m.seek(6))
+}
+
+func (m *MappedFile) ReadUint56BE() uint64 {
+	return GetUint56BE(m.seek(7))
+}
+
+func (m *MappedFile) ReadUint56LE() uint64 {
+	return GetUint56LE(m.seek(7))
+}
+
+func (m *MappedFile) ReadUint64BE() uint64 {
+	return GetUint64BE(m.seek(8))
+}
+
+func (m *MappedFile) ReadUint64LE() uint64 {
+	return GetUint64LE(m.seek(8))
+}
+
+func (m *MappedFile) ReadFloat32BE() float32 {
+	return GetFloat32BE(m.seek(4))
+}
+
+func (m *MappedFile) ReadFloat32LE() float32 {
+	return GetFloat32LE(m.seek(4))
+}
+
+func (m *MappedFile) ReadFloat64BE() float64 {
+	return GetFloat64BE(m.seek(8))
+}
+
+func (m *MappedFile) ReadFloat64LE() float64 {
+	return GetFloat64LE(m.seek(8))
+}
+
+func (m *MappedFile) ReadComplex64BE() complex64 {
+	return GetComplex64BE(m.seek(8))
+}
+
+func (m *MappedFile) ReadComplex64LE() complex64 {
+	return GetComplex64LE(m.seek(8))
+}
+
+func (m *MappedFile) ReadComplex128BE() complex128 {
+	return GetComplex128BE(m.seek(16))
+}
+
+func (m *MappedFile) ReadComplex128LE() complex128 {
+	return GetComplex128LE(m.seek(16))
+}
+
+func (m *MappedFile) ReadFloat80BE() float64 {
+	return GetFloat80BE(m.seek(10))
+}
+
+func (m *MappedFile) ReadFloat80LE() float64 {
+	return GetFloat80LE(m.seek(10))
+}
+
+func (m *MappedFile) ReadIBMFloat32BE() float64 {
+	return GetIBMFloat32BE(m.seek(4))
+}
+
+func (m *MappedFile) ReadIBMFloat64BE() float64 {
+	return GetIBMFloat64BE(m.seek(8))
+}
+
+func (m *MappedFile) ReadUint128BE() Uint128 {
+	return GetUint128BE(m.seek(16))
+}
+
+func (m *MappedFile) ReadUint128LE() Uint128 {
+	return GetUint128LE(m.seek(16))
+}
+
+func (m *MappedFile) ReadInt128BE() Int128 {
+	return GetInt128BE(m.seek(16))
+}
+
+func (m *MappedFile) ReadInt128LE() Int128 {
+	return GetInt128LE(m.seek(16))
+}
+
+func (m *MappedFile) ReadFloat16BE() float32 {
+	return GetFloat16BE(m.seek(2))
+}
+
+func (m *MappedFile) ReadFloat16LE() float32 {
+	return GetFloat16LE(m.seek(2))
+}
+
+func (m *MappedFile) ReadBFloat16BE() float32 {
+	return GetBFloat16BE(m.seek(2))
+}
+
+func (m *MappedFile) ReadBFloat16LE() float32 {
+	return GetBFloat16LE(m.seek(2))
+}
+
+func (m *MappedFile) ReadInt8() int8     { return int8(m.ReadUint8()) }
+func (m *MappedFile) ReadInt16BE() int16 { return int16(m.ReadUint16BE()) }
+func (m *MappedFile) ReadInt16LE() int16 { return int16(m.ReadUint16LE()) }
+func (m *MappedFile) ReadInt24BE() int32 { return int32(m.ReadUint24BE()) }
+func (m *MappedFile) ReadInt24LE() int32 { return int32(m.ReadUint24LE()) }
+func (m *MappedFile) ReadInt32BE() int32 { return int32(m.ReadUint32BE()) }
+func (m *MappedFile) ReadInt32LE() int32 { return int32(m.ReadUint32LE()) }
+func (m *MappedFile) ReadInt40BE() int64 { return int64(m.ReadUint40BE()) }
+func (m *MappedFile) ReadInt40LE() int64 { return int64(m.ReadUint40LE()) }
+func (m *MappedFile) ReadInt48BE() int64 { return int64(m.ReadUint48BE()) }
+func (m *MappedFile) ReadInt48LE() int64 { return int64(m.ReadUint48LE()) }
+func (m *MappedFile) ReadInt56BE() int64 { return int64(m.ReadUint56BE()) }
+func (m *MappedFile) ReadInt56LE() int64 { return int64(m.ReadUint56LE()) }
+func (m *MappedFile) ReadInt64BE() int64 { return int64(m.ReadUint64BE()) }
+func (m *MappedFile) ReadInt64LE() int64 { return int64(m.ReadUint64LE()) }
+func (m *MappedFile) ReadIntBE() int     { return int(m.ReadUint64BE()) }
+func (m *MappedFile) ReadIntLE() int     { return int(m.ReadUint64LE()) }
+func (m *MappedFile) ReadUintBE() uint   { return uint(m.ReadUint64BE()) }
+func (m *MappedFile) ReadUintLE() uint   { return uint(m.ReadUint64LE()) }
+
+func (m *MappedFile) ReadUint8At(off int64) uint8 {
+	return uint8(m.at(off, 1)[0])
+}
+
+func (m *MappedFile) ReadUint16BEAt(off int64) uint16 {
+	return GetUint16BE(m.at(off, 2))
+}
+
+func (m *MappedFile) ReadUint16LEAt(off int64) uint16 {
+	return GetUint16LE(m.at(off, 2))
+}
+
+func (m *MappedFile) ReadUint24BEAt(off int64) uint32 {
+	return GetUint24BE(m.at(off, 3))
+}
+
+func (m *MappedFile) ReadUint24LEAt(off int64) uint32 {
+	return GetUint24LE(m.at(off, 3))
+}
+
+func (m *MappedFile) ReadUint32BEAt(off int64) uint32 {
+	return GetUint32BE(m.at(off, 4))
+}
+
+func (m *MappedFile) ReadUint32LEAt(off int64) uint32 {
+	return GetUint32LE(m.at(off, 4))
+}
+
+func (m *MappedFile) ReadUint40BEAt(off int64) uint64 {
+	return GetUint40BE(m.at(off, 5))
+}
+
+func (m *MappedFile) ReadUint40LEAt(off int64) uint64 {
+	return GetUint40LE(m.at(off, 5))
+}
+
+func (m *MappedFile) ReadUint48BEAt(off int64) uint64 {
+	return GetUint48BE(m.at(off, 6))
+}
+
+func (m *MappedFile) ReadUint48LEAt(off int64) uint64 {
+	return GetUint48LE(m.at(off, 6))
+}
+
+func (m *MappedFile) ReadUint56BEAt(off int64) uint64 {
+	return GetUint56BE(m.at(off, 7))
+}
+
+func (m *MappedFile) ReadUint56LEAt(off int64) uint64 {
+	return GetUint56LE(m.at(off, 7))
+}
+
+func (m *MappedFile) ReadUint64BEAt(off int64) uint64 {
+	return GetUint64BE(m.at(off, 8))
+}
+
+func (m *MappedFile) ReadUint64LEAt(off int64) uint64 {
+	return GetUint64LE(m.at(off, 8))
+}
+
+func (m *MappedFile) ReadFloat32BEAt(off int64) float32 {
+	return GetFloat32BE(m.at(off, 4))
+}
+
+func (m *MappedFile) ReadFloat32LEAt(off int64) float32 {
+	return GetFloat32LE(m.at(off, 4))
+}
+
+func (m *MappedFile) ReadFloat64BEAt(off int64) float64 {
+	return GetFloat64BE(m.at(off, 8))
+}
+
+func (m *MappedFile) ReadFloat64LEAt(off int64) float64 {
+	return GetFloat64LE(m.at(off, 8))
+}
+
+func (m *MappedFile) ReadComplex64BEAt(off int64) complex64 {
+	return GetComplex64BE(m.at(off, 8))
+}
+
+func (m *MappedFile) ReadComplex64LEAt(off int64) complex64 {
+	return GetComplex64LE(m.at(off, 8))
+}
+
+func (m *MappedFile) ReadComplex128BEAt(off int64) complex128 {
+	return GetComplex128BE(m.at(off, 16))
+}
+
+func (m *MappedFile) ReadComplex128LEAt(off int64) complex128 {
+	return GetComplex128LE(m.at(off, 16))
+}
+
+func (m *MappedFile) ReadFloat80BEAt(off int64) float64 {
+	return GetFloat80BE(m.at(off, 10))
+}
+
+func (m *MappedFile) ReadFloat80LEAt(off int64) float64 {
+	return GetFloat80LE(m.at(off, 10))
+}
+
+func (m *MappedFile) ReadIBMFloat32BEAt(off int64) float64 {
+	return GetIBMFloat32BE(m.at(off, 4))
+}
+
+func (m *MappedFile) ReadIBMFloat64BEAt(off int64) float64 {
+	return GetIBMFloat64BE(m.at(off, 8))
+}
+
+func (m *MappedFile) ReadUint128BEAt(off int64) Uint128 {
+	return GetUint128BE(m.at(off, 16))
+}
+
+func (m *MappedFile) ReadUint128LEAt(off int64) Uint128 {
+	return GetUint128LE(m.at(off, 16))
+}
+
+func (m *MappedFile) ReadInt128BEAt(off int64) Int128 {
+	return GetInt128BE(m.at(off, 16))
+}
+
+func (m *MappedFile) ReadInt128LEAt(off int64) Int128 {
+	return GetInt128LE(m.at(off, 16))
+}
+
+func (m *MappedFile) ReadFloat16BEAt(off int64) float32 {
+	return GetFloat16BE(m.at(off, 2))
+}
+
+func (m *MappedFile) ReadFloat16LEAt(off int64) float32 {
+	return GetFloat16LE(m.at(off, 2))
+}
+
+func (m *MappedFile) ReadBFloat16BEAt(off int64) float32 {
+	return GetBFloat16BE(m.at(off, 2))
+}
+
+func (m *MappedFile) ReadBFloat16LEAt(off int64) float32 {
+	return GetBFloat16LE(m.at(off, 2))
+}
+
+func (m *MappedFile) ReadInt8At(off int64) int8     { return int8(m.ReadUint8At(off)) }
+func (m *MappedFile) ReadInt16BEAt(off int64) int16 { return int16(m.ReadUint16BEAt(off)) }
+func (m *MappedFile) ReadInt16LEAt(off int64) int16 { return int16(m.ReadUint16LEAt(off)) }
+func (m *MappedFile) ReadInt24BEAt(off int64) int32 { return int32(m.ReadUint24BEAt(off)) }
+func (m *MappedFile) ReadInt24LEAt(off int64) int32 { return int32(m.ReadUint24LEAt(off)) }
+func (m *MappedFile) ReadInt32BEAt(off int64) int32 { return int32(m.ReadUint32BEAt(off)) }
+func (m *MappedFile) ReadInt32LEAt(off int64) int32 { return int32(m.ReadUint32LEAt(off)) }
+func (m *MappedFile) ReadInt40BEAt(off int64) int64 { return int64(m.ReadUint40BEAt(off)) }
+func (m *MappedFile) ReadInt40LEAt(off int64) int64 { return int64(m.ReadUint40LEAt(off)) }
+func (m *MappedFile) ReadInt48BEAt(off int64) int64 { return int64(m.ReadUint48BEAt(off)) }
+func (m *MappedFile) ReadInt48LEAt(off int64) int64 { return int64(m.ReadUint48LEAt(off)) }
+func (m *MappedFile) ReadInt56BEAt(off int64) int64 { return int64(m.ReadUint56BEAt(off)) }
+func (m *MappedFile) ReadInt56LEAt(off int64) int64 { return int64(m.ReadUint56LEAt(off)) }
+func (m *MappedFile) ReadInt64BEAt(off int64) int64 { return int64(m.ReadUint64BEAt(off)) }
+func (m *MappedFile) ReadInt64LEAt(off int64) int64 { return int64(m.ReadUint64LEAt(off)) }
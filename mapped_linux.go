@@ -0,0 +1,53 @@
+package binary
+
+import (
+	"errors"
+	"os"
+	"syscall"
+)
+
+var ErrMappedTooLarge = errors.New("funny/binary.MappedFile: file too large to map")
+
+// OpenMapped maps the whole file into memory read-only. The file
+// descriptor is closed before returning, the mapping lives until Close.
+func OpenMapped(path string) (*MappedFile, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	size := info.Size()
+	if size == 0 {
+		return &MappedFile{}, nil
+	}
+	if int64(int(size)) != size || size < 0 {
+		return nil, ErrMappedTooLarge
+	}
+
+	data, err := syscall.Mmap(int(f.Fd()), 0, int(size), syscall.PROT_READ, syscall.MAP_SHARED)
+	if err != nil {
+		return nil, &os.PathError{Op: "mmap", Path: path, Err: err}
+	}
+	return &MappedFile{Data: data}, nil
+}
+
+// Close unmaps the file. Slices taken from Data must not be used after
+// Close, any later read fails with ErrMappedClosed.
+func (m *MappedFile) Close() error {
+	if m.err == ErrMappedClosed {
+		return m.err
+	}
+	var err error
+	if m.Data != nil {
+		err = syscall.Munmap(m.Data)
+	}
+	m.Data = nil
+	m.ReadPos = 0
+	m.err = ErrMappedClosed
+	return err
+}
@@ -0,0 +1,111 @@
+package binary
+
+import (
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"os"
+	"testing"
+
+	"github.com/funny/utest"
+)
+
+func MappedTest(t *testing.T, data []byte) *MappedFile {
+	f, err := ioutil.TempFile("", "funny-binary-mapped")
+	utest.IsNilNow(t, err)
+	defer os.Remove(f.Name())
+
+	_, err = f.Write(data)
+	utest.IsNilNow(t, err)
+	utest.IsNilNow(t, f.Close())
+
+	m, err := OpenMapped(f.Name())
+	utest.IsNilNow(t, err)
+	return m
+}
+
+func Test_Mapped_Read(t *testing.T) {
+	var buf = Buffer{Data: make([]byte, 10000*(2+3+4+8+8))}
+	var v1 = make([]uint16, 10000)
+	var v2 = make([]uint32, 10000)
+	var v3 = make([]uint32, 10000)
+	var v4 = make([]uint64, 10000)
+	var v5 = make([]float64, 10000)
+	for i := 0; i < 10000; i++ {
+		v1[i] = uint16(rand.Intn(0xFFFF))
+		v2[i] = uint32(rand.Intn(0xFFFFFF))
+		v3[i] = uint32(rand.Intn(0xFFFFFFFF))
+		v4[i] = uint64(rand.Int63())
+		v5[i] = rand.Float64()
+		buf.WriteUint16BE(v1[i])
+		buf.WriteUint24LE(v2[i])
+		buf.WriteUint32BE(v3[i])
+		buf.WriteUint64LE(v4[i])
+		buf.WriteFloat64BE(v5[i])
+	}
+
+	m := MappedTest(t, buf.Data)
+	defer m.Close()
+	for i := 0; i < 10000; i++ {
+		utest.EqualNow(t, m.ReadUint16BE(), v1[i])
+		utest.EqualNow(t, m.ReadUint24LE(), v2[i])
+		utest.EqualNow(t, m.ReadUint32BE(), v3[i])
+		utest.EqualNow(t, m.ReadUint64LE(), v4[i])
+		utest.EqualNow(t, m.ReadFloat64BE(), v5[i])
+	}
+	utest.IsNilNow(t, m.Error())
+
+	for i := 0; i < 10000; i++ {
+		j := rand.Intn(10000)
+		off := int64(j * 25)
+		utest.EqualNow(t, m.ReadUint16BEAt(off), v1[j])
+		utest.EqualNow(t, m.ReadUint32BEAt(off+5), v3[j])
+		utest.EqualNow(t, m.ReadFloat64BEAt(off+17), v5[j])
+	}
+	utest.EqualNow(t, m.Offset(), m.Size())
+	utest.IsNilNow(t, m.Error())
+}
+
+func Test_Mapped_Varint(t *testing.T) {
+	var buf = Buffer{Data: make([]byte, 1000*MaxVarintLen64)}
+	var v = make([]int64, 1000)
+	for i := 0; i < 1000; i++ {
+		v[i] = rand.Int63() - rand.Int63()
+		buf.WriteVarint(v[i])
+	}
+
+	m := MappedTest(t, buf.Data[:buf.WritePos])
+	defer m.Close()
+	for i := 0; i < 1000; i++ {
+		utest.EqualNow(t, m.ReadVarint(), v[i])
+	}
+	utest.IsNilNow(t, m.Error())
+
+	m.ReadVarint()
+	utest.EqualNow(t, m.Error(), io.EOF)
+}
+
+func Test_Mapped_Bounds(t *testing.T) {
+	m := MappedTest(t, []byte{1, 2, 3})
+
+	utest.EqualNow(t, m.ReadUint32BEAt(1), 0)
+	utest.EqualNow(t, m.Error(), io.ErrUnexpectedEOF)
+	utest.EqualNow(t, m.ReadUint8At(0), 0)
+	utest.EqualNow(t, m.Offset(), int64(0))
+
+	_, err := m.Seek(4, io.SeekStart)
+	utest.EqualNow(t, err, ErrInvalidOffset)
+
+	utest.IsNilNow(t, m.Close())
+	utest.EqualNow(t, m.Close(), ErrMappedClosed)
+	utest.EqualNow(t, m.Error(), ErrMappedClosed)
+	utest.EqualNow(t, m.ReadUint8(), 0)
+}
+
+func Test_Mapped_Empty(t *testing.T) {
+	m := MappedTest(t, nil)
+	utest.EqualNow(t, m.Size(), int64(0))
+	utest.EqualNow(t, m.ReadUint8(), 0)
+	utest.EqualNow(t, m.Error(), io.EOF)
+	utest.IsNilNow(t, m.Close())
+}
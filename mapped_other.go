@@ -0,0 +1,22 @@
+//go:build !linux
+// +build !linux
+
+package binary
+
+import "errors"
+
+var ErrMappedUnsupported = errors.New("funny/binary.MappedFile: mmap not supported on this platform")
+
+func OpenMapped(path string) (*MappedFile, error) {
+	return nil, ErrMappedUnsupported
+}
+
+func (m *MappedFile) Close() error {
+	if m.err == ErrMappedClosed {
+		return m.err
+	}
+	m.Data = nil
+	m.ReadPos = 0
+	m.err = ErrMappedClosed
+	return nil
+}
@@ -0,0 +1,608 @@
+package msgpack
+
+import (
+	"math"
+	"reflect"
+	"time"
+
+	"github.com/funny/binary"
+	"github.com/funny/binary/internal/structs"
+)
+
+// Decoder reads MessagePack values. Errors of the BinaryReader are returned
+// as they are, the decoder must not be used after an error.
+type Decoder struct {
+	r     binary.BinaryReader
+	depth int
+
+	// MaxLen limits lengths and element counts, zero means DefaultMaxLen.
+	MaxLen int
+}
+
+func NewDecoder(r binary.BinaryReader) *Decoder {
+	return &Decoder{r: r}
+}
+
+func (d *Decoder) maxLen() int {
+	if d.MaxLen > 0 {
+		return d.MaxLen
+	}
+	return DefaultMaxLen
+}
+
+func (d *Decoder) readLen(size int) (int, error) {
+	var n uint64
+	switch size {
+	case 1:
+		n = uint64(d.r.ReadUint8())
+	case 2:
+		n = uint64(d.r.ReadUint16BE())
+	default:
+		n = uint64(d.r.ReadUint32BE())
+	}
+	if err := d.r.Error(); err != nil {
+		return 0, err
+	}
+	if n > uint64(d.maxLen()) {
+		return 0, ErrTooLarge
+	}
+	return int(n), nil
+}
+
+func (d *Decoder) enter() error {
+	d.depth++
+	if d.depth > maxDepth {
+		return ErrTooDeep
+	}
+	return nil
+}
+
+// initialCap keeps a forged element count from allocating up front.
+func initialCap(n int) int {
+	if n > 1024 {
+		return 1024
+	}
+	return n
+}
+
+// arrayLen returns -1 when code does not start an array.
+func (d *Decoder) arrayLen(code byte) (int, error) {
+	switch {
+	case code&0xf0 == fixArray:
+		return int(code & 0x0f), nil
+	case code == codeArray16:
+		return d.readLen(2)
+	case code == codeArray32:
+		return d.readLen(4)
+	}
+	return -1, nil
+}
+
+// mapLen returns -1 when code does not start a map.
+func (d *Decoder) mapLen(code byte) (int, error) {
+	switch {
+	case code&0xf0 == fixMap:
+		return int(code & 0x0f), nil
+	case code == codeMap16:
+		return d.readLen(2)
+	case code == codeMap32:
+		return d.readLen(4)
+	}
+	return -1, nil
+}
+
+// DecodeArrayLen reads the header of an array, a nil reads as -1.
+func (d *Decoder) DecodeArrayLen() (int, error) {
+	code, err := d.r.ReadByte()
+	if err != nil || code == codeNil {
+		return -1, err
+	}
+	n, err := d.arrayLen(code)
+	if err == nil && n < 0 {
+		err = ErrTypeMismatch
+	}
+	return n, err
+}
+
+// DecodeMapLen reads the header of a map, a nil reads as -1.
+func (d *Decoder) DecodeMapLen() (int, error) {
+	code, err := d.r.ReadByte()
+	if err != nil || code == codeNil {
+		return -1, err
+	}
+	n, err := d.mapLen(code)
+	if err == nil && n < 0 {
+		err = ErrTypeMismatch
+	}
+	return n, err
+}
+
+// DecodeInterface reads the next value as nil, bool, int64, uint64 (only
+// for values above math.MaxInt64), float32, float64, string, []byte,
+// time.Time, Ext, []interface{}, or a map[string]interface{} when every key
+// is a string and map[interface{}]interface{} otherwise.
+func (d *Decoder) DecodeInterface() (interface{}, error) {
+	code, err := d.r.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+	return d.decodeInterface(code)
+}
+
+func (d *Decoder) decodeInterface(code byte) (interface{}, error) {
+	switch {
+	case code < fixMap:
+		return int64(code), nil
+	case code >= negFix:
+		return int64(int8(code)), nil
+	case code < fixArray:
+		return d.decodeMap(int(code & 0x0f))
+	case code < fixStr:
+		return d.decodeArray(int(code & 0x0f))
+	case code < codeNil:
+		return d.r.ReadString(int(code & 0x1f)), d.r.Error()
+	}
+	switch code {
+	case codeNil:
+		return nil, nil
+	case codeFalse:
+		return false, nil
+	case codeTrue:
+		return true, nil
+	case codeFloat32:
+		return d.r.ReadFloat32BE(), d.r.Error()
+	case codeFloat64:
+		return d.r.ReadFloat64BE(), d.r.Error()
+	case codeUint8:
+		return int64(d.r.ReadUint8()), d.r.Error()
+	case codeUint16:
+		return int64(d.r.ReadUint16BE()), d.r.Error()
+	case codeUint32:
+		return int64(d.r.ReadUint32BE()), d.r.Error()
+	case codeUint64:
+		v := d.r.ReadUint64BE()
+		if v > math.MaxInt64 {
+			return v, d.r.Error()
+		}
+		return int64(v), d.r.Error()
+	case codeInt8:
+		return int64(d.r.ReadInt8()), d.r.Error()
+	case codeInt16:
+		return int64(d.r.ReadInt16BE()), d.r.Error()
+	case codeInt32:
+		return int64(d.r.ReadInt32BE()), d.r.Error()
+	case codeInt64:
+		return d.r.ReadInt64BE(), d.r.Error()
+	case codeStr8, codeStr16, codeStr32:
+		n, err := d.readLen(1 << (code - codeStr8))
+		if err != nil {
+			return nil, err
+		}
+		return d.r.ReadString(n), d.r.Error()
+	case codeBin8, codeBin16, codeBin32:
+		n, err := d.readLen(1 << (code - codeBin8))
+		if err != nil {
+			return nil, err
+		}
+		return d.r.ReadBytes(n), d.r.Error()
+	case codeArray16, codeArray32:
+		n, err := d.readLen(2 << (code - codeArray16))
+		if err != nil {
+			return nil, err
+		}
+		return d.decodeArray(n)
+	case codeMap16, codeMap32:
+		n, err := d.readLen(2 << (code - codeMap16))
+		if err != nil {
+			return nil, err
+		}
+		return d.decodeMap(n)
+	}
+	typ, data, err := d.readExt(code)
+	if err != nil {
+		return nil, err
+	}
+	if typ == TimestampExt {
+		return decodeTime(data)
+	}
+	return Ext{typ, data}, nil
+}
+
+func (d *Decoder) decodeArray(n int) (interface{}, error) {
+	if err := d.enter(); err != nil {
+		return nil, err
+	}
+	defer func() { d.depth-- }()
+	a := make([]interface{}, 0, initialCap(n))
+	for i := 0; i < n; i++ {
+		v, err := d.DecodeInterface()
+		if err != nil {
+			return nil, err
+		}
+		a = append(a, v)
+	}
+	return a, nil
+}
+
+func (d *Decoder) decodeMap(n int) (interface{}, error) {
+	if err := d.enter(); err != nil {
+		return nil, err
+	}
+	defer func() { d.depth-- }()
+	m := make(map[string]interface{}, initialCap(n))
+	var mi map[interface{}]interface{}
+	for i := 0; i < n; i++ {
+		k, err := d.DecodeInterface()
+		if err != nil {
+			return nil, err
+		}
+		v, err := d.DecodeInterface()
+		if err != nil {
+			return nil, err
+		}
+		if s, ok := k.(string); ok && mi == nil {
+			m[s] = v
+			continue
+		}
+		if !hashable(reflect.ValueOf(k)) {
+			return nil, ErrInvalidMapKey
+		}
+		if mi == nil {
+			mi = make(map[interface{}]interface{}, len(m)+1)
+			for s, v := range m {
+				mi[s] = v
+			}
+		}
+		mi[k] = v
+	}
+	if mi != nil {
+		return mi, nil
+	}
+	return m, nil
+}
+
+func (d *Decoder) readExt(code byte) (typ int8, data []byte, err error) {
+	var n int
+	switch code {
+	case codeFixExt1, codeFixExt2, codeFixExt4, codeFixExt8, codeFixExt16:
+		n = 1 << (code - codeFixExt1)
+	case codeExt8, codeExt16, codeExt32:
+		if n, err = d.readLen(1 << (code - codeExt8)); err != nil {
+			return
+		}
+	default:
+		return 0, nil, ErrInvalidCode
+	}
+	typ = d.r.ReadInt8()
+	data = d.r.ReadBytes(n)
+	return typ, data, d.r.Error()
+}
+
+func decodeTime(b []byte) (time.Time, error) {
+	var sec int64
+	var nsec uint32
+	switch len(b) {
+	case 4:
+		sec = int64(binary.GetUint32BE(b))
+	case 8:
+		v := binary.GetUint64BE(b)
+		sec, nsec = int64(v&(1<<34-1)), uint32(v>>34)
+	case 12:
+		sec, nsec = int64(binary.GetUint64BE(b[4:])), binary.GetUint32BE(b)
+	default:
+		return time.Time{}, ErrInvalidCode
+	}
+	if nsec >= 1e9 {
+		return time.Time{}, ErrInvalidCode
+	}
+	return time.Unix(sec, int64(nsec)).UTC(), nil
+}
+
+// Decode reads the next value into the value pointed to by v. Struct
+// fields are matched by their encoded name, unknown keys are skipped.
+func (d *Decoder) Decode(v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return ErrNotPointer
+	}
+	code, err := d.r.ReadByte()
+	if err != nil {
+		return err
+	}
+	return d.decodeValue(code, rv.Elem())
+}
+
+func (d *Decoder) decodeNext(v reflect.Value) error {
+	code, err := d.r.ReadByte()
+	if err != nil {
+		return err
+	}
+	return d.decodeValue(code, v)
+}
+
+func (d *Decoder) decodeValue(code byte, v reflect.Value) error {
+	if code == codeNil {
+		v.Set(reflect.Zero(v.Type()))
+		return nil
+	}
+	switch v.Type() {
+	case timeType:
+		typ, data, err := d.readExt(code)
+		if err != nil {
+			return err
+		}
+		if typ != TimestampExt {
+			return ErrTypeMismatch
+		}
+		t, err := decodeTime(data)
+		v.Set(reflect.ValueOf(t))
+		return err
+	case extType:
+		typ, data, err := d.readExt(code)
+		v.Set(reflect.ValueOf(Ext{typ, data}))
+		return err
+	}
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		return d.decodeValue(code, v.Elem())
+	case reflect.Interface:
+		if v.NumMethod() == 0 {
+			x, err := d.decodeInterface(code)
+			if err != nil {
+				return err
+			}
+			if x == nil {
+				v.Set(reflect.Zero(v.Type()))
+			} else {
+				v.Set(reflect.ValueOf(x))
+			}
+			return nil
+		}
+		if !v.IsNil() && v.Elem().Kind() == reflect.Ptr {
+			return d.decodeValue(code, v.Elem())
+		}
+		return ErrTypeMismatch
+	case reflect.Slice:
+		if v.Type().Elem().Kind() != reflect.Uint8 {
+			return d.decodeSlice(code, v)
+		}
+	case reflect.Array:
+		if v.Type().Elem().Kind() != reflect.Uint8 {
+			return d.decodeSlice(code, v)
+		}
+	case reflect.Map:
+		return d.decodeMapValue(code, v)
+	case reflect.Struct:
+		return d.decodeStruct(code, v)
+	}
+	if n, err := d.arrayLen(code); n >= 0 || err != nil {
+		return ErrTypeMismatch
+	}
+	if n, err := d.mapLen(code); n >= 0 || err != nil {
+		return ErrTypeMismatch
+	}
+	x, err := d.decodeInterface(code)
+	if err != nil {
+		return err
+	}
+	return setScalar(v, x)
+}
+
+func setScalar(v reflect.Value, x interface{}) error {
+	switch v.Kind() {
+	case reflect.Bool:
+		if b, ok := x.(bool); ok {
+			v.SetBool(b)
+			return nil
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		switch n := x.(type) {
+		case int64:
+			if v.OverflowInt(n) {
+				return ErrOverflow
+			}
+			v.SetInt(n)
+			return nil
+		case uint64:
+			return ErrOverflow
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		switch n := x.(type) {
+		case int64:
+			if n < 0 || v.OverflowUint(uint64(n)) {
+				return ErrOverflow
+			}
+			v.SetUint(uint64(n))
+			return nil
+		case uint64:
+			if v.OverflowUint(n) {
+				return ErrOverflow
+			}
+			v.SetUint(n)
+			return nil
+		}
+	case reflect.Float32, reflect.Float64:
+		switch n := x.(type) {
+		case float32:
+			v.SetFloat(float64(n))
+			return nil
+		case float64:
+			v.SetFloat(n)
+			return nil
+		case int64:
+			v.SetFloat(float64(n))
+			return nil
+		case uint64:
+			v.SetFloat(float64(n))
+			return nil
+		}
+	case reflect.String:
+		switch s := x.(type) {
+		case string:
+			v.SetString(s)
+			return nil
+		case []byte:
+			v.SetString(string(s))
+			return nil
+		}
+	case reflect.Slice:
+		switch s := x.(type) {
+		case string:
+			v.SetBytes([]byte(s))
+			return nil
+		case []byte:
+			v.SetBytes(s)
+			return nil
+		}
+	case reflect.Array:
+		var b []byte
+		switch s := x.(type) {
+		case string:
+			b = []byte(s)
+		case []byte:
+			b = s
+		default:
+			return ErrTypeMismatch
+		}
+		if len(b) != v.Len() {
+			return ErrTypeMismatch
+		}
+		reflect.Copy(v, reflect.ValueOf(b))
+		return nil
+	}
+	return ErrTypeMismatch
+}
+
+func (d *Decoder) decodeSlice(code byte, v reflect.Value) error {
+	n, err := d.arrayLen(code)
+	if err != nil {
+		return err
+	}
+	if n < 0 {
+		return ErrTypeMismatch
+	}
+	if err := d.enter(); err != nil {
+		return err
+	}
+	defer func() { d.depth-- }()
+	if v.Kind() == reflect.Array {
+		if n > v.Len() {
+			return ErrTypeMismatch
+		}
+		for i := 0; i < n; i++ {
+			if err := d.decodeNext(v.Index(i)); err != nil {
+				return err
+			}
+		}
+		for i := n; i < v.Len(); i++ {
+			v.Index(i).Set(reflect.Zero(v.Type().Elem()))
+		}
+		return nil
+	}
+	s := reflect.MakeSlice(v.Type(), 0, initialCap(n))
+	elem := reflect.New(v.Type().Elem()).Elem()
+	for i := 0; i < n; i++ {
+		elem.Set(reflect.Zero(elem.Type()))
+		if err := d.decodeNext(elem); err != nil {
+			return err
+		}
+		s = reflect.Append(s, elem)
+	}
+	v.Set(s)
+	return nil
+}
+
+func (d *Decoder) decodeMapValue(code byte, v reflect.Value) error {
+	n, err := d.mapLen(code)
+	if err != nil {
+		return err
+	}
+	if n < 0 {
+		return ErrTypeMismatch
+	}
+	if err := d.enter(); err != nil {
+		return err
+	}
+	defer func() { d.depth-- }()
+	t := v.Type()
+	if v.IsNil() {
+		v.Set(reflect.MakeMapWithSize(t, initialCap(n)))
+	}
+	for i := 0; i < n; i++ {
+		key := reflect.New(t.Key()).Elem()
+		if err := d.decodeNext(key); err != nil {
+			return err
+		}
+		if !hashable(key) {
+			return ErrInvalidMapKey
+		}
+		elem := reflect.New(t.Elem()).Elem()
+		if err := d.decodeNext(elem); err != nil {
+			return err
+		}
+		v.SetMapIndex(key, elem)
+	}
+	return nil
+}
+
+func (d *Decoder) decodeStruct(code byte, v reflect.Value) error {
+	n, err := d.mapLen(code)
+	if err != nil {
+		return err
+	}
+	if n < 0 {
+		return ErrTypeMismatch
+	}
+	if err := d.enter(); err != nil {
+		return err
+	}
+	defer func() { d.depth-- }()
+	fields := structs.Fields(v.Type(), "msgpack")
+	for i := 0; i < n; i++ {
+		var name string
+		if err := d.decodeNext(reflect.ValueOf(&name).Elem()); err != nil {
+			return err
+		}
+		f := structs.Find(fields, name)
+		if f == nil {
+			if _, err := d.DecodeInterface(); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := d.decodeNext(v.FieldByIndex(f.Index)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// hashable reports whether v can be a map key. Unlike Type.Comparable it
+// looks at the dynamic values inside interfaces, such as the elements of an
+// [1]interface{} key.
+func hashable(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Invalid:
+		return true
+	case reflect.Interface:
+		return v.IsNil() || hashable(v.Elem())
+	case reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			if !hashable(v.Index(i)) {
+				return false
+			}
+		}
+		return true
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if !hashable(v.Field(i)) {
+				return false
+			}
+		}
+		return true
+	}
+	return v.Type().Comparable()
+}
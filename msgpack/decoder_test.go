@@ -0,0 +1,131 @@
+package msgpack
+
+import (
+	"bytes"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/funny/binary"
+	"github.com/funny/utest"
+)
+
+func newDecoder(b []byte) *Decoder {
+	return NewDecoder(binary.NewReader(bytes.NewReader(b)))
+}
+
+func Test_Decoder_Interface(t *testing.T) {
+	now := time.Unix(1700000000, 123456789).UTC()
+	v := map[string]interface{}{
+		"nil":    nil,
+		"bool":   true,
+		"int":    int64(-100000),
+		"uint":   uint64(math.MaxUint64),
+		"f32":    float32(1.5),
+		"f64":    math.Pi,
+		"str":    "hello",
+		"bin":    []byte{1, 2, 3},
+		"time":   now,
+		"ext":    Ext{7, []byte{1, 2, 3, 4, 5}},
+		"array":  []interface{}{int64(1), "two", []interface{}{}},
+		"intmap": map[interface{}]interface{}{int64(1): "one", "two": int64(2)},
+	}
+	x, err := newDecoder(encode(t, v)).DecodeInterface()
+	utest.IsNilNow(t, err)
+	utest.EqualNow(t, x, v)
+}
+
+type inner struct {
+	X float64
+	T time.Time
+}
+
+type record struct {
+	embedded
+	ID     uint16            `msgpack:"id"`
+	Name   string            `msgpack:"name,omitempty"`
+	Tags   []string          `msgpack:"tags"`
+	Attrs  map[string]int    `msgpack:"attrs"`
+	Inner  *inner            `msgpack:"inner"`
+	Raw    [4]byte           `msgpack:"raw"`
+	Any    interface{}       `msgpack:"any"`
+	Nested map[int][]float32 `msgpack:"nested"`
+}
+
+func Test_Decoder_Struct(t *testing.T) {
+	v := record{
+		embedded: embedded{42},
+		ID:       7,
+		Name:     "n",
+		Tags:     []string{"a", "b"},
+		Attrs:    map[string]int{"x": -1},
+		Inner:    &inner{2.5, time.Unix(5, 0).UTC()},
+		Raw:      [4]byte{1, 2, 3, 4},
+		Any:      "any",
+		Nested:   map[int][]float32{1: {0.5}},
+	}
+	var v2 record
+	utest.IsNilNow(t, newDecoder(encode(t, &v)).Decode(&v2))
+	utest.EqualNow(t, v2, v)
+
+	// Unknown keys are skipped, nil clears pointers.
+	b := encode(t, map[string]interface{}{"id": 9, "unknown": []interface{}{1, "x"}, "inner": nil})
+	utest.IsNilNow(t, newDecoder(b).Decode(&v2))
+	utest.EqualNow(t, v2.ID, uint16(9))
+	utest.IsNilNow(t, v2.Inner)
+}
+
+func Test_Decoder_Stream(t *testing.T) {
+	var buf bytes.Buffer
+	e := NewEncoder(binary.NewWriter(&buf))
+	e.EncodeArrayLen(2)
+	e.EncodeMapLen(20)
+	for i := 0; i < 20; i++ {
+		e.EncodeInt(int64(i))
+		e.EncodeNil()
+	}
+	e.EncodeString("end")
+
+	d := newDecoder(buf.Bytes())
+	n, err := d.DecodeArrayLen()
+	utest.IsNilNow(t, err)
+	utest.EqualNow(t, n, 2)
+	n, err = d.DecodeMapLen()
+	utest.IsNilNow(t, err)
+	utest.EqualNow(t, n, 20)
+	for i := 0; i < 40; i++ {
+		_, err = d.DecodeInterface()
+		utest.IsNilNow(t, err)
+	}
+	var s string
+	utest.IsNilNow(t, d.Decode(&s))
+	utest.EqualNow(t, s, "end")
+}
+
+func Test_Decoder_Errors(t *testing.T) {
+	var i8 int8
+	utest.EqualNow(t, newDecoder([]byte{0xcc, 0x80}).Decode(&i8), ErrOverflow)
+	var u uint
+	utest.EqualNow(t, newDecoder([]byte{0xff}).Decode(&u), ErrOverflow)
+	var s string
+	utest.EqualNow(t, newDecoder([]byte{0x01}).Decode(&s), ErrTypeMismatch)
+	utest.EqualNow(t, newDecoder([]byte{0x91, 0x01}).Decode(&s), ErrTypeMismatch)
+	utest.EqualNow(t, newDecoder([]byte{0x01}).Decode(s), ErrNotPointer)
+
+	_, err := newDecoder([]byte{0xc1}).DecodeInterface()
+	utest.EqualNow(t, err, ErrInvalidCode)
+	_, err = newDecoder([]byte{0x81, 0x91, 0x01, 0x01}).DecodeInterface()
+	utest.EqualNow(t, err, ErrInvalidMapKey)
+	utest.EqualNow(t, newDecoder([]byte{0x81, 0x91, 0x91, 0x01, 0x01}).Decode(&map[[1]interface{}]int{}), ErrInvalidMapKey)
+	_, err = newDecoder([]byte{0xd6, 0xff, 0x00}).DecodeInterface()
+	utest.NotNilNow(t, err)
+
+	d := newDecoder([]byte{0xdb, 0x00, 0x00, 0x10, 0x00})
+	d.MaxLen = 1024
+	_, err = d.DecodeInterface()
+	utest.EqualNow(t, err, ErrTooLarge)
+
+	deep := bytes.Repeat([]byte{0x91}, maxDepth+1)
+	_, err = newDecoder(append(deep, 0x00)).DecodeInterface()
+	utest.EqualNow(t, err, ErrTooDeep)
+}
@@ -0,0 +1,288 @@
+package msgpack
+
+import (
+	"math"
+	"reflect"
+	"time"
+
+	"github.com/funny/binary"
+	"github.com/funny/binary/internal/structs"
+)
+
+var (
+	timeType = reflect.TypeOf(time.Time{})
+	extType  = reflect.TypeOf(Ext{})
+)
+
+// Encoder writes MessagePack values. Integers use the shortest encoding
+// that holds them, write errors are left in the BinaryWriter.
+type Encoder struct {
+	w binary.BinaryWriter
+}
+
+func NewEncoder(w binary.BinaryWriter) *Encoder {
+	return &Encoder{w}
+}
+
+func (e *Encoder) EncodeNil() {
+	e.w.WriteUint8(codeNil)
+}
+
+func (e *Encoder) EncodeBool(v bool) {
+	if v {
+		e.w.WriteUint8(codeTrue)
+	} else {
+		e.w.WriteUint8(codeFalse)
+	}
+}
+
+func (e *Encoder) EncodeUint(v uint64) {
+	switch {
+	case v < fixMap:
+		e.w.WriteUint8(uint8(v))
+	case v <= math.MaxUint8:
+		e.w.WriteUint8(codeUint8)
+		e.w.WriteUint8(uint8(v))
+	case v <= math.MaxUint16:
+		e.w.WriteUint8(codeUint16)
+		e.w.WriteUint16BE(uint16(v))
+	case v <= math.MaxUint32:
+		e.w.WriteUint8(codeUint32)
+		e.w.WriteUint32BE(uint32(v))
+	default:
+		e.w.WriteUint8(codeUint64)
+		e.w.WriteUint64BE(v)
+	}
+}
+
+// EncodeInt writes non-negative values like EncodeUint.
+func (e *Encoder) EncodeInt(v int64) {
+	switch {
+	case v >= 0:
+		e.EncodeUint(uint64(v))
+	case v >= -32:
+		e.w.WriteInt8(int8(v))
+	case v >= math.MinInt8:
+		e.w.WriteUint8(codeInt8)
+		e.w.WriteInt8(int8(v))
+	case v >= math.MinInt16:
+		e.w.WriteUint8(codeInt16)
+		e.w.WriteInt16BE(int16(v))
+	case v >= math.MinInt32:
+		e.w.WriteUint8(codeInt32)
+		e.w.WriteInt32BE(int32(v))
+	default:
+		e.w.WriteUint8(codeInt64)
+		e.w.WriteInt64BE(v)
+	}
+}
+
+func (e *Encoder) EncodeFloat32(v float32) {
+	e.w.WriteUint8(codeFloat32)
+	e.w.WriteFloat32BE(v)
+}
+
+func (e *Encoder) EncodeFloat64(v float64) {
+	e.w.WriteUint8(codeFloat64)
+	e.w.WriteFloat64BE(v)
+}
+
+// writeLen writes the smallest of the 8, 16 and 32 bit forms, code8 is
+// zero for formats without an 8 bit form.
+func (e *Encoder) writeLen(n int, code8, code16, code32 uint8) {
+	switch {
+	case n <= math.MaxUint8 && code8 != 0:
+		e.w.WriteUint8(code8)
+		e.w.WriteUint8(uint8(n))
+	case n <= math.MaxUint16:
+		e.w.WriteUint8(code16)
+		e.w.WriteUint16BE(uint16(n))
+	default:
+		e.w.WriteUint8(code32)
+		e.w.WriteUint32BE(uint32(n))
+	}
+}
+
+func (e *Encoder) EncodeString(v string) {
+	if len(v) < 32 {
+		e.w.WriteUint8(fixStr | uint8(len(v)))
+	} else {
+		e.writeLen(len(v), codeStr8, codeStr16, codeStr32)
+	}
+	e.w.WriteString(v)
+}
+
+func (e *Encoder) EncodeBytes(v []byte) {
+	e.writeLen(len(v), codeBin8, codeBin16, codeBin32)
+	e.w.WriteBytes(v)
+}
+
+// EncodeArrayLen starts an array, the n elements follow.
+func (e *Encoder) EncodeArrayLen(n int) {
+	if n < 16 {
+		e.w.WriteUint8(fixArray | uint8(n))
+	} else {
+		e.writeLen(n, 0, codeArray16, codeArray32)
+	}
+}
+
+// EncodeMapLen starts a map, the n key and value pairs follow.
+func (e *Encoder) EncodeMapLen(n int) {
+	if n < 16 {
+		e.w.WriteUint8(fixMap | uint8(n))
+	} else {
+		e.writeLen(n, 0, codeMap16, codeMap32)
+	}
+}
+
+func (e *Encoder) EncodeExt(typ int8, data []byte) {
+	switch len(data) {
+	case 1:
+		e.w.WriteUint8(codeFixExt1)
+	case 2:
+		e.w.WriteUint8(codeFixExt2)
+	case 4:
+		e.w.WriteUint8(codeFixExt4)
+	case 8:
+		e.w.WriteUint8(codeFixExt8)
+	case 16:
+		e.w.WriteUint8(codeFixExt16)
+	default:
+		e.writeLen(len(data), codeExt8, codeExt16, codeExt32)
+	}
+	e.w.WriteInt8(typ)
+	e.w.WriteBytes(data)
+}
+
+// EncodeTime writes the timestamp extension in its 32, 64 or 96 bit form.
+func (e *Encoder) EncodeTime(t time.Time) {
+	sec, nsec := t.Unix(), uint64(t.Nanosecond())
+	var b []byte
+	switch {
+	case sec>>32 == 0 && nsec == 0:
+		b = make([]byte, 4)
+		binary.PutUint32BE(b, uint32(sec))
+	case sec>>34 == 0:
+		b = make([]byte, 8)
+		binary.PutUint64BE(b, nsec<<34|uint64(sec))
+	default:
+		b = make([]byte, 12)
+		binary.PutUint32BE(b, uint32(nsec))
+		binary.PutUint64BE(b[4:], uint64(sec))
+	}
+	e.EncodeExt(TimestampExt, b)
+}
+
+// Encode writes v by reflection. Maps are written in iteration order.
+func (e *Encoder) Encode(v interface{}) error {
+	return e.encodeValue(reflect.ValueOf(v), 0)
+}
+
+func (e *Encoder) encodeValue(v reflect.Value, depth int) error {
+	if depth > maxDepth {
+		return ErrTooDeep
+	}
+	if !v.IsValid() {
+		e.EncodeNil()
+		return nil
+	}
+	switch v.Type() {
+	case timeType:
+		e.EncodeTime(v.Interface().(time.Time))
+		return nil
+	case extType:
+		ext := v.Interface().(Ext)
+		e.EncodeExt(ext.Type, ext.Data)
+		return nil
+	}
+	switch v.Kind() {
+	case reflect.Bool:
+		e.EncodeBool(v.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		e.EncodeInt(v.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		e.EncodeUint(v.Uint())
+	case reflect.Float32:
+		e.EncodeFloat32(float32(v.Float()))
+	case reflect.Float64:
+		e.EncodeFloat64(v.Float())
+	case reflect.String:
+		e.EncodeString(v.String())
+	case reflect.Slice:
+		if v.IsNil() {
+			e.EncodeNil()
+			return nil
+		}
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			e.EncodeBytes(v.Bytes())
+			return nil
+		}
+		return e.encodeArray(v, depth)
+	case reflect.Array:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			b := make([]byte, v.Len())
+			reflect.Copy(reflect.ValueOf(b), v)
+			e.EncodeBytes(b)
+			return nil
+		}
+		return e.encodeArray(v, depth)
+	case reflect.Map:
+		if v.IsNil() {
+			e.EncodeNil()
+			return nil
+		}
+		e.EncodeMapLen(v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			if err := e.encodeValue(iter.Key(), depth+1); err != nil {
+				return err
+			}
+			if err := e.encodeValue(iter.Value(), depth+1); err != nil {
+				return err
+			}
+		}
+	case reflect.Struct:
+		return e.encodeStruct(v, depth)
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			e.EncodeNil()
+			return nil
+		}
+		return e.encodeValue(v.Elem(), depth+1)
+	default:
+		return ErrUnsupportedType
+	}
+	return nil
+}
+
+func (e *Encoder) encodeArray(v reflect.Value, depth int) error {
+	e.EncodeArrayLen(v.Len())
+	for i := 0; i < v.Len(); i++ {
+		if err := e.encodeValue(v.Index(i), depth+1); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (e *Encoder) encodeStruct(v reflect.Value, depth int) error {
+	fields := structs.Fields(v.Type(), "msgpack")
+	values := make([]reflect.Value, 0, len(fields))
+	names := make([]string, 0, len(fields))
+	for _, f := range fields {
+		fv := v.FieldByIndex(f.Index)
+		if f.OmitEmpty && structs.IsEmpty(fv) {
+			continue
+		}
+		values = append(values, fv)
+		names = append(names, f.Name)
+	}
+	e.EncodeMapLen(len(values))
+	for i, fv := range values {
+		e.EncodeString(names[i])
+		if err := e.encodeValue(fv, depth+1); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+package msgpack
+
+import (
+	"bytes"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/funny/binary"
+	"github.com/funny/utest"
+)
+
+func encode(t *testing.T, v interface{}) []byte {
+	var buf bytes.Buffer
+	w := binary.NewWriter(&buf)
+	utest.IsNilNow(t, NewEncoder(w).Encode(v))
+	utest.IsNilNow(t, w.Error())
+	return buf.Bytes()
+}
+
+func Test_Encoder_Vectors(t *testing.T) {
+	for _, c := range []struct {
+		v interface{}
+		b []byte
+	}{
+		{nil, []byte{0xc0}},
+		{false, []byte{0xc2}},
+		{true, []byte{0xc3}},
+		{0, []byte{0x00}},
+		{127, []byte{0x7f}},
+		{128, []byte{0xcc, 0x80}},
+		{256, []byte{0xcd, 0x01, 0x00}},
+		{uint32(65536), []byte{0xce, 0x00, 0x01, 0x00, 0x00}},
+		{uint64(math.MaxUint64), []byte{0xcf, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}},
+		{-1, []byte{0xff}},
+		{-32, []byte{0xe0}},
+		{-33, []byte{0xd0, 0xdf}},
+		{-129, []byte{0xd1, 0xff, 0x7f}},
+		{int64(math.MinInt32), []byte{0xd2, 0x80, 0x00, 0x00, 0x00}},
+		{int64(math.MinInt64), []byte{0xd3, 0x80, 0, 0, 0, 0, 0, 0, 0}},
+		{float32(1.5), []byte{0xca, 0x3f, 0xc0, 0x00, 0x00}},
+		{1.5, []byte{0xcb, 0x3f, 0xf8, 0, 0, 0, 0, 0, 0}},
+		{"", []byte{0xa0}},
+		{"abc", []byte{0xa3, 'a', 'b', 'c'}},
+		{[]byte{1, 2}, []byte{0xc4, 0x02, 0x01, 0x02}},
+		{[2]byte{1, 2}, []byte{0xc4, 0x02, 0x01, 0x02}},
+		{[]int{1, 2}, []byte{0x92, 0x01, 0x02}},
+		{map[string]int{"a": 1}, []byte{0x81, 0xa1, 'a', 0x01}},
+		{Ext{5, []byte{1}}, []byte{0xd4, 0x05, 0x01}},
+		{Ext{5, []byte{1, 2, 3}}, []byte{0xc7, 0x03, 0x05, 0x01, 0x02, 0x03}},
+		{time.Unix(1, 0), []byte{0xd6, 0xff, 0, 0, 0, 1}},
+		{time.Unix(1, 1), []byte{0xd7, 0xff, 0, 0, 0, 0x04, 0, 0, 0, 1}},
+		{time.Unix(-1, 0), []byte{0xc7, 0x0c, 0xff, 0, 0, 0, 0, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}},
+	} {
+		utest.EqualNow(t, encode(t, c.v), c.b)
+	}
+
+	b := encode(t, strings.Repeat("x", 32))
+	utest.EqualNow(t, b[:2], []byte{0xd9, 32})
+	b = encode(t, strings.Repeat("x", 256))
+	utest.EqualNow(t, b[:3], []byte{0xda, 0x01, 0x00})
+	b = encode(t, make([]byte, 65536))
+	utest.EqualNow(t, b[:5], []byte{0xc6, 0x00, 0x01, 0x00, 0x00})
+	b = encode(t, make([]bool, 16))
+	utest.EqualNow(t, b[:3], []byte{0xdc, 0x00, 0x10})
+	b = encode(t, make([]bool, 65536))
+	utest.EqualNow(t, b[:5], []byte{0xdd, 0x00, 0x01, 0x00, 0x00})
+	b = encode(t, Ext{1, make([]byte, 16)})
+	utest.EqualNow(t, b[:2], []byte{0xd8, 0x01})
+}
+
+type embedded struct {
+	E int `msgpack:"e"`
+}
+
+type tagged struct {
+	embedded
+	A       int    `msgpack:"a"`
+	B       string `msgpack:",omitempty"`
+	Skip    int    `msgpack:"-"`
+	private int
+}
+
+func Test_Encoder_Struct(t *testing.T) {
+	b := encode(t, tagged{embedded{1}, 2, "", 3, 4})
+	utest.EqualNow(t, b, []byte{0x82, 0xa1, 'e', 0x01, 0xa1, 'a', 0x02})
+	b = encode(t, &tagged{B: "x"})
+	utest.EqualNow(t, b, []byte{0x83, 0xa1, 'e', 0x00, 0xa1, 'a', 0x00, 0xa1, 'B', 0xa1, 'x'})
+
+	var buf bytes.Buffer
+	utest.EqualNow(t, NewEncoder(binary.NewWriter(&buf)).Encode(make(chan int)), ErrUnsupportedType)
+
+	type selfRef struct{ P *selfRef }
+	cycle := &selfRef{}
+	cycle.P = cycle
+	utest.EqualNow(t, NewEncoder(binary.NewWriter(&buf)).Encode(cycle), ErrTooDeep)
+}
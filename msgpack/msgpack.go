@@ -0,0 +1,75 @@
+// Package msgpack implements MessagePack on top of the BinaryReader and
+// BinaryWriter interfaces of github.com/funny/binary.
+//
+// Structs are encoded as maps keyed by field name, the "msgpack" struct tag
+// renames a field, "-" skips it and the "omitempty" option drops empty
+// values like encoding/json does.
+package msgpack
+
+import "errors"
+
+var (
+	ErrUnsupportedType = errors.New("funny/binary/msgpack: unsupported type")
+	ErrInvalidCode     = errors.New("funny/binary/msgpack: invalid type code")
+	ErrTypeMismatch    = errors.New("funny/binary/msgpack: value does not match target type")
+	ErrOverflow        = errors.New("funny/binary/msgpack: value overflows target type")
+	ErrInvalidMapKey   = errors.New("funny/binary/msgpack: map key is not comparable")
+	ErrTooLarge        = errors.New("funny/binary/msgpack: length exceeds limit")
+	ErrTooDeep         = errors.New("funny/binary/msgpack: nesting exceeds limit")
+	ErrNotPointer      = errors.New("funny/binary/msgpack: decode target must be a non-nil pointer")
+)
+
+const (
+	codeNil      = 0xc0
+	codeFalse    = 0xc2
+	codeTrue     = 0xc3
+	codeBin8     = 0xc4
+	codeBin16    = 0xc5
+	codeBin32    = 0xc6
+	codeExt8     = 0xc7
+	codeExt16    = 0xc8
+	codeExt32    = 0xc9
+	codeFloat32  = 0xca
+	codeFloat64  = 0xcb
+	codeUint8    = 0xcc
+	codeUint16   = 0xcd
+	codeUint32   = 0xce
+	codeUint64   = 0xcf
+	codeInt8     = 0xd0
+	codeInt16    = 0xd1
+	codeInt32    = 0xd2
+	codeInt64    = 0xd3
+	codeFixExt1  = 0xd4
+	codeFixExt2  = 0xd5
+	codeFixExt4  = 0xd6
+	codeFixExt8  = 0xd7
+	codeFixExt16 = 0xd8
+	codeStr8     = 0xd9
+	codeStr16    = 0xda
+	codeStr32    = 0xdb
+	codeArray16  = 0xdc
+	codeArray32  = 0xdd
+	codeMap16    = 0xde
+	codeMap32    = 0xdf
+
+	fixMap   = 0x80
+	fixArray = 0x90
+	fixStr   = 0xa0
+	negFix   = 0xe0
+)
+
+// DefaultMaxLen limits strings, binaries, ext data and the element count
+// of arrays and maps when Decoder.MaxLen is zero.
+const DefaultMaxLen = 16 << 20
+
+const maxDepth = 1000
+
+// TimestampExt is the ext type of the timestamp extension, it is decoded
+// into time.Time.
+const TimestampExt = -1
+
+// Ext is an application defined ext value.
+type Ext struct {
+	Type int8
+	Data []byte
+}
@@ -0,0 +1,451 @@
+package binary
+
+import "unsafe"
+
+// ByteOrder selects the byte order used by OrderedReader and OrderedWriter.
+type ByteOrder int
+
+const (
+	BigEndian ByteOrder = iota
+	LittleEndian
+	NativeEndian
+)
+
+var nativeBigEndian = func() bool {
+	var x uint16 = 0x0102
+	return *(*byte)(unsafe.Pointer(&x)) == 0x01
+}()
+
+// IsBigEndian reports whether order is big endian, NativeEndian is
+// resolved against the running machine.
+func (order ByteOrder) IsBigEndian() bool {
+	return order == BigEndian || (order == NativeEndian && nativeBigEndian)
+}
+
+// OrderedReader reads fixed width values in a byte order chosen at runtime,
+// useful for formats like TIFF and ELF which declare it in their header.
+// Values it cannot decode, like a Fixed of an invalid format, set an error
+// that Error reports before the one of the underlying reader.
+type OrderedReader struct {
+	BinaryReader
+	Order ByteOrder
+	err   error
+}
+
+func NewOrderedReader(r BinaryReader, order ByteOrder) *OrderedReader {
+	return &OrderedReader{BinaryReader: r, Order: order}
+}
+
+func (r *OrderedReader) Error() error {
+	if r.err != nil {
+		return r.err
+	}
+	return r.BinaryReader.Error()
+}
+
+func (r *OrderedReader) fail(err error) {
+	if r.err == nil {
+		r.err = err
+	}
+}
+
+// OrderedWriter writes fixed width values in a byte order chosen at runtime.
+// Values it cannot encode set an error that Error reports before the one
+// of the underlying writer.
+type OrderedWriter struct {
+	BinaryWriter
+	Order ByteOrder
+	err   error
+}
+
+func NewOrderedWriter(w BinaryWriter, order ByteOrder) *OrderedWriter {
+	return &OrderedWriter{BinaryWriter: w, Order: order}
+}
+
+func (w *OrderedWriter) Error() error {
+	if w.err != nil {
+		return w.err
+	}
+	return w.BinaryWriter.Error()
+}
+
+func (w *OrderedWriter) fail(err error) {
+	if w.err == nil {
+		w.err = err
+	}
+}
+
+func (r *OrderedReader) ReadInt() int {
+	if r.Order.IsBigEndian() {
+		return r.ReadIntBE()
+	}
+	return r.ReadIntLE()
+}
+
+func (r *OrderedReader) ReadUint() uint {
+	if r.Order.IsBigEndian() {
+		return r.ReadUintBE()
+	}
+	return r.ReadUintLE()
+}
+
+func (r *OrderedReader) ReadInt16() int16 {
+	if r.Order.IsBigEndian() {
+		return r.ReadInt16BE()
+	}
+	return r.ReadInt16LE()
+}
+
+func (r *OrderedReader) ReadUint16() uint16 {
+	if r.Order.IsBigEndian() {
+		return r.ReadUint16BE()
+	}
+	return r.ReadUint16LE()
+}
+
+func (r *OrderedReader) ReadInt24() int32 {
+	if r.Order.IsBigEndian() {
+		return r.ReadInt24BE()
+	}
+	return r.ReadInt24LE()
+}
+
+func (r *OrderedReader) ReadUint24() uint32 {
+	if r.Order.IsBigEndian() {
+		return r.ReadUint24BE()
+	}
+	return r.ReadUint24LE()
+}
+
+func (r *OrderedReader) ReadInt32() int32 {
+	if r.Order.IsBigEndian() {
+		return r.ReadInt32BE()
+	}
+	return r.ReadInt32LE()
+}
+
+func (r *OrderedReader) ReadUint32() uint32 {
+	if r.Order.IsBigEndian() {
+		return r.ReadUint32BE()
+	}
+	return r.ReadUint32LE()
+}
+
+func (r *OrderedReader) ReadInt40() int64 {
+	if r.Order.IsBigEndian() {
+		return r.ReadInt40BE()
+	}
+	return r.ReadInt40LE()
+}
+
+func (r *OrderedReader) ReadUint40() uint64 {
+	if r.Order.IsBigEndian() {
+		return r.ReadUint40BE()
+	}
+	return r.ReadUint40LE()
+}
+
+func (r *OrderedReader) ReadInt48() int64 {
+	if r.Order.IsBigEndian() {
+		return r.ReadInt48BE()
+	}
+	return r.ReadInt48LE()
+}
+
+func (r *OrderedReader) ReadUint48() uint64 {
+	if r.Order.IsBigEndian() {
+		return r.ReadUint48BE()
+	}
+	return r.ReadUint48LE()
+}
+
+func (r *OrderedReader) ReadInt56() int64 {
+	if r.Order.IsBigEndian() {
+		return r.ReadInt56BE()
+	}
+	return r.ReadInt56LE()
+}
+
+func (r *OrderedReader) ReadUint56() uint64 {
+	if r.Order.IsBigEndian() {
+		return r.ReadUint56BE()
+	}
+	return r.ReadUint56LE()
+}
+
+func (r *OrderedReader) ReadInt64() int64 {
+	if r.Order.IsBigEndian() {
+		return r.ReadInt64BE()
+	}
+	return r.ReadInt64LE()
+}
+
+func (r *OrderedReader) ReadUint64() uint64 {
+	if r.Order.IsBigEndian() {
+		return r.ReadUint64BE()
+	}
+	return r.ReadUint64LE()
+}
+
+func (r *OrderedReader) ReadFloat32() float32 {
+	if r.Order.IsBigEndian() {
+		return r.ReadFloat32BE()
+	}
+	return r.ReadFloat32LE()
+}
+
+func (r *OrderedReader) ReadFloat64() float64 {
+	if r.Order.IsBigEndian() {
+		return r.ReadFloat64BE()
+	}
+	return r.ReadFloat64LE()
+}
+
+func (r *OrderedReader) ReadComplex64() complex64 {
+	if r.Order.IsBigEndian() {
+		return r.ReadComplex64BE()
+	}
+	return r.ReadComplex64LE()
+}
+
+func (r *OrderedReader) ReadComplex128() complex128 {
+	if r.Order.IsBigEndian() {
+		return r.ReadComplex128BE()
+	}
+	return r.ReadComplex128LE()
+}
+
+func (r *OrderedReader) ReadFloat80() float64 {
+	if r.Order.IsBigEndian() {
+		return r.ReadFloat80BE()
+	}
+	return r.ReadFloat80LE()
+}
+
+func (r *OrderedReader) ReadUint128() Uint128 {
+	if r.Order.IsBigEndian() {
+		return r.ReadUint128BE()
+	}
+	return r.ReadUint128LE()
+}
+
+func (r *OrderedReader) ReadInt128() Int128 {
+	if r.Order.IsBigEndian() {
+		return r.ReadInt128BE()
+	}
+	return r.ReadInt128LE()
+}
+
+func (r *OrderedReader) ReadFloat16() float32 {
+	if r.Order.IsBigEndian() {
+		return r.ReadFloat16BE()
+	}
+	return r.ReadFloat16LE()
+}
+
+func (r *OrderedReader) ReadBFloat16() float32 {
+	if r.Order.IsBigEndian() {
+		return r.ReadBFloat16BE()
+	}
+	return r.ReadBFloat16LE()
+}
+
+func (w *OrderedWriter) WriteInt(v int) {
+	if w.Order.IsBigEndian() {
+		w.WriteIntBE(v)
+	} else {
+		w.WriteIntLE(v)
+	}
+}
+
+func (w *OrderedWriter) WriteUint(v uint) {
+	if w.Order.IsBigEndian() {
+		w.WriteUintBE(v)
+	} else {
+		w.WriteUintLE(v)
+	}
+}
+
+func (w *OrderedWriter) WriteInt16(v int16) {
+	if w.Order.IsBigEndian() {
+		w.WriteInt16BE(v)
+	} else {
+		w.WriteInt16LE(v)
+	}
+}
+
+func (w *OrderedWriter) WriteUint16(v uint16) {
+	if w.Order.IsBigEndian() {
+		w.WriteUint16BE(v)
+	} else {
+		w.WriteUint16LE(v)
+	}
+}
+
+func (w *OrderedWriter) WriteInt24(v int32) {
+	if w.Order.IsBigEndian() {
+		w.WriteInt24BE(v)
+	} else {
+		w.WriteInt24LE(v)
+	}
+}
+
+func (w *OrderedWriter) WriteUint24(v uint32) {
+	if w.Order.IsBigEndian() {
+		w.WriteUint24BE(v)
+	} else {
+		w.WriteUint24LE(v)
+	}
+}
+
+func (w *OrderedWriter) WriteInt32(v int32) {
+	if w.Order.IsBigEndian() {
+		w.WriteInt32BE(v)
+	} else {
+		w.WriteInt32LE(v)
+	}
+}
+
+func (w *OrderedWriter) WriteUint32(v uint32) {
+	if w.Order.IsBigEndian() {
+		w.WriteUint32BE(v)
+	} else {
+		w.WriteUint32LE(v)
+	}
+}
+
+func (w *OrderedWriter) WriteInt40(v int64) {
+	if w.Order.IsBigEndian() {
+		w.WriteInt40BE(v)
+	} else {
+		w.WriteInt40LE(v)
+	}
+}
+
+func (w *OrderedWriter) WriteUint40(v uint64) {
+	if w.Order.IsBigEndian() {
+		w.WriteUint40BE(v)
+	} else {
+		w.WriteUint40LE(v)
+	}
+}
+
+func (w *OrderedWriter) WriteInt48(v int64) {
+	if w.Order.IsBigEndian() {
+		w.WriteInt48BE(v)
+	} else {
+		w.WriteInt48LE(v)
+	}
+}
+
+func (w *OrderedWriter) WriteUint48(v uint64) {
+	if w.Order.IsBigEndian() {
+		w.WriteUint48BE(v)
+	} else {
+		w.WriteUint48LE(v)
+	}
+}
+
+func (w *OrderedWriter) WriteInt56(v int64) {
+	if w.Order.IsBigEndian() {
+		w.WriteInt56BE(v)
+	} else {
+		w.WriteInt56LE(v)
+	}
+}
+
+func (w *OrderedWriter) WriteUint56(v uint64) {
+	if w.Order.IsBigEndian() {
+		w.WriteUint56BE(v)
+	} else {
+		w.WriteUint56LE(v)
+	}
+}
+
+func (w *OrderedWriter) WriteInt64(v int64) {
+	if w.Order.IsBigEndian() {
+		w.WriteInt64BE(v)
+	} else {
+		w.WriteInt64LE(v)
+	}
+}
+
+func (w *OrderedWriter) WriteUint64(v uint64) {
+	if w.Order.IsBigEndian() {
+		w.WriteUint64BE(v)
+	} else {
+		w.WriteUint64LE(v)
+	}
+}
+
+func (w *OrderedWriter) WriteFloat32(v float32) {
+	if w.Order.IsBigEndian() {
+		w.WriteFloat32BE(v)
+	} else {
+		w.WriteFloat32LE(v)
+	}
+}
+
+func (w *OrderedWriter) WriteFloat64(v float64) {
+	if w.Order.IsBigEndian() {
+		w.WriteFloat64BE(v)
+	} else {
+		w.WriteFloat64LE(v)
+	}
+}
+
+func (w *OrderedWriter) WriteComplex64(v complex64) {
+	if w.Order.IsBigEndian() {
+		w.WriteComplex64BE(v)
+	} else {
+		w.WriteComplex64LE(v)
+	}
+}
+
+func (w *OrderedWriter) WriteComplex128(v complex128) {
+	if w.Order.IsBigEndian() {
+		w.WriteComplex128BE(v)
+	} else {
+		w.WriteComplex128LE(v)
+	}
+}
+
+func (w *OrderedWriter) WriteFloat80(v float64) {
+	if w.Order.IsBigEndian() {
+		w.WriteFloat80BE(v)
+	} else {
+		w.WriteFloat80LE(v)
+	}
+}
+
+func (w *OrderedWriter) WriteUint128(v Uint128) {
+	if w.Order.IsBigEndian() {
+		w.WriteUint128BE(v)
+	} else {
+		w.WriteUint128LE(v)
+	}
+}
+
+func (w *OrderedWriter) WriteInt128(v Int128) {
+	if w.Order.IsBigEndian() {
+		w.WriteInt128BE(v)
+	} else {
+		w.WriteInt128LE(v)
+	}
+}
+
+func (w *OrderedWriter) WriteFloat16(v float32) {
+	if w.Order.IsBigEndian() {
+		w.WriteFloat16BE(v)
+	} else {
+		w.WriteFloat16LE(v)
+	}
+}
+
+func (w *OrderedWriter) WriteBFloat16(v float32) {
+	if w.Order.IsBigEndian() {
+		w.WriteBFloat16BE(v)
+	} else {
+		w.WriteBFloat16LE(v)
+	}
+}
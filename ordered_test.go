@@ -0,0 +1,69 @@
+package binary
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/funny/utest"
+)
+
+func Test_Ordered_Bytes(t *testing.T) {
+	var buf = Buffer{Data: make([]byte, 8)}
+
+	NewOrderedWriter(&buf, BigEndian).WriteUint32(0x01020304)
+	utest.EqualNow(t, buf.Data[:4], []byte{1, 2, 3, 4})
+
+	buf.WritePos = 0
+	NewOrderedWriter(&buf, LittleEndian).WriteUint32(0x01020304)
+	utest.EqualNow(t, buf.Data[:4], []byte{4, 3, 2, 1})
+
+	buf.WritePos = 0
+	NewOrderedWriter(&buf, NativeEndian).WriteUint16(0x0102)
+	if NativeEndian.IsBigEndian() {
+		utest.EqualNow(t, GetUint16BE(buf.Data), 0x0102)
+	} else {
+		utest.EqualNow(t, GetUint16LE(buf.Data), 0x0102)
+	}
+}
+
+func Test_Ordered_ReadWrite(t *testing.T) {
+	for _, order := range []ByteOrder{BigEndian, LittleEndian, NativeEndian} {
+		var buf = Buffer{Data: make([]byte, 2+3+4+5+6+7+8+4+8)}
+		for i := 0; i < 10000; i++ {
+			buf.ReadPos = 0
+			buf.WritePos = 0
+
+			v1 := int16(rand.Intn(0xFFFF))
+			v2 := uint32(rand.Intn(0xFFFFFF))
+			v3 := int32(rand.Intn(0xFFFFFFFF))
+			v4 := uint64(rand.Intn(0xFFFFFFFFFF))
+			v5 := int64(rand.Intn(0x7FFFFFFFFFFF))
+			v6 := uint64(rand.Intn(0xFFFFFFFFFFFFFF))
+			v7 := rand.Int63()
+			v8 := rand.Float32()
+			v9 := rand.Float64()
+
+			w := NewOrderedWriter(&buf, order)
+			w.WriteInt16(v1)
+			w.WriteUint24(v2)
+			w.WriteInt32(v3)
+			w.WriteUint40(v4)
+			w.WriteInt48(v5)
+			w.WriteUint56(v6)
+			w.WriteInt64(v7)
+			w.WriteFloat32(v8)
+			w.WriteFloat64(v9)
+
+			r := NewOrderedReader(&buf, order)
+			utest.EqualNow(t, r.ReadInt16(), v1)
+			utest.EqualNow(t, r.ReadUint24(), v2)
+			utest.EqualNow(t, r.ReadInt32(), v3)
+			utest.EqualNow(t, r.ReadUint40(), v4)
+			utest.EqualNow(t, r.ReadInt48(), v5)
+			utest.EqualNow(t, r.ReadUint56(), v6)
+			utest.EqualNow(t, r.ReadInt64(), v7)
+			utest.EqualNow(t, r.ReadFloat32(), v8)
+			utest.EqualNow(t, r.ReadFloat64(), v9)
+		}
+	}
+}
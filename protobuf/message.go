@@ -0,0 +1,248 @@
+package protobuf
+
+import (
+	"bytes"
+	"io"
+	"math"
+
+	"github.com/funny/binary"
+)
+
+// maxDepth matches the default recursion limit of the reference
+// implementation.
+const maxDepth = 100
+
+// Field is a decoded field. Embedded messages, strings and packed fields
+// are all length-delimited, Parse decodes Bytes as a message on demand.
+type Field struct {
+	Number int32
+	Type   WireType
+
+	// Value holds varint, fixed32 and fixed64 values.
+	Value uint64
+	// Bytes holds the payload of length-delimited fields.
+	Bytes []byte
+	// Group holds the fields of a group.
+	Group Message
+
+	// Widths of the tag, the varint value or length prefix and the group
+	// end tag as read. Varints are padded back to them, so fields which
+	// were not changed encode to the same bytes.
+	tagLen, valueLen, endLen int
+}
+
+// Message is the fields of a message in wire order, repeated and unknown
+// fields included.
+type Message []Field
+
+func (f *Field) Int64() int64     { return int64(f.Value) }
+func (f *Field) Sint64() int64    { return int64(f.Value>>1) ^ -int64(f.Value&1) }
+func (f *Field) Float32() float32 { return math.Float32frombits(uint32(f.Value)) }
+func (f *Field) Float64() float64 { return math.Float64frombits(f.Value) }
+
+// Find returns the last field numbered num, which is the one that counts
+// for singular fields, or nil.
+func (m Message) Find(num int32) *Field {
+	for i := len(m) - 1; i >= 0; i-- {
+		if m[i].Number == num {
+			return &m[i]
+		}
+	}
+	return nil
+}
+
+// Decoder reads messages field by field.
+type Decoder struct {
+	r     binary.BinaryReader
+	depth int
+
+	// MaxLen limits length-delimited fields, zero means DefaultMaxLen.
+	MaxLen int
+}
+
+func NewDecoder(r binary.BinaryReader) *Decoder {
+	// Reading past the data of a Buffer panics instead of giving io.EOF.
+	if buf, ok := r.(*binary.Buffer); ok {
+		r = binary.NewReader(bufferSource{buf})
+	}
+	return &Decoder{r: r}
+}
+
+// bufferSource reports the end of a Buffer as io.EOF.
+type bufferSource struct {
+	buf *binary.Buffer
+}
+
+func (s bufferSource) Read(b []byte) (int, error) {
+	n, err := s.buf.Read(b)
+	if n == 0 && err == nil && len(b) > 0 {
+		err = io.EOF
+	}
+	return n, err
+}
+
+// Parse decodes a serialized message.
+func Parse(b []byte) (Message, error) {
+	d := NewDecoder(binary.NewReader(bytes.NewReader(b)))
+	d.MaxLen = len(b)
+	return d.ReadMessage()
+}
+
+type countingReader struct {
+	r io.ByteReader
+	n int
+}
+
+func (c *countingReader) ReadByte() (byte, error) {
+	c.n++
+	return c.r.ReadByte()
+}
+
+func (d *Decoder) readVarint() (uint64, int, error) {
+	cr := countingReader{r: d.r}
+	v, err := binary.ReadUvarintStrict(&cr, false)
+	return v, cr.n, err
+}
+
+func (d *Decoder) readTag() (num int32, typ WireType, n int, err error) {
+	v, n, err := d.readVarint()
+	if err != nil {
+		return
+	}
+	num, typ = int32(v>>3), WireType(v&7)
+	if !validTag(v>>3, typ) {
+		err = ErrInvalidTag
+	}
+	return
+}
+
+// ReadField reads the next field, it returns io.EOF at the end of the
+// stream and ErrGroupMismatch on a group end tag.
+func (d *Decoder) ReadField() (Field, error) {
+	num, typ, n, err := d.readTag()
+	if err != nil {
+		return Field{}, err
+	}
+	if typ == EndGroupType {
+		return Field{}, ErrGroupMismatch
+	}
+	f, err := d.readValue(num, typ)
+	f.tagLen = n
+	return f, err
+}
+
+// ReadMessage reads fields up to the end of the stream.
+func (d *Decoder) ReadMessage() (Message, error) {
+	var m Message
+	for {
+		f, err := d.ReadField()
+		if err == io.EOF {
+			return m, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		m = append(m, f)
+	}
+}
+
+func (d *Decoder) readValue(num int32, typ WireType) (f Field, err error) {
+	f.Number, f.Type = num, typ
+	switch typ {
+	case VarintType:
+		f.Value, f.valueLen, err = d.readVarint()
+	case Fixed32Type:
+		f.Value = uint64(d.r.ReadUint32LE())
+	case Fixed64Type:
+		f.Value = d.r.ReadUint64LE()
+	case BytesType:
+		var n uint64
+		if n, f.valueLen, err = d.readVarint(); err != nil {
+			break
+		}
+		maxLen := d.MaxLen
+		if maxLen <= 0 {
+			maxLen = DefaultMaxLen
+		}
+		if n > uint64(maxLen) {
+			return f, ErrTooLarge
+		}
+		f.Bytes = d.r.ReadBytes(int(n))
+	case StartGroupType:
+		f.Group, f.endLen, err = d.readGroup(num)
+	}
+	if err == nil {
+		err = d.r.Error()
+	}
+	if err == io.EOF {
+		err = io.ErrUnexpectedEOF
+	}
+	return
+}
+
+func (d *Decoder) readGroup(num int32) (Message, int, error) {
+	d.depth++
+	defer func() { d.depth-- }()
+	if d.depth > maxDepth {
+		return nil, 0, ErrTooDeep
+	}
+	var m Message
+	for {
+		fnum, typ, n, err := d.readTag()
+		if err != nil {
+			return nil, 0, err
+		}
+		if typ == EndGroupType {
+			if fnum != num {
+				return nil, 0, ErrGroupMismatch
+			}
+			return m, n, nil
+		}
+		f, err := d.readValue(fnum, typ)
+		if err != nil {
+			return nil, 0, err
+		}
+		f.tagLen = n
+		m = append(m, f)
+	}
+}
+
+// writeVarint pads v with zero groups to at least n bytes.
+func writeVarint(w binary.BinaryWriter, v uint64, n int) {
+	var b [binary.MaxVarintLen64]byte
+	i := binary.PutUvarint(b[:], v)
+	for ; i < n && i < len(b); i++ {
+		b[i-1] |= 0x80
+		b[i] = 0
+	}
+	w.WriteBytes(b[:i])
+}
+
+// WriteMessage encodes m, fields read by a Decoder and left unchanged get
+// the bytes they were read from.
+func WriteMessage(w binary.BinaryWriter, m Message) {
+	for i := range m {
+		f := &m[i]
+		writeVarint(w, uint64(f.Number)<<3|uint64(f.Type), f.tagLen)
+		switch f.Type {
+		case VarintType:
+			writeVarint(w, f.Value, f.valueLen)
+		case Fixed32Type:
+			w.WriteUint32LE(uint32(f.Value))
+		case Fixed64Type:
+			w.WriteUint64LE(f.Value)
+		case BytesType:
+			writeVarint(w, uint64(len(f.Bytes)), f.valueLen)
+			w.WriteBytes(f.Bytes)
+		case StartGroupType:
+			WriteMessage(w, f.Group)
+			writeVarint(w, uint64(f.Number)<<3|uint64(EndGroupType), f.endLen)
+		}
+	}
+}
+
+func (m Message) Marshal() []byte {
+	var buf bytes.Buffer
+	WriteMessage(binary.NewWriter(&buf), m)
+	return buf.Bytes()
+}
@@ -0,0 +1,138 @@
+package protobuf
+
+import (
+	"bytes"
+	"io"
+	"math"
+	"testing"
+
+	"github.com/funny/binary"
+	"github.com/funny/utest"
+)
+
+func Test_Message_Parse(t *testing.T) {
+	var buf bytes.Buffer
+	w := binary.NewWriter(&buf)
+	WriteTag(w, 1, VarintType)
+	w.WriteUvarint(uint64(math.MaxUint64 - 1)) // int64 -2
+	WriteTag(w, 2, VarintType)
+	w.WriteVarint(-3)
+	WriteTag(w, 3, Fixed32Type)
+	w.WriteFloat32LE(1.5)
+	WriteTag(w, 4, Fixed64Type)
+	w.WriteFloat64LE(-2.5)
+	WriteTag(w, 5, BytesType)
+	WriteLengthDelimited(w, []byte{0x08, 0x01, 0x08, 0x02})
+	WriteTag(w, 6, StartGroupType)
+	WriteTag(w, 7, BytesType)
+	WriteLengthDelimited(w, []byte("x"))
+	WriteTag(w, 6, EndGroupType)
+
+	m, err := Parse(buf.Bytes())
+	utest.IsNilNow(t, err)
+	utest.EqualNow(t, len(m), 6)
+	utest.EqualNow(t, m.Find(1).Int64(), int64(-2))
+	utest.EqualNow(t, m.Find(2).Sint64(), int64(-3))
+	utest.EqualNow(t, m.Find(3).Float32(), float32(1.5))
+	utest.EqualNow(t, m.Find(4).Float64(), -2.5)
+	utest.EqualNow(t, string(m.Find(6).Group.Find(7).Bytes), "x")
+	utest.IsNilNow(t, m.Find(8))
+
+	sub, err := Parse(m.Find(5).Bytes)
+	utest.IsNilNow(t, err)
+	utest.EqualNow(t, len(sub), 2)
+	utest.EqualNow(t, sub.Find(1).Value, uint64(2))
+
+	utest.EqualNow(t, m.Marshal(), buf.Bytes())
+}
+
+func Test_Message_Identity(t *testing.T) {
+	// Over-long tag, varint and length prefix, a repeated field and an
+	// unknown group.
+	b := []byte{
+		0x88, 0x00, 0x96, 0x81, 0x80, 0x00,
+		0x12, 0x82, 0x00, 'h', 'i',
+		0x08, 0x01,
+		0x1B, 0x20, 0x00, 0x9C, 0x00,
+	}
+	m, err := Parse(b)
+	utest.IsNilNow(t, err)
+	utest.EqualNow(t, m.Marshal(), b)
+
+	// Patched fields keep their widths when the new value fits.
+	m[0].Value = 1
+	m[1].Bytes = []byte("hello")
+	utest.EqualNow(t, m.Marshal()[:13], []byte{0x88, 0x00, 0x81, 0x80, 0x80, 0x00, 0x12, 0x85, 0x00, 'h', 'e', 'l', 'l'})
+
+	m = append(m, Field{Number: 9, Type: BytesType, Bytes: Message{{Number: 1, Type: VarintType, Value: 300}}.Marshal()})
+	m2, err := Parse(m.Marshal())
+	utest.IsNilNow(t, err)
+	utest.EqualNow(t, m2.Find(9).Bytes, []byte{0x08, 0xAC, 0x02})
+}
+
+func Test_Message_Stream(t *testing.T) {
+	var buf bytes.Buffer
+	w := binary.NewWriter(&buf)
+	for i := 1; i <= 100; i++ {
+		WriteTag(w, int32(i), VarintType)
+		w.WriteUvarint(uint64(i))
+	}
+	d := NewDecoder(binary.NewReader(&buf))
+	var sum uint64
+	for {
+		f, err := d.ReadField()
+		if err == io.EOF {
+			break
+		}
+		utest.IsNilNow(t, err)
+		sum += f.Value
+	}
+	utest.EqualNow(t, sum, uint64(5050))
+}
+
+func Test_Message_Buffer(t *testing.T) {
+	var buf binary.Buffer
+	buf.Data = make([]byte, 64)
+	WriteTag(&buf, 1, VarintType)
+	buf.WriteUvarint(300)
+	WriteTag(&buf, 2, BytesType)
+	buf.WriteUvarint(2)
+	buf.WriteString("hi")
+	data := buf.Data[:buf.WritePos]
+
+	m, err := NewDecoder(&binary.Buffer{Data: data}).ReadMessage()
+	utest.IsNilNow(t, err)
+	utest.EqualNow(t, len(m), 2)
+	utest.EqualNow(t, m[0].Value, uint64(300))
+	utest.EqualNow(t, string(m[1].Bytes), "hi")
+
+	for _, n := range []int{2, 4, 5} {
+		_, err = NewDecoder(&binary.Buffer{Data: data[:n]}).ReadMessage()
+		utest.EqualNow(t, err, io.ErrUnexpectedEOF)
+	}
+}
+
+func Test_Message_Errors(t *testing.T) {
+	for _, c := range []struct {
+		b   []byte
+		err error
+	}{
+		{[]byte{0x0C}, ErrGroupMismatch},
+		{[]byte{0x0B, 0x14}, ErrGroupMismatch},
+		{[]byte{0x0B}, io.ErrUnexpectedEOF},
+		{[]byte{0x0A, 0x05, 0x01}, ErrTooLarge},
+		{[]byte{0x0D, 0x01}, io.ErrUnexpectedEOF},
+		{[]byte{0x08}, io.ErrUnexpectedEOF},
+		{[]byte{0x08, 0x80}, io.ErrUnexpectedEOF},
+		{[]byte{0x08, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0x02}, binary.ErrVarintOverflow},
+		{bytes.Repeat([]byte{0x0B}, maxDepth+1), ErrTooDeep},
+	} {
+		_, err := Parse(c.b)
+		utest.EqualNow(t, err, c.err)
+	}
+
+	d := NewDecoder(binary.NewReader(bytes.NewReader([]byte{0x0A, 0x05, 1, 2, 3, 4, 5})))
+	d.MaxLen = 4
+	_, err := d.ReadField()
+	utest.EqualNow(t, err, ErrTooLarge)
+}
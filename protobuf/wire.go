@@ -0,0 +1,105 @@
+// Package protobuf reads and writes the Protocol Buffers wire format on top
+// of the BinaryReader and BinaryWriter interfaces of github.com/funny/binary,
+// without generated code or schemas.
+//
+// Varint fields use the Uvarint methods of the readers and writers, sint32
+// and sint64 fields are the zig-zag Varint methods.
+package protobuf
+
+import (
+	"errors"
+
+	"github.com/funny/binary"
+)
+
+var (
+	ErrInvalidTag    = errors.New("funny/binary/protobuf: invalid field tag")
+	ErrGroupMismatch = errors.New("funny/binary/protobuf: unmatched group end")
+	ErrTooLarge      = errors.New("funny/binary/protobuf: length exceeds limit")
+	ErrTooDeep       = errors.New("funny/binary/protobuf: nesting exceeds limit")
+)
+
+type WireType uint8
+
+const (
+	VarintType     WireType = 0
+	Fixed64Type    WireType = 1
+	BytesType      WireType = 2
+	StartGroupType WireType = 3
+	EndGroupType   WireType = 4
+	Fixed32Type    WireType = 5
+)
+
+const (
+	MinFieldNumber = 1
+	MaxFieldNumber = 1<<29 - 1
+)
+
+// DefaultMaxLen limits length-delimited fields when Decoder.MaxLen is
+// zero.
+const DefaultMaxLen = 64 << 20
+
+func validTag(num uint64, typ WireType) bool {
+	return num >= MinFieldNumber && num <= MaxFieldNumber && typ <= Fixed32Type
+}
+
+// ReadTag reads a field tag. A stream that ends before the tag gives
+// io.EOF.
+func ReadTag(r binary.BinaryReader) (int32, WireType, error) {
+	v := r.ReadUvarint()
+	if err := r.Error(); err != nil {
+		return 0, 0, err
+	}
+	num, typ := v>>3, WireType(v&7)
+	if !validTag(num, typ) {
+		return 0, 0, ErrInvalidTag
+	}
+	return int32(num), typ, nil
+}
+
+func WriteTag(w binary.BinaryWriter, num int32, typ WireType) {
+	w.WriteUvarint(uint64(num)<<3 | uint64(typ))
+}
+
+func ReadFixed32(r binary.BinaryReader) uint32 {
+	return r.ReadUint32LE()
+}
+
+func WriteFixed32(w binary.BinaryWriter, v uint32) {
+	w.WriteUint32LE(v)
+}
+
+func ReadFixed64(r binary.BinaryReader) uint64 {
+	return r.ReadUint64LE()
+}
+
+func WriteFixed64(w binary.BinaryWriter, v uint64) {
+	w.WriteUint64LE(v)
+}
+
+// ReadLengthDelimited reads a length prefixed payload of at most maxLen
+// bytes, as used by strings, bytes, embedded messages and packed fields.
+func ReadLengthDelimited(r binary.BinaryReader, maxLen int) ([]byte, error) {
+	n := r.ReadUvarint()
+	if err := r.Error(); err != nil {
+		return nil, err
+	}
+	if n > uint64(maxLen) {
+		return nil, ErrTooLarge
+	}
+	b := r.ReadBytes(int(n))
+	return b, r.Error()
+}
+
+func WriteLengthDelimited(w binary.BinaryWriter, b []byte) {
+	w.WriteUvarint(uint64(len(b)))
+	w.WriteBytes(b)
+}
+
+// SkipField reads past the value of a field whose tag has been read, a
+// group is skipped up to its end tag.
+func SkipField(r binary.BinaryReader, num int32, typ WireType) error {
+	d := NewDecoder(r)
+	_, err := d.readValue(num, typ)
+	return err
+}
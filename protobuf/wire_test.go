@@ -0,0 +1,66 @@
+package protobuf
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/funny/binary"
+	"github.com/funny/utest"
+)
+
+func Test_Wire_ReadWrite(t *testing.T) {
+	var buf bytes.Buffer
+	w := binary.NewWriter(&buf)
+	WriteTag(w, 1, VarintType)
+	w.WriteUvarint(150)
+	WriteTag(w, 2, BytesType)
+	WriteLengthDelimited(w, []byte("testing"))
+	WriteTag(w, 3, Fixed32Type)
+	WriteFixed32(w, 0xDEADBEEF)
+	WriteTag(w, MaxFieldNumber, Fixed64Type)
+	WriteFixed64(w, 1)
+	WriteTag(w, 5, StartGroupType)
+	WriteTag(w, 6, VarintType)
+	w.WriteVarint(-1)
+	WriteTag(w, 5, EndGroupType)
+	utest.IsNilNow(t, w.Error())
+	utest.EqualNow(t, buf.Bytes()[:12], []byte{0x08, 0x96, 0x01, 0x12, 0x07, 't', 'e', 's', 't', 'i', 'n', 'g'})
+
+	r := binary.NewReader(&buf)
+	num, typ, err := ReadTag(r)
+	utest.IsNilNow(t, err)
+	utest.EqualNow(t, num, int32(1))
+	utest.EqualNow(t, typ, VarintType)
+	utest.EqualNow(t, r.ReadUvarint(), uint64(150))
+
+	num, typ, err = ReadTag(r)
+	utest.IsNilNow(t, err)
+	utest.EqualNow(t, typ, BytesType)
+	b, err := ReadLengthDelimited(r, 100)
+	utest.IsNilNow(t, err)
+	utest.EqualNow(t, string(b), "testing")
+
+	ReadTag(r)
+	utest.EqualNow(t, ReadFixed32(r), uint32(0xDEADBEEF))
+	num, _, _ = ReadTag(r)
+	utest.EqualNow(t, num, int32(MaxFieldNumber))
+	utest.EqualNow(t, ReadFixed64(r), uint64(1))
+
+	num, typ, err = ReadTag(r)
+	utest.IsNilNow(t, err)
+	utest.IsNilNow(t, SkipField(r, num, typ))
+	_, _, err = ReadTag(r)
+	utest.EqualNow(t, err, io.EOF)
+}
+
+func Test_Wire_Errors(t *testing.T) {
+	for _, b := range [][]byte{{0x00}, {0x07}, {0x0E}, {0x80, 0x80, 0x80, 0x80, 0x80, 0x01}} {
+		_, _, err := ReadTag(binary.NewReader(bytes.NewReader(b)))
+		utest.EqualNow(t, err, ErrInvalidTag)
+	}
+	_, err := ReadLengthDelimited(binary.NewReader(bytes.NewReader([]byte{0x05, 1, 2, 3, 4, 5})), 4)
+	utest.EqualNow(t, err, ErrTooLarge)
+	_, err = ReadLengthDelimited(binary.NewReader(bytes.NewReader([]byte{0x05, 1, 2})), 10)
+	utest.NotNilNow(t, err)
+}
@@ -2,13 +2,14 @@ package binary
 
 import (
 	"io"
+	"net"
 )
 
-var zero [MaxVarintLen64]byte
+var zero [MaxVarintLen128]byte
 
 type Reader struct {
 	R   io.Reader
-	buf [MaxVarintLen64]byte
+	buf [MaxVarintLen128]byte
 	err error
 }
 
@@ -43,6 +44,50 @@ func (reader *Reader) ReadString(n int) string {
 	return string(reader.ReadBytes(n))
 }
 
+func (reader *Reader) ReadBCD(nDigits int) (v uint64) {
+	if reader.err == nil {
+		b := reader.ReadBytes(BCDSize(nDigits))
+		if reader.err == nil {
+			v, reader.err = GetBCD(b, nDigits)
+		}
+	}
+	return
+}
+
+func (reader *Reader) ReadPackedDecimal(nDigits int) (v int64) {
+	if reader.err == nil {
+		b := reader.ReadBytes(PackedDecimalSize(nDigits))
+		if reader.err == nil {
+			v, reader.err = GetPackedDecimal(b, nDigits)
+		}
+	}
+	return
+}
+
+func (reader *Reader) ReadIPv4() net.IP {
+	return net.IP(reader.ReadBytes(net.IPv4len))
+}
+
+func (reader *Reader) ReadIPv6() net.IP {
+	return net.IP(reader.ReadBytes(net.IPv6len))
+}
+
+func (reader *Reader) ReadMAC() net.HardwareAddr {
+	return net.HardwareAddr(reader.ReadBytes(MACLen))
+}
+
+func (reader *Reader) ReadUUID() UUID {
+	return GetUUID(reader.ReadBytes(UUIDLen))
+}
+
+func (reader *Reader) ReadGUID() UUID {
+	return GetGUID(reader.ReadBytes(UUIDLen))
+}
+
+func (reader *Reader) ReadBools(n int, order BitOrder) []bool {
+	return GetBools(reader.ReadBytes(BoolsSize(n)), n, order)
+}
+
 func (reader *Reader) ReadUvarint() (v uint64) {
 	if reader.err == nil {
 		v, reader.err = ReadUvarint(reader)
@@ -57,6 +102,48 @@ func (reader *Reader) ReadVarint() (v int64) {
 	return
 }
 
+func (reader *Reader) ReadQuicVarint() (v uint64) {
+	if reader.err == nil {
+		v, reader.err = ReadQuicVarint(reader)
+	}
+	return
+}
+
+func (reader *Reader) ReadSQLiteVarint() (v uint64) {
+	if reader.err == nil {
+		v, reader.err = ReadSQLiteVarint(reader)
+	}
+	return
+}
+
+func (reader *Reader) ReadVLQ() (v uint64) {
+	if reader.err == nil {
+		v, reader.err = ReadVLQ(reader)
+	}
+	return
+}
+
+func (reader *Reader) ReadGitVarint() (v uint64) {
+	if reader.err == nil {
+		v, reader.err = ReadGitVarint(reader)
+	}
+	return
+}
+
+func (reader *Reader) ReadUvarint128() (v Uint128) {
+	if reader.err == nil {
+		v, reader.err = ReadUvarint128(reader)
+	}
+	return
+}
+
+func (reader *Reader) ReadVarint128() (v Int128) {
+	if reader.err == nil {
+		v, reader.err = ReadVarint128(reader)
+	}
+	return
+}
+
 func (reader *Reader) seek(n int) (b []byte) {
 	if reader.err == nil {
 		b = reader.buf[:n]
@@ -151,6 +238,70 @@ func (reader *Reader) ReadFloat64LE() float64 {
 	return GetFloat64LE(reader.seek(8))
 }
 
+func (reader *Reader) ReadComplex64BE() complex64 {
+	return GetComplex64BE(reader.seek(8))
+}
+
+func (reader *Reader) ReadComplex64LE() complex64 {
+	return GetComplex64LE(reader.seek(8))
+}
+
+func (reader *Reader) ReadComplex128BE() complex128 {
+	return GetComplex128BE(reader.seek(16))
+}
+
+func (reader *Reader) ReadComplex128LE() complex128 {
+	return GetComplex128LE(reader.seek(16))
+}
+
+func (reader *Reader) ReadFloat80BE() float64 {
+	return GetFloat80BE(reader.seek(10))
+}
+
+func (reader *Reader) ReadFloat80LE() float64 {
+	return GetFloat80LE(reader.seek(10))
+}
+
+func (reader *Reader) ReadIBMFloat32BE() float64 {
+	return GetIBMFloat32BE(reader.seek(4))
+}
+
+func (reader *Reader) ReadIBMFloat64BE() float64 {
+	return GetIBMFloat64BE(reader.seek(8))
+}
+
+func (reader *Reader) ReadUint128BE() Uint128 {
+	return GetUint128BE(reader.seek(16))
+}
+
+func (reader *Reader) ReadUint128LE() Uint128 {
+	return GetUint128LE(reader.seek(16))
+}
+
+func (reader *Reader) ReadInt128BE() Int128 {
+	return GetInt128BE(reader.seek(16))
+}
+
+func (reader *Reader) ReadInt128LE() Int128 {
+	return GetInt128LE(reader.seek(16))
+}
+
+func (reader *Reader) ReadFloat16BE() float32 {
+	return GetFloat16BE(reader.seek(2))
+}
+
+func (reader *Reader) ReadFloat16LE() float32 {
+	return GetFloat16LE(reader.seek(2))
+}
+
+func (reader *Reader) ReadBFloat16BE() float32 {
+	return GetBFloat16BE(reader.seek(2))
+}
+
+func (reader *Reader) ReadBFloat16LE() float32 {
+	return GetBFloat16LE(reader.seek(2))
+}
+
 func (reader *Reader) ReadInt8() int8     { return int8(reader.ReadUint8()) }
 func (reader *Reader) ReadInt16BE() int16 { return int16(reader.ReadUint16BE()) }
 func (reader *Reader) ReadInt16LE() int16 { return int16(reader.ReadUint16LE()) }
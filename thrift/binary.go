@@ -0,0 +1,251 @@
+package thrift
+
+import (
+	"math"
+
+	"github.com/funny/binary"
+)
+
+const (
+	binaryVersion1    = 0x80010000
+	binaryVersionMask = 0xffff0000
+)
+
+// BinaryWriter writes the binary protocol: big endian integers, 32-bit
+// sizes and strict message headers.
+type BinaryWriter struct {
+	w   binary.BinaryWriter
+	err error
+}
+
+var _ ProtocolWriter = (*BinaryWriter)(nil)
+
+func NewBinaryWriter(w binary.BinaryWriter) *BinaryWriter {
+	return &BinaryWriter{w: w}
+}
+
+func (p *BinaryWriter) Error() error {
+	if p.err != nil {
+		return p.err
+	}
+	return p.w.Error()
+}
+
+func (p *BinaryWriter) writeSize(n int) {
+	if n < 0 || n > math.MaxInt32 {
+		if p.err == nil {
+			p.err = ErrTooLarge
+		}
+		n = 0
+	}
+	p.w.WriteInt32BE(int32(n))
+}
+
+func (p *BinaryWriter) WriteMessageBegin(name string, typ MessageType, seq int32) {
+	p.w.WriteUint32BE(binaryVersion1 | uint32(typ))
+	p.WriteString(name)
+	p.w.WriteInt32BE(seq)
+}
+
+func (p *BinaryWriter) WriteMessageEnd()  {}
+func (p *BinaryWriter) WriteStructBegin() {}
+func (p *BinaryWriter) WriteStructEnd()   {}
+
+func (p *BinaryWriter) WriteFieldBegin(typ Type, id int16) {
+	p.w.WriteUint8(uint8(typ))
+	p.w.WriteInt16BE(id)
+}
+
+func (p *BinaryWriter) WriteFieldEnd() {}
+
+func (p *BinaryWriter) WriteFieldStop() {
+	p.w.WriteUint8(uint8(TypeStop))
+}
+
+func (p *BinaryWriter) WriteMapBegin(keyType, valueType Type, size int) {
+	p.w.WriteUint8(uint8(keyType))
+	p.w.WriteUint8(uint8(valueType))
+	p.writeSize(size)
+}
+
+func (p *BinaryWriter) WriteMapEnd() {}
+
+func (p *BinaryWriter) WriteListBegin(elemType Type, size int) {
+	p.w.WriteUint8(uint8(elemType))
+	p.writeSize(size)
+}
+
+func (p *BinaryWriter) WriteListEnd() {}
+
+func (p *BinaryWriter) WriteSetBegin(elemType Type, size int) {
+	p.WriteListBegin(elemType, size)
+}
+
+func (p *BinaryWriter) WriteSetEnd() {}
+
+func (p *BinaryWriter) WriteBool(v bool) {
+	if v {
+		p.w.WriteUint8(1)
+	} else {
+		p.w.WriteUint8(0)
+	}
+}
+
+func (p *BinaryWriter) WriteI8(v int8)        { p.w.WriteInt8(v) }
+func (p *BinaryWriter) WriteI16(v int16)      { p.w.WriteInt16BE(v) }
+func (p *BinaryWriter) WriteI32(v int32)      { p.w.WriteInt32BE(v) }
+func (p *BinaryWriter) WriteI64(v int64)      { p.w.WriteInt64BE(v) }
+func (p *BinaryWriter) WriteDouble(v float64) { p.w.WriteFloat64BE(v) }
+func (p *BinaryWriter) WriteUUID(v binary.UUID) {
+	p.w.WriteUUID(v)
+}
+
+func (p *BinaryWriter) WriteString(v string) {
+	p.writeSize(len(v))
+	p.w.WriteString(v)
+}
+
+func (p *BinaryWriter) WriteBinary(v []byte) {
+	p.writeSize(len(v))
+	p.w.WriteBytes(v)
+}
+
+// BinaryReader reads the binary protocol. Both strict and old style
+// message headers are accepted.
+type BinaryReader struct {
+	r binary.BinaryReader
+
+	// MaxLen limits sizes, zero means DefaultMaxLen.
+	MaxLen int
+}
+
+var _ ProtocolReader = (*BinaryReader)(nil)
+
+func NewBinaryReader(r binary.BinaryReader) *BinaryReader {
+	return &BinaryReader{r: r}
+}
+
+func (p *BinaryReader) readSize() (int, error) {
+	n := p.r.ReadInt32BE()
+	if err := p.r.Error(); err != nil {
+		return 0, err
+	}
+	return checkSize(int64(n), p.MaxLen)
+}
+
+func (p *BinaryReader) ReadMessageBegin() (name string, typ MessageType, seq int32, err error) {
+	v := p.r.ReadInt32BE()
+	if err = p.r.Error(); err != nil {
+		return
+	}
+	if v < 0 {
+		if uint32(v)&binaryVersionMask != binaryVersion1 {
+			return "", 0, 0, ErrBadVersion
+		}
+		typ = MessageType(v)
+		if name, err = p.ReadString(); err != nil {
+			return
+		}
+	} else {
+		var n int
+		if n, err = checkSize(int64(v), p.MaxLen); err != nil {
+			return
+		}
+		name = p.r.ReadString(n)
+		typ = MessageType(p.r.ReadUint8())
+	}
+	seq = p.r.ReadInt32BE()
+	err = p.r.Error()
+	return
+}
+
+func (p *BinaryReader) ReadMessageEnd() error  { return nil }
+func (p *BinaryReader) ReadStructBegin() error { return nil }
+func (p *BinaryReader) ReadStructEnd() error   { return nil }
+
+func (p *BinaryReader) ReadFieldBegin() (Type, int16, error) {
+	typ := Type(p.r.ReadUint8())
+	if err := p.r.Error(); err != nil || typ == TypeStop {
+		return typ, 0, err
+	}
+	id := p.r.ReadInt16BE()
+	return typ, id, p.r.Error()
+}
+
+func (p *BinaryReader) ReadFieldEnd() error { return nil }
+
+func (p *BinaryReader) ReadMapBegin() (keyType, valueType Type, size int, err error) {
+	keyType = Type(p.r.ReadUint8())
+	valueType = Type(p.r.ReadUint8())
+	size, err = p.readSize()
+	return
+}
+
+func (p *BinaryReader) ReadMapEnd() error { return nil }
+
+func (p *BinaryReader) ReadListBegin() (Type, int, error) {
+	typ := Type(p.r.ReadUint8())
+	size, err := p.readSize()
+	return typ, size, err
+}
+
+func (p *BinaryReader) ReadListEnd() error { return nil }
+
+func (p *BinaryReader) ReadSetBegin() (Type, int, error) {
+	return p.ReadListBegin()
+}
+
+func (p *BinaryReader) ReadSetEnd() error { return nil }
+
+func (p *BinaryReader) ReadBool() (bool, error) {
+	v := p.r.ReadUint8()
+	return v != 0, p.r.Error()
+}
+
+func (p *BinaryReader) ReadI8() (int8, error) {
+	v := p.r.ReadInt8()
+	return v, p.r.Error()
+}
+
+func (p *BinaryReader) ReadI16() (int16, error) {
+	v := p.r.ReadInt16BE()
+	return v, p.r.Error()
+}
+
+func (p *BinaryReader) ReadI32() (int32, error) {
+	v := p.r.ReadInt32BE()
+	return v, p.r.Error()
+}
+
+func (p *BinaryReader) ReadI64() (int64, error) {
+	v := p.r.ReadInt64BE()
+	return v, p.r.Error()
+}
+
+func (p *BinaryReader) ReadDouble() (float64, error) {
+	v := p.r.ReadFloat64BE()
+	return v, p.r.Error()
+}
+
+func (p *BinaryReader) ReadUUID() (binary.UUID, error) {
+	v := p.r.ReadUUID()
+	return v, p.r.Error()
+}
+
+func (p *BinaryReader) ReadString() (string, error) {
+	n, err := p.readSize()
+	if err != nil {
+		return "", err
+	}
+	v := p.r.ReadString(n)
+	return v, p.r.Error()
+}
+
+func (p *BinaryReader) ReadBinary() ([]byte, error) {
+	n, err := p.readSize()
+	if err != nil {
+		return nil, err
+	}
+	v := p.r.ReadBytes(n)
+	return v, p.r.Error()
+}
@@ -0,0 +1,58 @@
+package thrift
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/funny/binary"
+	"github.com/funny/utest"
+)
+
+func Test_Binary_Encoding(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewBinaryWriter(binary.NewWriter(&buf))
+	w.WriteMessageBegin("ping", Call, 1)
+	w.WriteFieldBegin(TypeI16, 2)
+	w.WriteI16(-2)
+	w.WriteFieldBegin(TypeBool, 3)
+	w.WriteBool(true)
+	w.WriteMapBegin(TypeString, TypeI32, 1)
+	w.WriteListBegin(TypeDouble, 0)
+	w.WriteFieldStop()
+	utest.IsNilNow(t, w.Error())
+	utest.EqualNow(t, buf.Bytes(), []byte{
+		0x80, 0x01, 0x00, 0x01, 0, 0, 0, 4, 'p', 'i', 'n', 'g', 0, 0, 0, 1,
+		0x06, 0x00, 0x02, 0xff, 0xfe,
+		0x02, 0x00, 0x03, 0x01,
+		0x0b, 0x08, 0, 0, 0, 1,
+		0x04, 0, 0, 0, 0,
+		0x00,
+	})
+}
+
+func Test_Binary_OldMessage(t *testing.T) {
+	b := []byte{0, 0, 0, 4, 'p', 'i', 'n', 'g', 4, 0, 0, 0, 9}
+	name, typ, seq, err := NewBinaryReader(binary.NewReader(bytes.NewReader(b))).ReadMessageBegin()
+	utest.IsNilNow(t, err)
+	utest.EqualNow(t, name, "ping")
+	utest.EqualNow(t, typ, Oneway)
+	utest.EqualNow(t, seq, int32(9))
+}
+
+func Test_Binary_Errors(t *testing.T) {
+	newReader := func(b ...byte) *BinaryReader {
+		return NewBinaryReader(binary.NewReader(bytes.NewReader(b)))
+	}
+	_, _, _, err := newReader(0x80, 0x02, 0, 1).ReadMessageBegin()
+	utest.EqualNow(t, err, ErrBadVersion)
+	_, err = newReader(0xff, 0xff, 0xff, 0xff).ReadString()
+	utest.EqualNow(t, err, ErrNegativeSize)
+	r := newReader(0, 0, 0, 9, 1, 2, 3)
+	r.MaxLen = 8
+	_, err = r.ReadBinary()
+	utest.EqualNow(t, err, ErrTooLarge)
+	_, err = newReader(0, 0, 0, 9, 1, 2, 3).ReadBinary()
+	utest.NotNilNow(t, err)
+	_, err = newReader(0, 0).ReadI32()
+	utest.NotNilNow(t, err)
+}
@@ -0,0 +1,454 @@
+package thrift
+
+import (
+	"math"
+
+	"github.com/funny/binary"
+)
+
+const (
+	compactProtocolID = 0x82
+	compactVersion    = 1
+	compactTypeShift  = 5
+)
+
+// Element types of the compact protocol.
+const (
+	ctStop   = 0
+	ctTrue   = 1
+	ctFalse  = 2
+	ctI8     = 3
+	ctI16    = 4
+	ctI32    = 5
+	ctI64    = 6
+	ctDouble = 7
+	ctBinary = 8
+	ctList   = 9
+	ctSet    = 10
+	ctMap    = 11
+	ctStruct = 12
+	ctUUID   = 13
+)
+
+var compactTypes = [...]byte{
+	TypeStop:   ctStop,
+	TypeBool:   ctTrue,
+	TypeI8:     ctI8,
+	TypeDouble: ctDouble,
+	TypeI16:    ctI16,
+	TypeI32:    ctI32,
+	TypeI64:    ctI64,
+	TypeString: ctBinary,
+	TypeStruct: ctStruct,
+	TypeMap:    ctMap,
+	TypeSet:    ctSet,
+	TypeList:   ctList,
+	TypeUUID:   ctUUID,
+}
+
+var types = [...]Type{
+	ctStop:   TypeStop,
+	ctTrue:   TypeBool,
+	ctFalse:  TypeBool,
+	ctI8:     TypeI8,
+	ctI16:    TypeI16,
+	ctI32:    TypeI32,
+	ctI64:    TypeI64,
+	ctDouble: TypeDouble,
+	ctBinary: TypeString,
+	ctList:   TypeList,
+	ctSet:    TypeSet,
+	ctMap:    TypeMap,
+	ctStruct: TypeStruct,
+	ctUUID:   TypeUUID,
+}
+
+func compactType(t Type) (byte, error) {
+	if int(t) >= len(compactTypes) || (t != TypeStop && compactTypes[t] == ctStop) {
+		return 0, ErrInvalidType
+	}
+	return compactTypes[t], nil
+}
+
+func fromCompactType(t byte) (Type, error) {
+	if int(t) >= len(types) {
+		return 0, ErrInvalidType
+	}
+	return types[t], nil
+}
+
+// CompactWriter writes the compact protocol: zigzag varints, field ids as
+// deltas from the previous field and booleans folded into field headers.
+type CompactWriter struct {
+	w   binary.BinaryWriter
+	err error
+
+	lastID  int16
+	lastIDs []int16
+
+	// A bool field header is held back until WriteBool gives its value.
+	boolID      int16
+	boolPending bool
+}
+
+var _ ProtocolWriter = (*CompactWriter)(nil)
+
+func NewCompactWriter(w binary.BinaryWriter) *CompactWriter {
+	return &CompactWriter{w: w}
+}
+
+func (p *CompactWriter) Error() error {
+	if p.err != nil {
+		return p.err
+	}
+	return p.w.Error()
+}
+
+func (p *CompactWriter) setErr(err error) {
+	if p.err == nil {
+		p.err = err
+	}
+}
+
+func (p *CompactWriter) elemType(t Type) byte {
+	ct, err := compactType(t)
+	if err != nil {
+		p.setErr(err)
+	}
+	return ct
+}
+
+func (p *CompactWriter) writeSize(n int) {
+	if n < 0 || n > math.MaxInt32 {
+		p.setErr(ErrTooLarge)
+		n = 0
+	}
+	p.w.WriteUvarint(uint64(n))
+}
+
+func (p *CompactWriter) WriteMessageBegin(name string, typ MessageType, seq int32) {
+	p.w.WriteUint8(compactProtocolID)
+	p.w.WriteUint8(compactVersion | byte(typ)<<compactTypeShift)
+	p.w.WriteUvarint(uint64(uint32(seq)))
+	p.WriteString(name)
+}
+
+func (p *CompactWriter) WriteMessageEnd() {}
+
+func (p *CompactWriter) WriteStructBegin() {
+	p.lastIDs = append(p.lastIDs, p.lastID)
+	p.lastID = 0
+}
+
+func (p *CompactWriter) WriteStructEnd() {
+	if len(p.lastIDs) == 0 {
+		p.setErr(ErrUnexpectedEnd)
+		return
+	}
+	p.lastID = p.lastIDs[len(p.lastIDs)-1]
+	p.lastIDs = p.lastIDs[:len(p.lastIDs)-1]
+}
+
+func (p *CompactWriter) writeFieldHeader(ct byte, id int16) {
+	if delta := int(id) - int(p.lastID); delta > 0 && delta <= 15 {
+		p.w.WriteUint8(byte(delta)<<4 | ct)
+	} else {
+		p.w.WriteUint8(ct)
+		p.w.WriteVarint(int64(id))
+	}
+	p.lastID = id
+}
+
+func (p *CompactWriter) WriteFieldBegin(typ Type, id int16) {
+	if typ == TypeBool {
+		p.boolID, p.boolPending = id, true
+		return
+	}
+	p.writeFieldHeader(p.elemType(typ), id)
+}
+
+func (p *CompactWriter) WriteFieldEnd() {}
+
+func (p *CompactWriter) WriteFieldStop() {
+	p.w.WriteUint8(ctStop)
+}
+
+func (p *CompactWriter) WriteMapBegin(keyType, valueType Type, size int) {
+	if size == 0 {
+		p.w.WriteUint8(0)
+		return
+	}
+	p.writeSize(size)
+	p.w.WriteUint8(p.elemType(keyType)<<4 | p.elemType(valueType))
+}
+
+func (p *CompactWriter) WriteMapEnd() {}
+
+func (p *CompactWriter) WriteListBegin(elemType Type, size int) {
+	ct := p.elemType(elemType)
+	if size >= 0 && size < 15 {
+		p.w.WriteUint8(byte(size)<<4 | ct)
+		return
+	}
+	p.w.WriteUint8(0xf0 | ct)
+	p.writeSize(size)
+}
+
+func (p *CompactWriter) WriteListEnd() {}
+
+func (p *CompactWriter) WriteSetBegin(elemType Type, size int) {
+	p.WriteListBegin(elemType, size)
+}
+
+func (p *CompactWriter) WriteSetEnd() {}
+
+// WriteBool completes a pending bool field header, elsewhere it writes a
+// single byte.
+func (p *CompactWriter) WriteBool(v bool) {
+	ct := byte(ctFalse)
+	if v {
+		ct = ctTrue
+	}
+	if p.boolPending {
+		p.boolPending = false
+		p.writeFieldHeader(ct, p.boolID)
+		return
+	}
+	p.w.WriteUint8(ct)
+}
+
+func (p *CompactWriter) WriteI8(v int8)        { p.w.WriteInt8(v) }
+func (p *CompactWriter) WriteI16(v int16)      { p.w.WriteVarint(int64(v)) }
+func (p *CompactWriter) WriteI32(v int32)      { p.w.WriteVarint(int64(v)) }
+func (p *CompactWriter) WriteI64(v int64)      { p.w.WriteVarint(v) }
+func (p *CompactWriter) WriteDouble(v float64) { p.w.WriteFloat64LE(v) }
+func (p *CompactWriter) WriteUUID(v binary.UUID) {
+	p.w.WriteUUID(v)
+}
+
+func (p *CompactWriter) WriteString(v string) {
+	p.writeSize(len(v))
+	p.w.WriteString(v)
+}
+
+func (p *CompactWriter) WriteBinary(v []byte) {
+	p.writeSize(len(v))
+	p.w.WriteBytes(v)
+}
+
+// CompactReader reads the compact protocol.
+type CompactReader struct {
+	r binary.BinaryReader
+
+	lastID  int16
+	lastIDs []int16
+
+	// Set by ReadFieldBegin for a bool field, the value is in the header.
+	boolValue   bool
+	boolPending bool
+
+	// MaxLen limits sizes, zero means DefaultMaxLen.
+	MaxLen int
+}
+
+var _ ProtocolReader = (*CompactReader)(nil)
+
+func NewCompactReader(r binary.BinaryReader) *CompactReader {
+	return &CompactReader{r: r}
+}
+
+func (p *CompactReader) readSize() (int, error) {
+	n := p.r.ReadUvarint()
+	if err := p.r.Error(); err != nil {
+		return 0, err
+	}
+	if n > math.MaxInt32 {
+		return 0, ErrTooLarge
+	}
+	return checkSize(int64(n), p.MaxLen)
+}
+
+func (p *CompactReader) readVarint(min, max int64) (int64, error) {
+	v := p.r.ReadVarint()
+	if err := p.r.Error(); err != nil {
+		return 0, err
+	}
+	if v < min || v > max {
+		return 0, ErrOverflow
+	}
+	return v, nil
+}
+
+func (p *CompactReader) ReadMessageBegin() (name string, typ MessageType, seq int32, err error) {
+	id := p.r.ReadUint8()
+	b := p.r.ReadUint8()
+	if err = p.r.Error(); err != nil {
+		return
+	}
+	if id != compactProtocolID || b&(1<<compactTypeShift-1) != compactVersion {
+		return "", 0, 0, ErrBadVersion
+	}
+	typ = MessageType(b >> compactTypeShift)
+	s := p.r.ReadUvarint()
+	if err = p.r.Error(); err != nil {
+		return
+	}
+	if s > math.MaxUint32 {
+		return "", 0, 0, ErrOverflow
+	}
+	seq = int32(uint32(s))
+	name, err = p.ReadString()
+	return
+}
+
+func (p *CompactReader) ReadMessageEnd() error { return nil }
+
+func (p *CompactReader) ReadStructBegin() error {
+	if len(p.lastIDs) >= maxDepth {
+		return ErrTooDeep
+	}
+	p.lastIDs = append(p.lastIDs, p.lastID)
+	p.lastID = 0
+	return nil
+}
+
+func (p *CompactReader) ReadStructEnd() error {
+	if len(p.lastIDs) == 0 {
+		return ErrUnexpectedEnd
+	}
+	p.lastID = p.lastIDs[len(p.lastIDs)-1]
+	p.lastIDs = p.lastIDs[:len(p.lastIDs)-1]
+	return nil
+}
+
+func (p *CompactReader) ReadFieldBegin() (Type, int16, error) {
+	b := p.r.ReadUint8()
+	if err := p.r.Error(); err != nil {
+		return 0, 0, err
+	}
+	ct := b & 0x0f
+	if ct == ctStop {
+		return TypeStop, 0, nil
+	}
+	typ, err := fromCompactType(ct)
+	if err != nil {
+		return 0, 0, err
+	}
+	id := p.lastID + int16(b>>4)
+	if b>>4 == 0 {
+		v, err := p.readVarint(math.MinInt16, math.MaxInt16)
+		if err != nil {
+			return 0, 0, err
+		}
+		id = int16(v)
+	}
+	p.lastID = id
+	if typ == TypeBool {
+		p.boolValue, p.boolPending = ct == ctTrue, true
+	}
+	return typ, id, nil
+}
+
+func (p *CompactReader) ReadFieldEnd() error { return nil }
+
+func (p *CompactReader) ReadMapBegin() (keyType, valueType Type, size int, err error) {
+	if size, err = p.readSize(); err != nil || size == 0 {
+		return
+	}
+	b := p.r.ReadUint8()
+	if err = p.r.Error(); err != nil {
+		return
+	}
+	if keyType, err = fromCompactType(b >> 4); err != nil {
+		return
+	}
+	valueType, err = fromCompactType(b & 0x0f)
+	return
+}
+
+func (p *CompactReader) ReadMapEnd() error { return nil }
+
+func (p *CompactReader) ReadListBegin() (Type, int, error) {
+	b := p.r.ReadUint8()
+	if err := p.r.Error(); err != nil {
+		return 0, 0, err
+	}
+	typ, err := fromCompactType(b & 0x0f)
+	if err != nil {
+		return 0, 0, err
+	}
+	size := int(b >> 4)
+	if size == 15 {
+		if size, err = p.readSize(); err != nil {
+			return 0, 0, err
+		}
+	}
+	return typ, size, nil
+}
+
+func (p *CompactReader) ReadListEnd() error { return nil }
+
+func (p *CompactReader) ReadSetBegin() (Type, int, error) {
+	return p.ReadListBegin()
+}
+
+func (p *CompactReader) ReadSetEnd() error { return nil }
+
+// ReadBool returns the value of a bool field header read by ReadFieldBegin,
+// elsewhere it reads a single byte.
+func (p *CompactReader) ReadBool() (bool, error) {
+	if p.boolPending {
+		p.boolPending = false
+		return p.boolValue, nil
+	}
+	v := p.r.ReadUint8()
+	return v == ctTrue, p.r.Error()
+}
+
+func (p *CompactReader) ReadI8() (int8, error) {
+	v := p.r.ReadInt8()
+	return v, p.r.Error()
+}
+
+func (p *CompactReader) ReadI16() (int16, error) {
+	v, err := p.readVarint(math.MinInt16, math.MaxInt16)
+	return int16(v), err
+}
+
+func (p *CompactReader) ReadI32() (int32, error) {
+	v, err := p.readVarint(math.MinInt32, math.MaxInt32)
+	return int32(v), err
+}
+
+func (p *CompactReader) ReadI64() (int64, error) {
+	v := p.r.ReadVarint()
+	return v, p.r.Error()
+}
+
+func (p *CompactReader) ReadDouble() (float64, error) {
+	v := p.r.ReadFloat64LE()
+	return v, p.r.Error()
+}
+
+func (p *CompactReader) ReadUUID() (binary.UUID, error) {
+	v := p.r.ReadUUID()
+	return v, p.r.Error()
+}
+
+func (p *CompactReader) ReadString() (string, error) {
+	n, err := p.readSize()
+	if err != nil {
+		return "", err
+	}
+	v := p.r.ReadString(n)
+	return v, p.r.Error()
+}
+
+func (p *CompactReader) ReadBinary() ([]byte, error) {
+	n, err := p.readSize()
+	if err != nil {
+		return nil, err
+	}
+	v := p.r.ReadBytes(n)
+	return v, p.r.Error()
+}
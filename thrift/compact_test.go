@@ -0,0 +1,79 @@
+package thrift
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/funny/binary"
+	"github.com/funny/utest"
+)
+
+func Test_Compact_Encoding(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewCompactWriter(binary.NewWriter(&buf))
+	w.WriteMessageBegin("ping", Call, 1)
+	w.WriteStructBegin()
+	w.WriteFieldBegin(TypeI32, 1)
+	w.WriteI32(150)
+	w.WriteFieldBegin(TypeBool, 3)
+	w.WriteBool(true)
+	w.WriteFieldBegin(TypeString, 20)
+	w.WriteString("a")
+	w.WriteFieldBegin(TypeStruct, 21)
+	w.WriteStructBegin()
+	w.WriteFieldBegin(TypeI8, 1)
+	w.WriteI8(-1)
+	w.WriteFieldStop()
+	w.WriteStructEnd()
+	w.WriteFieldBegin(TypeMap, 22)
+	w.WriteMapBegin(TypeString, TypeI64, 0)
+	w.WriteFieldBegin(TypeMap, 23)
+	w.WriteMapBegin(TypeString, TypeI64, 2)
+	w.WriteFieldBegin(TypeList, 24)
+	w.WriteListBegin(TypeI16, 3)
+	w.WriteFieldBegin(TypeSet, 25)
+	w.WriteSetBegin(TypeDouble, 20)
+	w.WriteFieldStop()
+	w.WriteStructEnd()
+	utest.IsNilNow(t, w.Error())
+	utest.EqualNow(t, buf.Bytes(), []byte{
+		0x82, 0x21, 0x01, 0x04, 'p', 'i', 'n', 'g',
+		0x15, 0xac, 0x02,
+		0x21,
+		0x08, 0x28, 0x01, 'a',
+		0x1c, 0x13, 0xff, 0x00,
+		0x1b, 0x00,
+		0x1b, 0x02, 0x86,
+		0x19, 0x34,
+		0x1a, 0xf7, 0x14,
+		0x00,
+	})
+}
+
+func Test_Compact_Errors(t *testing.T) {
+	newReader := func(b ...byte) *CompactReader {
+		return NewCompactReader(binary.NewReader(bytes.NewReader(b)))
+	}
+	_, _, _, err := newReader(0x80, 0x21).ReadMessageBegin()
+	utest.EqualNow(t, err, ErrBadVersion)
+	_, _, _, err = newReader(0x82, 0x22).ReadMessageBegin()
+	utest.EqualNow(t, err, ErrBadVersion)
+	_, _, err = newReader(0x1e).ReadFieldBegin()
+	utest.EqualNow(t, err, ErrInvalidType)
+	_, _, err = newReader(0x05, 0x80, 0x80, 0x04).ReadFieldBegin()
+	utest.EqualNow(t, err, ErrOverflow)
+	_, err = newReader(0x80, 0x80, 0x80, 0x80, 0x10).ReadI32()
+	utest.EqualNow(t, err, ErrOverflow)
+	r := newReader(0xf3, 0x09)
+	r.MaxLen = 8
+	_, _, err = r.ReadListBegin()
+	utest.EqualNow(t, err, ErrTooLarge)
+	utest.EqualNow(t, newReader().ReadStructEnd(), ErrUnexpectedEnd)
+
+	w := NewCompactWriter(binary.NewWriter(&bytes.Buffer{}))
+	w.WriteListBegin(TypeVoid, 1)
+	utest.EqualNow(t, w.Error(), ErrInvalidType)
+	w = NewCompactWriter(binary.NewWriter(&bytes.Buffer{}))
+	w.WriteStructEnd()
+	utest.EqualNow(t, w.Error(), ErrUnexpectedEnd)
+}
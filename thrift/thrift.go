@@ -0,0 +1,221 @@
+// Package thrift implements the Apache Thrift binary and compact protocols
+// on top of the BinaryReader and BinaryWriter interfaces of
+// github.com/funny/binary.
+//
+// The protocols follow the shape of the Thrift library's TProtocol so
+// hand-written codecs read like generated ones, but no IDL compiler or
+// transport layer is involved. Writers keep the first error they detect
+// themselves, such as ErrTooLarge, and otherwise report the Error of the
+// underlying BinaryWriter, so Error is checked once after a message. Readers
+// return errors from every call.
+package thrift
+
+import (
+	"errors"
+
+	"github.com/funny/binary"
+)
+
+var (
+	ErrInvalidType   = errors.New("funny/binary/thrift: invalid type")
+	ErrBadVersion    = errors.New("funny/binary/thrift: bad protocol version")
+	ErrNegativeSize  = errors.New("funny/binary/thrift: negative size")
+	ErrTooLarge      = errors.New("funny/binary/thrift: size exceeds limit")
+	ErrTooDeep       = errors.New("funny/binary/thrift: nesting exceeds limit")
+	ErrOverflow      = errors.New("funny/binary/thrift: value overflows its type")
+	ErrUnexpectedEnd = errors.New("funny/binary/thrift: unbalanced end call")
+)
+
+// Type is a Thrift data type as written by the binary protocol.
+type Type byte
+
+const (
+	TypeStop   Type = 0
+	TypeVoid   Type = 1
+	TypeBool   Type = 2
+	TypeI8     Type = 3
+	TypeDouble Type = 4
+	TypeI16    Type = 6
+	TypeI32    Type = 8
+	TypeI64    Type = 10
+	TypeString Type = 11
+	TypeStruct Type = 12
+	TypeMap    Type = 13
+	TypeSet    Type = 14
+	TypeList   Type = 15
+	TypeUUID   Type = 16
+)
+
+// MessageType is the kind of a message.
+type MessageType byte
+
+const (
+	Call      MessageType = 1
+	Reply     MessageType = 2
+	Exception MessageType = 3
+	Oneway    MessageType = 4
+)
+
+// DefaultMaxLen limits strings and the element count of containers when
+// MaxLen of a reader is zero.
+const DefaultMaxLen = 16 << 20
+
+const maxDepth = 64
+
+// ProtocolWriter writes Thrift values. Strings and binaries share
+// TypeString, WriteBinary only differs in its argument.
+type ProtocolWriter interface {
+	Error() error
+
+	WriteMessageBegin(name string, typ MessageType, seq int32)
+	WriteMessageEnd()
+	WriteStructBegin()
+	WriteStructEnd()
+	WriteFieldBegin(typ Type, id int16)
+	WriteFieldEnd()
+	WriteFieldStop()
+	WriteMapBegin(keyType, valueType Type, size int)
+	WriteMapEnd()
+	WriteListBegin(elemType Type, size int)
+	WriteListEnd()
+	WriteSetBegin(elemType Type, size int)
+	WriteSetEnd()
+
+	WriteBool(v bool)
+	WriteI8(v int8)
+	WriteI16(v int16)
+	WriteI32(v int32)
+	WriteI64(v int64)
+	WriteDouble(v float64)
+	WriteString(v string)
+	WriteBinary(v []byte)
+	WriteUUID(v binary.UUID)
+}
+
+// ProtocolReader reads Thrift values. ReadFieldBegin returns TypeStop after
+// the last field of a struct.
+type ProtocolReader interface {
+	ReadMessageBegin() (name string, typ MessageType, seq int32, err error)
+	ReadMessageEnd() error
+	ReadStructBegin() error
+	ReadStructEnd() error
+	ReadFieldBegin() (typ Type, id int16, err error)
+	ReadFieldEnd() error
+	ReadMapBegin() (keyType, valueType Type, size int, err error)
+	ReadMapEnd() error
+	ReadListBegin() (elemType Type, size int, err error)
+	ReadListEnd() error
+	ReadSetBegin() (elemType Type, size int, err error)
+	ReadSetEnd() error
+
+	ReadBool() (bool, error)
+	ReadI8() (int8, error)
+	ReadI16() (int16, error)
+	ReadI32() (int32, error)
+	ReadI64() (int64, error)
+	ReadDouble() (float64, error)
+	ReadString() (string, error)
+	ReadBinary() ([]byte, error)
+	ReadUUID() (binary.UUID, error)
+}
+
+func checkSize(n int64, maxLen int) (int, error) {
+	if n < 0 {
+		return 0, ErrNegativeSize
+	}
+	if maxLen <= 0 {
+		maxLen = DefaultMaxLen
+	}
+	if n > int64(maxLen) {
+		return 0, ErrTooLarge
+	}
+	return int(n), nil
+}
+
+// Skip reads and discards a value of type typ, typically an unknown field.
+func Skip(r ProtocolReader, typ Type) error {
+	return skip(r, typ, maxDepth)
+}
+
+func skip(r ProtocolReader, typ Type, depth int) error {
+	if depth == 0 {
+		return ErrTooDeep
+	}
+	var err error
+	switch typ {
+	case TypeBool:
+		_, err = r.ReadBool()
+	case TypeI8:
+		_, err = r.ReadI8()
+	case TypeI16:
+		_, err = r.ReadI16()
+	case TypeI32:
+		_, err = r.ReadI32()
+	case TypeI64:
+		_, err = r.ReadI64()
+	case TypeDouble:
+		_, err = r.ReadDouble()
+	case TypeString:
+		_, err = r.ReadBinary()
+	case TypeUUID:
+		_, err = r.ReadUUID()
+	case TypeStruct:
+		if err = r.ReadStructBegin(); err != nil {
+			return err
+		}
+		for {
+			ft, _, err := r.ReadFieldBegin()
+			if err != nil {
+				return err
+			}
+			if ft == TypeStop {
+				break
+			}
+			if err = skip(r, ft, depth-1); err != nil {
+				return err
+			}
+			if err = r.ReadFieldEnd(); err != nil {
+				return err
+			}
+		}
+		err = r.ReadStructEnd()
+	case TypeMap:
+		kt, vt, n, err := r.ReadMapBegin()
+		if err != nil {
+			return err
+		}
+		for i := 0; i < n; i++ {
+			if err = skip(r, kt, depth-1); err != nil {
+				return err
+			}
+			if err = skip(r, vt, depth-1); err != nil {
+				return err
+			}
+		}
+		return r.ReadMapEnd()
+	case TypeList, TypeSet:
+		var et Type
+		var n int
+		if typ == TypeList {
+			et, n, err = r.ReadListBegin()
+		} else {
+			et, n, err = r.ReadSetBegin()
+		}
+		if err != nil {
+			return err
+		}
+		for i := 0; i < n; i++ {
+			if err = skip(r, et, depth-1); err != nil {
+				return err
+			}
+		}
+		if typ == TypeList {
+			err = r.ReadListEnd()
+		} else {
+			err = r.ReadSetEnd()
+		}
+	default:
+		err = ErrInvalidType
+	}
+	return err
+}
@@ -0,0 +1,183 @@
+package thrift
+
+import (
+	"bytes"
+	"math"
+	"testing"
+
+	"github.com/funny/binary"
+	"github.com/funny/utest"
+)
+
+func writeSample(p ProtocolWriter) {
+	p.WriteMessageBegin("getUser", Reply, -7)
+	p.WriteStructBegin()
+	p.WriteFieldBegin(TypeI32, 1)
+	p.WriteI32(math.MinInt32)
+	p.WriteFieldEnd()
+	p.WriteFieldBegin(TypeBool, 2)
+	p.WriteBool(true)
+	p.WriteFieldEnd()
+	p.WriteFieldBegin(TypeBool, 3)
+	p.WriteBool(false)
+	p.WriteFieldEnd()
+	p.WriteFieldBegin(TypeStruct, 40)
+	p.WriteStructBegin()
+	p.WriteFieldBegin(TypeString, -1)
+	p.WriteString("nested")
+	p.WriteFieldEnd()
+	p.WriteFieldStop()
+	p.WriteStructEnd()
+	p.WriteFieldEnd()
+	p.WriteFieldBegin(TypeMap, 41)
+	p.WriteMapBegin(TypeString, TypeDouble, 2)
+	p.WriteString("a")
+	p.WriteDouble(1.5)
+	p.WriteString("b")
+	p.WriteDouble(-2)
+	p.WriteMapEnd()
+	p.WriteFieldEnd()
+	p.WriteFieldBegin(TypeList, 42)
+	p.WriteListBegin(TypeBool, 20)
+	for i := 0; i < 20; i++ {
+		p.WriteBool(i%3 == 0)
+	}
+	p.WriteListEnd()
+	p.WriteFieldEnd()
+	p.WriteFieldBegin(TypeSet, 43)
+	p.WriteSetBegin(TypeI64, 1)
+	p.WriteI64(math.MaxInt64)
+	p.WriteSetEnd()
+	p.WriteFieldEnd()
+	p.WriteFieldBegin(TypeI16, 44)
+	p.WriteI16(-300)
+	p.WriteFieldEnd()
+	p.WriteFieldBegin(TypeI8, 45)
+	p.WriteI8(-1)
+	p.WriteFieldEnd()
+	p.WriteFieldBegin(TypeUUID, 46)
+	p.WriteUUID(binary.UUID{1, 2, 3})
+	p.WriteFieldEnd()
+	p.WriteFieldBegin(TypeString, 47)
+	p.WriteBinary([]byte{0, 1})
+	p.WriteFieldEnd()
+	p.WriteFieldStop()
+	p.WriteStructEnd()
+	p.WriteMessageEnd()
+}
+
+func readSample(t *testing.T, p ProtocolReader) {
+	name, typ, seq, err := p.ReadMessageBegin()
+	utest.IsNilNow(t, err)
+	utest.EqualNow(t, name, "getUser")
+	utest.EqualNow(t, typ, Reply)
+	utest.EqualNow(t, seq, int32(-7))
+	utest.IsNilNow(t, p.ReadStructBegin())
+
+	ft, id, err := p.ReadFieldBegin()
+	utest.IsNilNow(t, err)
+	utest.EqualNow(t, ft, TypeI32)
+	utest.EqualNow(t, id, int16(1))
+	i32, err := p.ReadI32()
+	utest.IsNilNow(t, err)
+	utest.EqualNow(t, i32, int32(math.MinInt32))
+
+	for _, want := range []bool{true, false} {
+		ft, _, _ = p.ReadFieldBegin()
+		utest.EqualNow(t, ft, TypeBool)
+		b, err := p.ReadBool()
+		utest.IsNilNow(t, err)
+		utest.EqualNow(t, b, want)
+	}
+
+	ft, id, _ = p.ReadFieldBegin()
+	utest.EqualNow(t, ft, TypeStruct)
+	utest.EqualNow(t, id, int16(40))
+	utest.IsNilNow(t, p.ReadStructBegin())
+	ft, id, _ = p.ReadFieldBegin()
+	utest.EqualNow(t, ft, TypeString)
+	utest.EqualNow(t, id, int16(-1))
+	s, err := p.ReadString()
+	utest.IsNilNow(t, err)
+	utest.EqualNow(t, s, "nested")
+	ft, _, _ = p.ReadFieldBegin()
+	utest.EqualNow(t, ft, TypeStop)
+	utest.IsNilNow(t, p.ReadStructEnd())
+
+	ft, id, _ = p.ReadFieldBegin()
+	utest.EqualNow(t, ft, TypeMap)
+	utest.EqualNow(t, id, int16(41))
+	kt, vt, n, err := p.ReadMapBegin()
+	utest.IsNilNow(t, err)
+	utest.EqualNow(t, kt, TypeString)
+	utest.EqualNow(t, vt, TypeDouble)
+	utest.EqualNow(t, n, 2)
+	p.ReadString()
+	f, _ := p.ReadDouble()
+	utest.EqualNow(t, f, 1.5)
+	p.ReadString()
+	p.ReadDouble()
+
+	ft, _, _ = p.ReadFieldBegin()
+	utest.EqualNow(t, ft, TypeList)
+	et, n, err := p.ReadListBegin()
+	utest.IsNilNow(t, err)
+	utest.EqualNow(t, et, TypeBool)
+	utest.EqualNow(t, n, 20)
+	for i := 0; i < n; i++ {
+		b, err := p.ReadBool()
+		utest.IsNilNow(t, err)
+		utest.EqualNow(t, b, i%3 == 0)
+	}
+
+	// The rest is skipped field by field.
+	for {
+		ft, id, err = p.ReadFieldBegin()
+		utest.IsNilNow(t, err)
+		if ft == TypeStop {
+			break
+		}
+		if id == 46 {
+			u, err := p.ReadUUID()
+			utest.IsNilNow(t, err)
+			utest.EqualNow(t, u, binary.UUID{1, 2, 3})
+			continue
+		}
+		utest.IsNilNow(t, Skip(p, ft))
+	}
+	utest.IsNilNow(t, p.ReadStructEnd())
+}
+
+func Test_Thrift_Binary(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewBinaryWriter(binary.NewWriter(&buf))
+	writeSample(w)
+	utest.IsNilNow(t, w.Error())
+	readSample(t, NewBinaryReader(binary.NewReader(bytes.NewReader(buf.Bytes()))))
+
+	r := NewBinaryReader(binary.NewReader(bytes.NewReader(buf.Bytes())))
+	r.ReadMessageBegin()
+	utest.IsNilNow(t, Skip(r, TypeStruct))
+}
+
+func Test_Thrift_Compact(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewCompactWriter(binary.NewWriter(&buf))
+	writeSample(w)
+	utest.IsNilNow(t, w.Error())
+	readSample(t, NewCompactReader(binary.NewReader(bytes.NewReader(buf.Bytes()))))
+
+	r := NewCompactReader(binary.NewReader(bytes.NewReader(buf.Bytes())))
+	r.ReadMessageBegin()
+	utest.IsNilNow(t, Skip(r, TypeStruct))
+}
+
+func Test_Thrift_SkipErrors(t *testing.T) {
+	r := NewBinaryReader(binary.NewReader(bytes.NewReader(nil)))
+	utest.EqualNow(t, Skip(r, TypeVoid), ErrInvalidType)
+
+	// Structs nested in a field of type struct, deeper than the limit.
+	deep := bytes.Repeat([]byte{byte(TypeStruct), 0, 1}, maxDepth+1)
+	r = NewBinaryReader(binary.NewReader(bytes.NewReader(deep)))
+	utest.EqualNow(t, Skip(r, TypeStruct), ErrTooDeep)
+}
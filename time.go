@@ -0,0 +1,177 @@
+package binary
+
+import (
+	"errors"
+	"math"
+	"time"
+)
+
+var ErrTimeRange = errors.New("funny/binary: time out of range for encoding")
+
+// NTP timestamps count seconds since 1900 in 32 bits, the counter wraps
+// in 2036. Like RFC 4330 a value with the high bit clear is taken as era 1,
+// so timestamps from 1968 to 2104 are accepted.
+const (
+	ntpEpochOffset = 2208988800
+	ntpEraSeconds  = 1 << 32
+)
+
+func NTPToTime(v uint64) time.Time {
+	sec := int64(v >> 32)
+	if sec < 1<<31 {
+		sec += ntpEraSeconds
+	}
+	nsec := (v & 0xFFFFFFFF) * 1e9 >> 32
+	return time.Unix(sec-ntpEpochOffset, int64(nsec)).UTC()
+}
+
+func TimeToNTP(t time.Time) (uint64, error) {
+	sec := t.Unix() + ntpEpochOffset
+	if sec < 1<<31 || sec >= ntpEraSeconds+1<<31 {
+		return 0, ErrTimeRange
+	}
+	// Rounding the fraction up makes NTPToTime return the same nanosecond.
+	frac := (uint64(t.Nanosecond())<<32 + 1e9 - 1) / 1e9
+	return uint64(sec)%ntpEraSeconds<<32 | frac, nil
+}
+
+// DOS date and time as used by FAT and ZIP, a local time with two second
+// resolution from 1980 to 2107. The date is the high 16 bits.
+
+func DOSToTime(v uint32) time.Time {
+	date, tm := int(v>>16), int(v&0xFFFF)
+	return time.Date(
+		date>>9+1980, time.Month(date>>5&0xF), date&0x1F,
+		tm>>11, tm>>5&0x3F, tm&0x1F*2, 0, time.UTC,
+	)
+}
+
+// TimeToDOS uses the wall clock of t in its own location and drops odd
+// seconds.
+func TimeToDOS(t time.Time) (uint32, error) {
+	year, month, day := t.Date()
+	if year < 1980 || year > 2107 {
+		return 0, ErrTimeRange
+	}
+	date := uint32(year-1980)<<9 | uint32(month)<<5 | uint32(day)
+	tm := uint32(t.Hour())<<11 | uint32(t.Minute())<<5 | uint32(t.Second()/2)
+	return date<<16 | tm, nil
+}
+
+var (
+	minUnixNano = time.Unix(0, math.MinInt64)
+	maxUnixNano = time.Unix(0, math.MaxInt64)
+)
+
+// ReadUnixTime32 reads signed 32-bit seconds, which end in 2038.
+func (r *OrderedReader) ReadUnixTime32() time.Time {
+	return time.Unix(int64(r.ReadInt32()), 0).UTC()
+}
+
+// ReadUnixTimeUint32 reads unsigned 32-bit seconds, which end in 2106.
+func (r *OrderedReader) ReadUnixTimeUint32() time.Time {
+	return time.Unix(int64(r.ReadUint32()), 0).UTC()
+}
+
+func (r *OrderedReader) ReadUnixTime64() time.Time {
+	return time.Unix(r.ReadInt64(), 0).UTC()
+}
+
+func (r *OrderedReader) ReadUnixMilli() time.Time {
+	ms := r.ReadInt64()
+	return time.Unix(ms/1e3, ms%1e3*1e6).UTC()
+}
+
+func (r *OrderedReader) ReadUnixMicro() time.Time {
+	us := r.ReadInt64()
+	return time.Unix(us/1e6, us%1e6*1e3).UTC()
+}
+
+func (r *OrderedReader) ReadUnixNano() time.Time {
+	return time.Unix(0, r.ReadInt64()).UTC()
+}
+
+func (r *OrderedReader) ReadNTPTime() time.Time {
+	return NTPToTime(r.ReadUint64())
+}
+
+func (r *OrderedReader) ReadDOSTime() time.Time {
+	return DOSToTime(r.ReadUint32())
+}
+
+func (r *OrderedReader) ReadDuration() time.Duration {
+	return time.Duration(r.ReadInt64())
+}
+
+// The time writers set ErrTimeRange and write nothing when the time does
+// not fit the encoding.
+
+func (w *OrderedWriter) WriteUnixTime32(t time.Time) {
+	sec := t.Unix()
+	if sec < math.MinInt32 || sec > math.MaxInt32 {
+		w.fail(ErrTimeRange)
+		return
+	}
+	w.WriteInt32(int32(sec))
+}
+
+func (w *OrderedWriter) WriteUnixTimeUint32(t time.Time) {
+	sec := t.Unix()
+	if sec < 0 || sec > math.MaxUint32 {
+		w.fail(ErrTimeRange)
+		return
+	}
+	w.WriteUint32(uint32(sec))
+}
+
+func (w *OrderedWriter) WriteUnixTime64(t time.Time) {
+	w.WriteInt64(t.Unix())
+}
+
+func (w *OrderedWriter) WriteUnixMilli(t time.Time) {
+	sec := t.Unix()
+	if sec < math.MinInt64/1000 || sec > math.MaxInt64/1000-1 {
+		w.fail(ErrTimeRange)
+		return
+	}
+	w.WriteInt64(sec*1e3 + int64(t.Nanosecond())/1e6)
+}
+
+func (w *OrderedWriter) WriteUnixMicro(t time.Time) {
+	sec := t.Unix()
+	if sec < math.MinInt64/1000000 || sec > math.MaxInt64/1000000-1 {
+		w.fail(ErrTimeRange)
+		return
+	}
+	w.WriteInt64(sec*1e6 + int64(t.Nanosecond())/1e3)
+}
+
+func (w *OrderedWriter) WriteUnixNano(t time.Time) {
+	if t.Before(minUnixNano) || t.After(maxUnixNano) {
+		w.fail(ErrTimeRange)
+		return
+	}
+	w.WriteInt64(t.UnixNano())
+}
+
+func (w *OrderedWriter) WriteNTPTime(t time.Time) {
+	v, err := TimeToNTP(t)
+	if err != nil {
+		w.fail(err)
+		return
+	}
+	w.WriteUint64(v)
+}
+
+func (w *OrderedWriter) WriteDOSTime(t time.Time) {
+	v, err := TimeToDOS(t)
+	if err != nil {
+		w.fail(err)
+		return
+	}
+	w.WriteUint32(v)
+}
+
+func (w *OrderedWriter) WriteDuration(d time.Duration) {
+	w.WriteInt64(int64(d))
+}
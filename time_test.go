@@ -0,0 +1,118 @@
+package binary
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/funny/utest"
+)
+
+func Test_Time_NTP(t *testing.T) {
+	utest.EqualNow(t, NTPToTime(2208988800<<32|1<<31), time.Date(1970, 1, 1, 0, 0, 0, 5e8, time.UTC))
+	// Era 1 starts on 2036-02-07T06:28:16Z.
+	utest.EqualNow(t, NTPToTime(0), time.Date(2036, 2, 7, 6, 28, 16, 0, time.UTC))
+
+	v, err := TimeToNTP(time.Date(2036, 2, 7, 6, 28, 17, 0, time.UTC))
+	utest.IsNilNow(t, err)
+	utest.EqualNow(t, v, uint64(1)<<32)
+
+	_, err = TimeToNTP(time.Date(1960, 1, 1, 0, 0, 0, 0, time.UTC))
+	utest.EqualNow(t, err, ErrTimeRange)
+	_, err = TimeToNTP(time.Date(2105, 1, 1, 0, 0, 0, 0, time.UTC))
+	utest.EqualNow(t, err, ErrTimeRange)
+
+	for i := 0; i < 10000; i++ {
+		tm := time.Unix(rand.Int63n(4000000000)-60000000, rand.Int63n(1e9)).UTC()
+		v, err := TimeToNTP(tm)
+		utest.IsNilNow(t, err)
+		utest.EqualNow(t, NTPToTime(v), tm)
+	}
+}
+
+func Test_Time_DOS(t *testing.T) {
+	tm := time.Date(2021, 12, 31, 23, 59, 58, 0, time.UTC)
+	v, err := TimeToDOS(tm)
+	utest.IsNilNow(t, err)
+	utest.EqualNow(t, v, uint32(0x539FBF7D))
+	utest.EqualNow(t, DOSToTime(v), tm)
+
+	v, _ = TimeToDOS(time.Date(1980, 1, 1, 0, 0, 1, 0, time.UTC))
+	utest.EqualNow(t, v, uint32(0x00210000))
+
+	_, err = TimeToDOS(time.Date(1979, 12, 31, 0, 0, 0, 0, time.UTC))
+	utest.EqualNow(t, err, ErrTimeRange)
+	_, err = TimeToDOS(time.Date(2108, 1, 1, 0, 0, 0, 0, time.UTC))
+	utest.EqualNow(t, err, ErrTimeRange)
+}
+
+func Test_Time_Range(t *testing.T) {
+	var buf = Buffer{Data: make([]byte, 8)}
+	w := NewOrderedWriter(&buf, BigEndian)
+
+	w.WriteUnixTime32(time.Date(1901, 12, 13, 20, 45, 52, 0, time.UTC))
+	w.WriteUnixTimeUint32(time.Date(2106, 2, 7, 6, 28, 15, 0, time.UTC))
+	utest.IsNilNow(t, w.Error())
+	w.WriteUnixTime32(time.Date(2038, 1, 19, 3, 14, 8, 0, time.UTC))
+	utest.EqualNow(t, w.Error(), ErrTimeRange)
+	utest.EqualNow(t, buf.WritePos, 8)
+
+	for _, write := range []func(w *OrderedWriter){
+		func(w *OrderedWriter) { w.WriteUnixTimeUint32(time.Date(2106, 2, 7, 6, 28, 16, 0, time.UTC)) },
+		func(w *OrderedWriter) { w.WriteUnixTimeUint32(time.Date(1969, 12, 31, 0, 0, 0, 0, time.UTC)) },
+		func(w *OrderedWriter) { w.WriteUnixNano(time.Date(2263, 1, 1, 0, 0, 0, 0, time.UTC)) },
+		func(w *OrderedWriter) { w.WriteUnixMilli(time.Unix(1<<62, 0)) },
+		func(w *OrderedWriter) { w.WriteUnixMicro(time.Unix(-1<<62, 0)) },
+		func(w *OrderedWriter) { w.WriteNTPTime(time.Unix(0, 0).AddDate(200, 0, 0)) },
+		func(w *OrderedWriter) { w.WriteDOSTime(time.Date(1979, 1, 1, 0, 0, 0, 0, time.UTC)) },
+	} {
+		var buf = Buffer{Data: make([]byte, 8)}
+		w := NewOrderedWriter(&buf, BigEndian)
+		write(w)
+		utest.EqualNow(t, w.Error(), ErrTimeRange)
+		utest.EqualNow(t, buf.WritePos, 0)
+	}
+
+	r := NewOrderedReader(&buf, BigEndian)
+	utest.EqualNow(t, r.ReadUnixTime32(), time.Date(1901, 12, 13, 20, 45, 52, 0, time.UTC))
+	utest.EqualNow(t, r.ReadUnixTimeUint32(), time.Date(2106, 2, 7, 6, 28, 15, 0, time.UTC))
+}
+
+func Test_Time_ReadWrite(t *testing.T) {
+	for _, order := range []ByteOrder{BigEndian, LittleEndian} {
+		ReadWriteTest(t, 10000, func(r *Reader, w *Writer) {
+			sec := rand.Int63n(1<<32) - 1<<31
+			nsec := rand.Int63n(1e9)
+			t32 := time.Unix(sec, 0).UTC()
+			t64 := time.Unix(sec*rand.Int63n(1000), 0).UTC()
+			tms := time.Unix(sec, nsec/1e6*1e6).UTC()
+			tus := time.Unix(sec, nsec/1e3*1e3).UTC()
+			tns := time.Unix(sec, nsec).UTC()
+			tntp := time.Unix(sec+1<<31-61505152, nsec).UTC()
+			tdos := time.Date(1980+rand.Intn(128), time.Month(rand.Intn(12)+1), rand.Intn(28)+1, rand.Intn(24), rand.Intn(60), rand.Intn(30)*2, 0, time.UTC)
+			d := time.Duration(rand.Int63() - rand.Int63())
+
+			ow := NewOrderedWriter(w, order)
+			ow.WriteUnixTime32(t32)
+			ow.WriteUnixTime64(t64)
+			ow.WriteUnixMilli(tms)
+			ow.WriteUnixMicro(tus)
+			ow.WriteUnixNano(tns)
+			ow.WriteNTPTime(tntp)
+			ow.WriteDOSTime(tdos)
+			ow.WriteDuration(d)
+			utest.IsNilNow(t, ow.Error())
+
+			or := NewOrderedReader(r, order)
+			utest.EqualNow(t, or.ReadUnixTime32(), t32)
+			utest.EqualNow(t, or.ReadUnixTime64(), t64)
+			utest.EqualNow(t, or.ReadUnixMilli(), tms)
+			utest.EqualNow(t, or.ReadUnixMicro(), tus)
+			utest.EqualNow(t, or.ReadUnixNano(), tns)
+			utest.EqualNow(t, or.ReadNTPTime(), tntp)
+			utest.EqualNow(t, or.ReadDOSTime(), tdos)
+			utest.EqualNow(t, or.ReadDuration(), d)
+			utest.IsNilNow(t, r.Error())
+		})
+	}
+}
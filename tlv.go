@@ -0,0 +1,302 @@
+package binary
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"io/ioutil"
+	"math"
+)
+
+var (
+	ErrTLVFieldRange = errors.New("funny/binary: value does not fit the TLV field")
+	ErrTLVLength     = errors.New("funny/binary: TLV length shorter than its header")
+)
+
+// TLVField is the encoding of the type or length field of a TLV record.
+type TLVField int
+
+const (
+	TLVUint8 TLVField = iota
+	TLVUint16BE
+	TLVUint16LE
+	TLVUint32BE
+	TLVUint32LE
+	TLVUvarint
+	TLVQuicVarint
+)
+
+func (f TLVField) max() uint64 {
+	switch f {
+	case TLVUint8:
+		return math.MaxUint8
+	case TLVUint16BE, TLVUint16LE:
+		return math.MaxUint16
+	case TLVUint32BE, TLVUint32LE:
+		return math.MaxUint32
+	case TLVQuicVarint:
+		return MaxQuicVarint
+	}
+	return math.MaxUint64
+}
+
+func (f TLVField) size(v uint64) int {
+	switch f {
+	case TLVUint8:
+		return 1
+	case TLVUint16BE, TLVUint16LE:
+		return 2
+	case TLVUint32BE, TLVUint32LE:
+		return 4
+	case TLVQuicVarint:
+		return QuicVarintSize(v)
+	}
+	return UvarintSize(v)
+}
+
+func (f TLVField) read(r BinaryReader) (uint64, error) {
+	var v uint64
+	switch f {
+	case TLVUint8:
+		v = uint64(r.ReadUint8())
+	case TLVUint16BE:
+		v = uint64(r.ReadUint16BE())
+	case TLVUint16LE:
+		v = uint64(r.ReadUint16LE())
+	case TLVUint32BE:
+		v = uint64(r.ReadUint32BE())
+	case TLVUint32LE:
+		v = uint64(r.ReadUint32LE())
+	case TLVQuicVarint:
+		v = r.ReadQuicVarint()
+	default:
+		v = r.ReadUvarint()
+	}
+	return v, r.Error()
+}
+
+func (f TLVField) write(w BinaryWriter, v uint64) {
+	switch f {
+	case TLVUint8:
+		w.WriteUint8(uint8(v))
+	case TLVUint16BE:
+		w.WriteUint16BE(uint16(v))
+	case TLVUint16LE:
+		w.WriteUint16LE(uint16(v))
+	case TLVUint32BE:
+		w.WriteUint32BE(uint32(v))
+	case TLVUint32LE:
+		w.WriteUint32LE(uint32(v))
+	case TLVQuicVarint:
+		w.WriteQuicVarint(v)
+	default:
+		w.WriteUvarint(v)
+	}
+}
+
+// TLVFormat describes the header of a TLV record. LengthFirst puts the
+// length field before the type field, InclusiveLength counts the header in
+// the length like RADIUS does.
+type TLVFormat struct {
+	Type            TLVField
+	Length          TLVField
+	LengthFirst     bool
+	InclusiveLength bool
+}
+
+var (
+	// TLV8 is a one byte type and a one byte length.
+	TLV8 = TLVFormat{Type: TLVUint8, Length: TLVUint8}
+	// TLV16 is a one byte type and a two byte big endian length.
+	TLV16 = TLVFormat{Type: TLVUint8, Length: TLVUint16BE}
+	// TLVVarint is an uvarint type and an uvarint length.
+	TLVVarint = TLVFormat{Type: TLVUvarint, Length: TLVUvarint}
+	// TLVRadius is the attribute layout of RADIUS, RFC 2865.
+	TLVRadius = TLVFormat{Type: TLVUint8, Length: TLVUint8, InclusiveLength: true}
+)
+
+// TLVHandler handles the value of a record, value ends with the record.
+type TLVHandler func(typ uint64, value *Reader) error
+
+// TLVReader iterates the records of a BinaryReader.
+type TLVReader struct {
+	r      BinaryReader
+	format TLVFormat
+	limit  io.LimitedReader
+	value  Reader
+	header Reader
+}
+
+func NewTLVReader(r BinaryReader, format TLVFormat) *TLVReader {
+	t := &TLVReader{r: r, format: format}
+	t.limit.R = tlvSource{r}
+	t.header.R = tlvSource{r}
+	return t
+}
+
+// tlvSource reports a Read without progress as io.ErrUnexpectedEOF, Buffer
+// returns 0, nil at the end of its data and io.ReadFull would spin.
+type tlvSource struct {
+	r BinaryReader
+}
+
+func (s tlvSource) Read(b []byte) (int, error) {
+	n, err := s.r.Read(b)
+	if n == 0 && err == nil && len(b) > 0 {
+		err = io.ErrUnexpectedEOF
+	}
+	return n, err
+}
+
+// Next skips what is left of the previous value and returns the type of
+// the next record and a reader limited to its value. The reader is reused
+// by the following call. Next returns io.EOF when the stream ends between
+// records.
+func (t *TLVReader) Next() (typ uint64, value *Reader, err error) {
+	if t.limit.N > 0 {
+		if _, err := io.CopyN(ioutil.Discard, &t.limit, t.limit.N); err != nil {
+			if err == io.EOF {
+				err = io.ErrUnexpectedEOF
+			}
+			return 0, nil, err
+		}
+	}
+	// Reading past the data of a Buffer panics instead of giving io.EOF, so
+	// its header is read through tlvSource.
+	r := t.r
+	if b, ok := t.r.(*Buffer); ok {
+		if b.ReadPos >= len(b.Data) {
+			return 0, nil, io.EOF
+		}
+		t.header.Reset(t.header.R)
+		r = &t.header
+	}
+	first, second := t.format.Type, t.format.Length
+	if t.format.LengthFirst {
+		first, second = second, first
+	}
+	a, err := first.read(r)
+	if err != nil {
+		return 0, nil, err
+	}
+	b, err := second.read(r)
+	if err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return 0, nil, err
+	}
+	typ, n := a, b
+	if t.format.LengthFirst {
+		typ, n = b, a
+	}
+	if t.format.InclusiveLength {
+		header := uint64(t.format.Type.size(typ) + t.format.Length.size(n))
+		if n < header {
+			return 0, nil, ErrTLVLength
+		}
+		n -= header
+	}
+	if n > math.MaxInt64 {
+		return 0, nil, ErrTLVFieldRange
+	}
+	t.limit.N = int64(n)
+	t.value.Reset(&t.limit)
+	return typ, &t.value, nil
+}
+
+// Dispatch reads records until the end of the stream and passes each one
+// to the handler of its type. Records without a handler go to unknown, or
+// are skipped when it is nil. The first handler error stops the loop.
+func (t *TLVReader) Dispatch(handlers map[uint64]TLVHandler, unknown TLVHandler) error {
+	for {
+		typ, value, err := t.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		h, ok := handlers[typ]
+		if !ok {
+			h = unknown
+		}
+		if h == nil {
+			continue
+		}
+		if err := h(typ, value); err != nil {
+			return err
+		}
+	}
+}
+
+// TLVWriter writes records to a BinaryWriter. Write errors are left in
+// the BinaryWriter, a type or length that does not fit its field is kept
+// as the first error and the record is dropped.
+type TLVWriter struct {
+	w      BinaryWriter
+	format TLVFormat
+	buf    bytes.Buffer
+	err    error
+}
+
+func NewTLVWriter(w BinaryWriter, format TLVFormat) *TLVWriter {
+	return &TLVWriter{w: w, format: format}
+}
+
+func (t *TLVWriter) Error() error {
+	if t.err != nil {
+		return t.err
+	}
+	return t.w.Error()
+}
+
+func (t *TLVWriter) WriteRecord(typ uint64, value []byte) {
+	if typ > t.format.Type.max() {
+		if t.err == nil {
+			t.err = ErrTLVFieldRange
+		}
+		return
+	}
+	n := uint64(len(value))
+	if t.format.InclusiveLength {
+		// A varint length may need another byte to hold itself.
+		base := n + uint64(t.format.Type.size(typ))
+		for n = base; ; {
+			m := base + uint64(t.format.Length.size(n))
+			if m == n {
+				break
+			}
+			n = m
+		}
+	}
+	if n > t.format.Length.max() {
+		if t.err == nil {
+			t.err = ErrTLVFieldRange
+		}
+		return
+	}
+	if t.format.LengthFirst {
+		t.format.Length.write(t.w, n)
+		t.format.Type.write(t.w, typ)
+	} else {
+		t.format.Type.write(t.w, typ)
+		t.format.Length.write(t.w, n)
+	}
+	t.w.WriteBytes(value)
+}
+
+// WriteRecordFunc writes a record whose value is written by fn, buffered
+// to learn its length.
+func (t *TLVWriter) WriteRecordFunc(typ uint64, fn func(w BinaryWriter)) {
+	t.buf.Reset()
+	w := NewWriter(&t.buf)
+	fn(w)
+	if err := w.Error(); err != nil {
+		if t.err == nil {
+			t.err = err
+		}
+		return
+	}
+	t.WriteRecord(typ, t.buf.Bytes())
+}
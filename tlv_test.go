@@ -0,0 +1,146 @@
+package binary
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/funny/utest"
+)
+
+func Test_TLV_Formats(t *testing.T) {
+	for _, c := range []struct {
+		format TLVFormat
+		data   []byte
+	}{
+		{TLV8, []byte{0x01, 0x02, 'h', 'i', 0x02, 0x00}},
+		{TLV16, []byte{0x01, 0x00, 0x02, 'h', 'i', 0x02, 0x00, 0x00}},
+		{TLVVarint, []byte{0x01, 0x02, 'h', 'i', 0x02, 0x00}},
+		{TLVRadius, []byte{0x01, 0x04, 'h', 'i', 0x02, 0x02}},
+		{TLVFormat{Type: TLVUint16LE, Length: TLVUint32LE, LengthFirst: true},
+			[]byte{0x02, 0, 0, 0, 0x01, 0x00, 'h', 'i', 0, 0, 0, 0, 0x02, 0x00}},
+		{TLVFormat{Type: TLVQuicVarint, Length: TLVUint32BE},
+			[]byte{0x01, 0, 0, 0, 0x02, 'h', 'i', 0x02, 0, 0, 0, 0}},
+	} {
+		var buf bytes.Buffer
+		w := NewTLVWriter(NewWriter(&buf), c.format)
+		w.WriteRecord(1, []byte("hi"))
+		w.WriteRecordFunc(2, func(BinaryWriter) {})
+		utest.IsNilNow(t, w.Error())
+		utest.EqualNow(t, buf.Bytes(), c.data)
+
+		r := NewTLVReader(NewReader(&buf), c.format)
+		typ, v, err := r.Next()
+		utest.IsNilNow(t, err)
+		utest.EqualNow(t, typ, uint64(1))
+		utest.EqualNow(t, string(v.ReadBytes(2)), "hi")
+		typ, v, err = r.Next()
+		utest.IsNilNow(t, err)
+		utest.EqualNow(t, typ, uint64(2))
+		v.ReadUint8()
+		utest.EqualNow(t, v.Error(), io.EOF)
+		_, _, err = r.Next()
+		utest.EqualNow(t, err, io.EOF)
+	}
+}
+
+func Test_TLV_InclusiveVarint(t *testing.T) {
+	format := TLVFormat{Type: TLVUint8, Length: TLVUvarint, InclusiveLength: true}
+	for _, n := range []int{0, 125, 126, 127, 128, 300} {
+		var buf bytes.Buffer
+		w := NewTLVWriter(NewWriter(&buf), format)
+		w.WriteRecord(7, make([]byte, n))
+		utest.IsNilNow(t, w.Error())
+		b := buf.Bytes()
+		length, _ := GetUvarint(b[1:])
+		utest.EqualNow(t, length, uint64(len(b)))
+
+		typ, v, err := NewTLVReader(NewReader(&buf), format).Next()
+		utest.IsNilNow(t, err)
+		utest.EqualNow(t, typ, uint64(7))
+		utest.EqualNow(t, len(v.ReadBytes(n)), n)
+		utest.IsNilNow(t, v.Error())
+	}
+}
+
+func Test_TLV_Skip(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewTLVWriter(NewWriter(&buf), TLV16)
+	w.WriteRecord(1, make([]byte, 10000))
+	w.WriteRecordFunc(2, func(w BinaryWriter) { w.WriteUint32BE(42) })
+	w.WriteRecord(3, []byte{1, 2, 3})
+
+	// Unread values are skipped, from any BinaryReader.
+	data := buf.Bytes()
+	for _, src := range []BinaryReader{NewReader(bytes.NewReader(data)), &Buffer{Data: data}} {
+		r := NewTLVReader(src, TLV16)
+		var got []uint64
+		err := r.Dispatch(map[uint64]TLVHandler{
+			2: func(typ uint64, v *Reader) error {
+				utest.EqualNow(t, v.ReadUint32BE(), uint32(42))
+				got = append(got, typ)
+				return v.Error()
+			},
+		}, func(typ uint64, v *Reader) error {
+			got = append(got, typ)
+			return nil
+		})
+		utest.IsNilNow(t, err)
+		utest.EqualNow(t, got, []uint64{1, 2, 3})
+	}
+}
+
+func Test_TLV_Errors(t *testing.T) {
+	stop := errors.New("stop")
+	r := NewTLVReader(NewReader(bytes.NewReader([]byte{1, 0, 2, 3, 0})), TLV8)
+	err := r.Dispatch(map[uint64]TLVHandler{
+		2: func(uint64, *Reader) error { return stop },
+	}, nil)
+	utest.EqualNow(t, err, stop)
+
+	for _, c := range []struct {
+		format TLVFormat
+		data   []byte
+		err    error
+	}{
+		{TLV8, []byte{0x01}, io.ErrUnexpectedEOF},
+		{TLV16, []byte{0x01, 0x00}, io.ErrUnexpectedEOF},
+		{TLV8, []byte{0x01, 0x05, 'a'}, io.ErrUnexpectedEOF},
+		{TLVRadius, []byte{0x01, 0x01}, ErrTLVLength},
+	} {
+		r := NewTLVReader(NewReader(bytes.NewReader(c.data)), c.format)
+		err := r.Dispatch(nil, nil)
+		utest.EqualNow(t, err, c.err)
+	}
+
+	// A truncated header in a Buffer is an error instead of a panic.
+	for _, c := range []struct {
+		format TLVFormat
+		data   []byte
+	}{
+		{TLV16, []byte{0x01, 0x00}},
+		{TLV8, []byte{0x01}},
+		{TLVVarint, []byte{0x81}},
+		{TLVVarint, []byte{0x01, 0x80}},
+	} {
+		_, _, err := NewTLVReader(&Buffer{Data: c.data}, c.format).Next()
+		utest.EqualNow(t, err, io.ErrUnexpectedEOF)
+	}
+
+	// A truncated value in a Buffer ends the sub-reader instead of spinning.
+	_, v, err := NewTLVReader(&Buffer{Data: []byte{0x01, 0x05, 'a'}}, TLV8).Next()
+	utest.IsNilNow(t, err)
+	v.ReadBytes(5)
+	utest.EqualNow(t, v.Error(), io.ErrUnexpectedEOF)
+
+	w := NewTLVWriter(NewWriter(&bytes.Buffer{}), TLV8)
+	w.WriteRecord(256, nil)
+	utest.EqualNow(t, w.Error(), ErrTLVFieldRange)
+	w = NewTLVWriter(NewWriter(&bytes.Buffer{}), TLVRadius)
+	w.WriteRecord(1, make([]byte, 254))
+	utest.EqualNow(t, w.Error(), ErrTLVFieldRange)
+	w = NewTLVWriter(NewWriter(&bytes.Buffer{}), TLV8)
+	w.WriteRecordFunc(1, func(w BinaryWriter) { w.WriteBCD(2, 100) })
+	utest.NotNilNow(t, w.Error())
+}
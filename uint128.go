@@ -0,0 +1,261 @@
+package binary
+
+import (
+	"io"
+	"math/bits"
+	"strconv"
+)
+
+const MaxVarintLen128 = 19
+
+// Uint128 is an unsigned 128-bit integer, arithmetic wraps around.
+type Uint128 struct {
+	Hi, Lo uint64
+}
+
+// Int128 is a two's complement signed 128-bit integer, arithmetic wraps
+// around.
+type Int128 struct {
+	Hi int64
+	Lo uint64
+}
+
+func Uint128From64(v uint64) Uint128 {
+	return Uint128{0, v}
+}
+
+func Int128From64(v int64) Int128 {
+	return Int128{v >> 63, uint64(v)}
+}
+
+func (u Uint128) IsZero() bool {
+	return u.Hi == 0 && u.Lo == 0
+}
+
+func (u Uint128) Cmp(v Uint128) int {
+	switch {
+	case u.Hi < v.Hi || (u.Hi == v.Hi && u.Lo < v.Lo):
+		return -1
+	case u == v:
+		return 0
+	}
+	return 1
+}
+
+func (u Uint128) Add(v Uint128) Uint128 {
+	lo, carry := bits.Add64(u.Lo, v.Lo, 0)
+	hi, _ := bits.Add64(u.Hi, v.Hi, carry)
+	return Uint128{hi, lo}
+}
+
+func (u Uint128) Sub(v Uint128) Uint128 {
+	lo, borrow := bits.Sub64(u.Lo, v.Lo, 0)
+	hi, _ := bits.Sub64(u.Hi, v.Hi, borrow)
+	return Uint128{hi, lo}
+}
+
+func (u Uint128) Mul(v Uint128) Uint128 {
+	hi, lo := bits.Mul64(u.Lo, v.Lo)
+	hi += u.Hi*v.Lo + u.Lo*v.Hi
+	return Uint128{hi, lo}
+}
+
+// QuoRem64 divides u by v, it panics when v is zero.
+func (u Uint128) QuoRem64(v uint64) (q Uint128, r uint64) {
+	q.Hi, r = bits.Div64(0, u.Hi, v)
+	q.Lo, r = bits.Div64(r, u.Lo, v)
+	return
+}
+
+func (u Uint128) And(v Uint128) Uint128 { return Uint128{u.Hi & v.Hi, u.Lo & v.Lo} }
+func (u Uint128) Or(v Uint128) Uint128  { return Uint128{u.Hi | v.Hi, u.Lo | v.Lo} }
+func (u Uint128) Xor(v Uint128) Uint128 { return Uint128{u.Hi ^ v.Hi, u.Lo ^ v.Lo} }
+func (u Uint128) Not() Uint128          { return Uint128{^u.Hi, ^u.Lo} }
+
+func (u Uint128) Lsh(n uint) Uint128 {
+	if n >= 64 {
+		return Uint128{u.Lo << (n - 64), 0}
+	}
+	return Uint128{u.Hi<<n | u.Lo>>(64-n), u.Lo << n}
+}
+
+func (u Uint128) Rsh(n uint) Uint128 {
+	if n >= 64 {
+		return Uint128{0, u.Hi >> (n - 64)}
+	}
+	return Uint128{u.Hi >> n, u.Lo>>n | u.Hi<<(64-n)}
+}
+
+func (u Uint128) String() string {
+	if u.Hi == 0 {
+		return strconv.FormatUint(u.Lo, 10)
+	}
+	var b [40]byte
+	i := len(b)
+	for !u.IsZero() {
+		var r uint64
+		u, r = u.QuoRem64(10)
+		i--
+		b[i] = byte('0' + r)
+	}
+	return string(b[i:])
+}
+
+func (i Int128) Uint128() Uint128 {
+	return Uint128{uint64(i.Hi), i.Lo}
+}
+
+func (u Uint128) Int128() Int128 {
+	return Int128{int64(u.Hi), u.Lo}
+}
+
+func (i Int128) Sign() int {
+	switch {
+	case i.Hi < 0:
+		return -1
+	case i.Hi == 0 && i.Lo == 0:
+		return 0
+	}
+	return 1
+}
+
+func (i Int128) Cmp(j Int128) int {
+	switch {
+	case i.Hi < j.Hi || (i.Hi == j.Hi && i.Lo < j.Lo):
+		return -1
+	case i == j:
+		return 0
+	}
+	return 1
+}
+
+func (i Int128) Neg() Int128         { return Uint128{}.Sub(i.Uint128()).Int128() }
+func (i Int128) Add(j Int128) Int128 { return i.Uint128().Add(j.Uint128()).Int128() }
+func (i Int128) Sub(j Int128) Int128 { return i.Uint128().Sub(j.Uint128()).Int128() }
+func (i Int128) Mul(j Int128) Int128 { return i.Uint128().Mul(j.Uint128()).Int128() }
+
+func (i Int128) String() string {
+	if i.Hi < 0 {
+		return "-" + i.Neg().Uint128().String()
+	}
+	return i.Uint128().String()
+}
+
+func GetUint128LE(b []byte) Uint128 {
+	return Uint128{GetUint64LE(b[8:]), GetUint64LE(b)}
+}
+
+func PutUint128LE(b []byte, v Uint128) {
+	PutUint64LE(b, v.Lo)
+	PutUint64LE(b[8:], v.Hi)
+}
+
+func GetUint128BE(b []byte) Uint128 {
+	return Uint128{GetUint64BE(b), GetUint64BE(b[8:])}
+}
+
+func PutUint128BE(b []byte, v Uint128) {
+	PutUint64BE(b, v.Hi)
+	PutUint64BE(b[8:], v.Lo)
+}
+
+func GetInt128LE(b []byte) Int128    { return GetUint128LE(b).Int128() }
+func PutInt128LE(b []byte, v Int128) { PutUint128LE(b, v.Uint128()) }
+func GetInt128BE(b []byte) Int128    { return GetUint128BE(b).Int128() }
+func PutInt128BE(b []byte, v Int128) { PutUint128BE(b, v.Uint128()) }
+
+// Uvarint128 is the LEB128 encoding of Uvarint extended to 128 bits, it is
+// byte compatible with Uvarint for values below 1<<64.
+
+func Uvarint128Size(x Uint128) int {
+	i := 1
+	for x.Hi != 0 || x.Lo >= 0x80 {
+		x = x.Rsh(7)
+		i++
+	}
+	return i
+}
+
+func Varint128Size(x Int128) int {
+	return Uvarint128Size(zigzag128(x))
+}
+
+func GetUvarint128(b []byte) (Uint128, int) {
+	var v Uint128
+	var s uint
+	for i, c := range b {
+		if i == MaxVarintLen128 {
+			return Uint128{}, -(i + 1)
+		}
+		if c < 0x80 {
+			if i == MaxVarintLen128-1 && c > 3 {
+				return Uint128{}, -(i + 1)
+			}
+			return v.Or(Uint128From64(uint64(c)).Lsh(s)), i + 1
+		}
+		v = v.Or(Uint128From64(uint64(c & 0x7F)).Lsh(s))
+		s += 7
+	}
+	return Uint128{}, 0
+}
+
+func PutUvarint128(b []byte, v Uint128) int {
+	i := 0
+	for v.Hi != 0 || v.Lo >= 0x80 {
+		b[i] = byte(v.Lo) | 0x80
+		v = v.Rsh(7)
+		i++
+	}
+	b[i] = byte(v.Lo)
+	return i + 1
+}
+
+func GetVarint128(b []byte) (Int128, int) {
+	ux, n := GetUvarint128(b)
+	return unzigzag128(ux), n
+}
+
+func PutVarint128(b []byte, v Int128) int {
+	return PutUvarint128(b, zigzag128(v))
+}
+
+func ReadUvarint128(r io.ByteReader) (Uint128, error) {
+	var v Uint128
+	var s uint
+	for i := 0; i < MaxVarintLen128; i++ {
+		c, err := readVarintByte(r, i)
+		if err != nil {
+			return v, err
+		}
+		if c < 0x80 {
+			if i == MaxVarintLen128-1 && c > 3 {
+				return v, ErrVarintOverflow
+			}
+			return v.Or(Uint128From64(uint64(c)).Lsh(s)), nil
+		}
+		v = v.Or(Uint128From64(uint64(c & 0x7F)).Lsh(s))
+		s += 7
+	}
+	return v, ErrVarintOverflow
+}
+
+func ReadVarint128(r io.ByteReader) (Int128, error) {
+	ux, err := ReadUvarint128(r)
+	return unzigzag128(ux), err
+}
+
+func zigzag128(x Int128) Uint128 {
+	ux := x.Uint128().Lsh(1)
+	if x.Hi < 0 {
+		ux = ux.Not()
+	}
+	return ux
+}
+
+func unzigzag128(ux Uint128) Int128 {
+	x := ux.Rsh(1)
+	if ux.Lo&1 != 0 {
+		x = x.Not()
+	}
+	return x.Int128()
+}
@@ -0,0 +1,146 @@
+package binary
+
+import (
+	"bytes"
+	"math/big"
+	"math/rand"
+	"testing"
+
+	"github.com/funny/utest"
+)
+
+func RandUint128() Uint128 {
+	return Uint128{rand.Uint64() >> uint(rand.Intn(64)), rand.Uint64()}
+}
+
+func Uint128Big(u Uint128) *big.Int {
+	b := new(big.Int).SetUint64(u.Hi)
+	return b.Lsh(b, 64).Or(b, new(big.Int).SetUint64(u.Lo))
+}
+
+func Test_Uint128_Arithmetic(t *testing.T) {
+	mod := new(big.Int).Lsh(big.NewInt(1), 128)
+	for i := 0; i < 10000; i++ {
+		u, v := RandUint128(), RandUint128()
+		bu, bv := Uint128Big(u), Uint128Big(v)
+
+		sum := new(big.Int).Add(bu, bv)
+		utest.EqualNow(t, Uint128Big(u.Add(v)).String(), sum.Mod(sum, mod).String())
+
+		diff := new(big.Int).Sub(bu, bv)
+		utest.EqualNow(t, Uint128Big(u.Sub(v)).String(), diff.Mod(diff, mod).String())
+
+		prod := new(big.Int).Mul(bu, bv)
+		utest.EqualNow(t, Uint128Big(u.Mul(v)).String(), prod.Mod(prod, mod).String())
+
+		d := rand.Uint64() | 1
+		q, r := u.QuoRem64(d)
+		bq, br := new(big.Int).QuoRem(bu, new(big.Int).SetUint64(d), new(big.Int))
+		utest.EqualNow(t, Uint128Big(q).String(), bq.String())
+		utest.EqualNow(t, r, br.Uint64())
+
+		n := uint(rand.Intn(128))
+		lsh := new(big.Int).Lsh(bu, n)
+		utest.EqualNow(t, Uint128Big(u.Lsh(n)).String(), lsh.Mod(lsh, mod).String())
+		utest.EqualNow(t, Uint128Big(u.Rsh(n)).String(), new(big.Int).Rsh(bu, n).String())
+
+		utest.EqualNow(t, u.String(), bu.String())
+		utest.EqualNow(t, u.Cmp(v), bu.Cmp(bv))
+	}
+}
+
+func Test_Int128_Arithmetic(t *testing.T) {
+	utest.EqualNow(t, Int128From64(-1), Int128{-1, 1<<64 - 1})
+	utest.EqualNow(t, Int128From64(-5).String(), "-5")
+	utest.EqualNow(t, Int128From64(-5).Neg(), Int128From64(5))
+	utest.EqualNow(t, Int128From64(-5).Sign(), -1)
+	utest.EqualNow(t, Int128{}.Sign(), 0)
+	utest.EqualNow(t, Int128From64(-7).Mul(Int128From64(6)), Int128From64(-42))
+	utest.EqualNow(t, Int128From64(-7).Add(Int128From64(6)), Int128From64(-1))
+	utest.EqualNow(t, Int128From64(-7).Sub(Int128From64(-9)), Int128From64(2))
+	utest.EqualNow(t, Int128From64(-7).Cmp(Int128From64(1)), -1)
+	utest.EqualNow(t, Int128{-1 << 63, 0}.String(), "-170141183460469231731687303715884105728")
+}
+
+func Test_Uint128_PutGet(t *testing.T) {
+	b := make([]byte, 16)
+	u := Uint128{0x0102030405060708, 0x090A0B0C0D0E0F10}
+
+	PutUint128BE(b, u)
+	utest.EqualNow(t, b, []byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16})
+	utest.EqualNow(t, GetUint128BE(b), u)
+
+	PutUint128LE(b, u)
+	utest.EqualNow(t, b, []byte{16, 15, 14, 13, 12, 11, 10, 9, 8, 7, 6, 5, 4, 3, 2, 1})
+	utest.EqualNow(t, GetUint128LE(b), u)
+}
+
+func Test_Uvarint128(t *testing.T) {
+	b := make([]byte, MaxVarintLen128)
+	for i := 0; i < 10000; i++ {
+		u := RandUint128()
+		n := PutUvarint128(b, u)
+		utest.EqualNow(t, n, Uvarint128Size(u))
+
+		u2, n2 := GetUvarint128(b[:n])
+		utest.EqualNow(t, u2, u)
+		utest.EqualNow(t, n2, n)
+
+		u2, err := ReadUvarint128(bytes.NewReader(b[:n]))
+		utest.IsNilNow(t, err)
+		utest.EqualNow(t, u2, u)
+
+		x := u.Int128()
+		n = PutVarint128(b, x)
+		utest.EqualNow(t, n, Varint128Size(x))
+		x2, n2 := GetVarint128(b[:n])
+		utest.EqualNow(t, x2, x)
+		utest.EqualNow(t, n2, n)
+	}
+
+	n := PutUvarint128(b, Uint128{}.Not())
+	utest.EqualNow(t, n, MaxVarintLen128)
+	b[n-1] = 4
+	_, n = GetUvarint128(b)
+	utest.EqualNow(t, n, -MaxVarintLen128)
+
+	v := rand.Uint64()
+	n = PutUvarint(b, v)
+	u, _ := GetUvarint128(b[:n])
+	utest.EqualNow(t, u, Uint128From64(v))
+}
+
+func Test_Uint128_ReadWrite(t *testing.T) {
+	var buf = Buffer{Data: make([]byte, 4*16+2*MaxVarintLen128)}
+	ReadWriteTest(t, 10000, func(r *Reader, w *Writer) {
+		v1 := RandUint128()
+		v2 := RandUint128().Int128()
+
+		w.WriteUint128BE(v1)
+		w.WriteUint128LE(v1)
+		w.WriteInt128BE(v2)
+		w.WriteInt128LE(v2)
+		w.WriteUvarint128(v1)
+		w.WriteVarint128(v2)
+		utest.IsNilNow(t, w.Error())
+
+		utest.EqualNow(t, r.ReadUint128BE(), v1)
+		utest.EqualNow(t, r.ReadUint128LE(), v1)
+		utest.EqualNow(t, r.ReadInt128BE(), v2)
+		utest.EqualNow(t, r.ReadInt128LE(), v2)
+		utest.EqualNow(t, r.ReadUvarint128(), v1)
+		utest.EqualNow(t, r.ReadVarint128(), v2)
+		utest.IsNilNow(t, r.Error())
+
+		buf.ReadPos = 0
+		buf.WritePos = 0
+		buf.WriteUint128BE(v1)
+		buf.WriteInt128LE(v2)
+		buf.WriteUvarint128(v1)
+		buf.WriteVarint128(v2)
+		utest.EqualNow(t, buf.ReadUint128BE(), v1)
+		utest.EqualNow(t, buf.ReadInt128LE(), v2)
+		utest.EqualNow(t, buf.ReadUvarint128(), v1)
+		utest.EqualNow(t, buf.ReadVarint128(), v2)
+	})
+}
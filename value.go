@@ -0,0 +1,351 @@
+package binary
+
+import (
+	"errors"
+	"reflect"
+)
+
+var (
+	ErrValueType     = errors.New("funny/binary: unsupported value type")
+	ErrValueTag      = errors.New("funny/binary: unknown value tag")
+	ErrValueTooLarge = errors.New("funny/binary: value length exceeds limit")
+	ErrValueTooDeep  = errors.New("funny/binary: value nesting exceeds limit")
+	ErrValueMapKey   = errors.New("funny/binary: value map key is not comparable")
+)
+
+// Tags of the self-describing value encoding. An integer tag carries its
+// width in the low three bits, 0 for one byte up to 7 for eight bytes.
+const (
+	ValueNil     = 0x00
+	ValueFalse   = 0x01
+	ValueTrue    = 0x02
+	ValueInt     = 0x10
+	ValueUint    = 0x18
+	ValueFloat32 = 0x20
+	ValueFloat64 = 0x21
+	ValueString  = 0x30
+	ValueBytes   = 0x31
+	ValueList    = 0x40
+	ValueMap     = 0x41
+)
+
+// DefaultValueMaxLen limits strings, bytes and element counts when the
+// maxLen of ReadValue is zero.
+const DefaultValueMaxLen = 16 << 20
+
+const maxValueDepth = 1000
+
+func intWidth(v int64) int {
+	n := 1
+	for v < -1<<(uint(n)*8-1) || v >= 1<<(uint(n)*8-1) {
+		n++
+		if n == 8 {
+			break
+		}
+	}
+	return n
+}
+
+func uintWidth(v uint64) int {
+	n := 1
+	for n < 8 && v >= 1<<(uint(n)*8) {
+		n++
+	}
+	return n
+}
+
+func writeIntN(w BinaryWriter, n int, v int64) {
+	switch n {
+	case 1:
+		w.WriteInt8(int8(v))
+	case 2:
+		w.WriteInt16BE(int16(v))
+	case 3:
+		w.WriteInt24BE(int32(v))
+	case 4:
+		w.WriteInt32BE(int32(v))
+	case 5:
+		w.WriteInt40BE(v)
+	case 6:
+		w.WriteInt48BE(v)
+	case 7:
+		w.WriteInt56BE(v)
+	default:
+		w.WriteInt64BE(v)
+	}
+}
+
+func writeUintN(w BinaryWriter, n int, v uint64) {
+	switch n {
+	case 1:
+		w.WriteUint8(uint8(v))
+	case 2:
+		w.WriteUint16BE(uint16(v))
+	case 3:
+		w.WriteUint24BE(uint32(v))
+	case 4:
+		w.WriteUint32BE(uint32(v))
+	case 5:
+		w.WriteUint40BE(v)
+	case 6:
+		w.WriteUint48BE(v)
+	case 7:
+		w.WriteUint56BE(v)
+	default:
+		w.WriteUint64BE(v)
+	}
+}
+
+// readIntN sign-extends the odd widths, whose readers return the raw bits.
+func readIntN(r BinaryReader, n int) int64 {
+	switch n {
+	case 1:
+		return int64(r.ReadInt8())
+	case 2:
+		return int64(r.ReadInt16BE())
+	case 4:
+		return int64(r.ReadInt32BE())
+	case 8:
+		return r.ReadInt64BE()
+	}
+	shift := uint(64 - n*8)
+	return int64(readUintN(r, n)<<shift) >> shift
+}
+
+func readUintN(r BinaryReader, n int) uint64 {
+	switch n {
+	case 1:
+		return uint64(r.ReadUint8())
+	case 2:
+		return uint64(r.ReadUint16BE())
+	case 3:
+		return uint64(r.ReadUint24BE())
+	case 4:
+		return uint64(r.ReadUint32BE())
+	case 5:
+		return r.ReadUint40BE()
+	case 6:
+		return r.ReadUint48BE()
+	case 7:
+		return r.ReadUint56BE()
+	}
+	return r.ReadUint64BE()
+}
+
+// WriteValue writes v with a tag describing it, so ReadValue needs no
+// schema. Integers take the smallest width that holds them, signed and
+// unsigned Go types keep their signedness. Slices and arrays become lists,
+// maps keep any supported key type and pointers are followed.
+func WriteValue(w BinaryWriter, v interface{}) error {
+	if err := writeValue(w, reflect.ValueOf(v), 0); err != nil {
+		return err
+	}
+	return w.Error()
+}
+
+func writeValue(w BinaryWriter, v reflect.Value, depth int) error {
+	if depth > maxValueDepth {
+		return ErrValueTooDeep
+	}
+	if !v.IsValid() {
+		w.WriteUint8(ValueNil)
+		return nil
+	}
+	switch v.Kind() {
+	case reflect.Bool:
+		if v.Bool() {
+			w.WriteUint8(ValueTrue)
+		} else {
+			w.WriteUint8(ValueFalse)
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n := intWidth(v.Int())
+		w.WriteUint8(ValueInt | uint8(n-1))
+		writeIntN(w, n, v.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		n := uintWidth(v.Uint())
+		w.WriteUint8(ValueUint | uint8(n-1))
+		writeUintN(w, n, v.Uint())
+	case reflect.Float32:
+		w.WriteUint8(ValueFloat32)
+		w.WriteFloat32BE(float32(v.Float()))
+	case reflect.Float64:
+		w.WriteUint8(ValueFloat64)
+		w.WriteFloat64BE(v.Float())
+	case reflect.String:
+		w.WriteUint8(ValueString)
+		w.WriteUvarint(uint64(v.Len()))
+		w.WriteString(v.String())
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			w.WriteUint8(ValueNil)
+			return nil
+		}
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			b := make([]byte, v.Len())
+			reflect.Copy(reflect.ValueOf(b), v)
+			w.WriteUint8(ValueBytes)
+			w.WriteUvarint(uint64(len(b)))
+			w.WriteBytes(b)
+			return nil
+		}
+		w.WriteUint8(ValueList)
+		w.WriteUvarint(uint64(v.Len()))
+		for i := 0; i < v.Len(); i++ {
+			if err := writeValue(w, v.Index(i), depth+1); err != nil {
+				return err
+			}
+		}
+	case reflect.Map:
+		if v.IsNil() {
+			w.WriteUint8(ValueNil)
+			return nil
+		}
+		w.WriteUint8(ValueMap)
+		w.WriteUvarint(uint64(v.Len()))
+		iter := v.MapRange()
+		for iter.Next() {
+			if err := writeValue(w, iter.Key(), depth+1); err != nil {
+				return err
+			}
+			if err := writeValue(w, iter.Value(), depth+1); err != nil {
+				return err
+			}
+		}
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			w.WriteUint8(ValueNil)
+			return nil
+		}
+		return writeValue(w, v.Elem(), depth+1)
+	default:
+		return ErrValueType
+	}
+	return nil
+}
+
+// ReadValue reads a value written by WriteValue. Integers come back as
+// int64 or uint64, floats as float32 or float64, lists as []interface{}
+// and maps as map[string]interface{} when all keys are strings, otherwise
+// as map[interface{}]interface{}. A zero maxLen means DefaultValueMaxLen.
+func ReadValue(r BinaryReader, maxLen int) (interface{}, error) {
+	if maxLen <= 0 {
+		maxLen = DefaultValueMaxLen
+	}
+	return readValue(r, maxLen, 0)
+}
+
+func readValueLen(r BinaryReader, maxLen int) (int, error) {
+	n := r.ReadUvarint()
+	if err := r.Error(); err != nil {
+		return 0, err
+	}
+	if n > uint64(maxLen) {
+		return 0, ErrValueTooLarge
+	}
+	return int(n), nil
+}
+
+func readValue(r BinaryReader, maxLen, depth int) (interface{}, error) {
+	if depth > maxValueDepth {
+		return nil, ErrValueTooDeep
+	}
+	tag := r.ReadUint8()
+	if err := r.Error(); err != nil {
+		return nil, err
+	}
+	var v interface{}
+	switch {
+	case tag == ValueNil:
+		return nil, nil
+	case tag == ValueFalse:
+		return false, nil
+	case tag == ValueTrue:
+		return true, nil
+	case tag&^7 == ValueInt:
+		v = readIntN(r, int(tag&7)+1)
+	case tag&^7 == ValueUint:
+		v = readUintN(r, int(tag&7)+1)
+	case tag == ValueFloat32:
+		v = r.ReadFloat32BE()
+	case tag == ValueFloat64:
+		v = r.ReadFloat64BE()
+	case tag == ValueString, tag == ValueBytes:
+		n, err := readValueLen(r, maxLen)
+		if err != nil {
+			return nil, err
+		}
+		if tag == ValueString {
+			v = r.ReadString(n)
+		} else {
+			v = r.ReadBytes(n)
+		}
+	case tag == ValueList:
+		n, err := readValueLen(r, maxLen)
+		if err != nil {
+			return nil, err
+		}
+		list := make([]interface{}, 0, valueCap(n))
+		for i := 0; i < n; i++ {
+			item, err := readValue(r, maxLen, depth+1)
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, item)
+		}
+		return list, nil
+	case tag == ValueMap:
+		n, err := readValueLen(r, maxLen)
+		if err != nil {
+			return nil, err
+		}
+		return readValueMap(r, n, maxLen, depth)
+	default:
+		return nil, ErrValueTag
+	}
+	return v, r.Error()
+}
+
+// valueCap keeps a forged element count from allocating up front.
+func valueCap(n int) int {
+	if n > 1024 {
+		return 1024
+	}
+	return n
+}
+
+func readValueMap(r BinaryReader, n, maxLen, depth int) (interface{}, error) {
+	keys := make([]interface{}, 0, valueCap(n))
+	values := make([]interface{}, 0, valueCap(n))
+	strKeys := true
+	for i := 0; i < n; i++ {
+		k, err := readValue(r, maxLen, depth+1)
+		if err != nil {
+			return nil, err
+		}
+		if k != nil && !reflect.TypeOf(k).Comparable() {
+			return nil, ErrValueMapKey
+		}
+		if _, ok := k.(string); !ok {
+			strKeys = false
+		}
+		v, err := readValue(r, maxLen, depth+1)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, k)
+		values = append(values, v)
+	}
+	if strKeys {
+		m := make(map[string]interface{}, len(keys))
+		for i, k := range keys {
+			m[k.(string)] = values[i]
+		}
+		return m, nil
+	}
+	m := make(map[interface{}]interface{}, len(keys))
+	for i, k := range keys {
+		m[k] = values[i]
+	}
+	return m, nil
+}
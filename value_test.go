@@ -0,0 +1,96 @@
+package binary
+
+import (
+	"bytes"
+	"math"
+	"testing"
+
+	"github.com/funny/utest"
+)
+
+func encodeValue(t *testing.T, v interface{}) []byte {
+	var buf bytes.Buffer
+	utest.IsNilNow(t, WriteValue(NewWriter(&buf), v))
+	return buf.Bytes()
+}
+
+func decodeValue(t *testing.T, data []byte) interface{} {
+	v, err := ReadValue(NewReader(bytes.NewReader(data)), 0)
+	utest.IsNilNow(t, err)
+	return v
+}
+
+func Test_Value_Scalars(t *testing.T) {
+	for _, c := range []struct {
+		in   interface{}
+		data []byte
+		out  interface{}
+	}{
+		{nil, []byte{0x00}, nil},
+		{false, []byte{0x01}, false},
+		{true, []byte{0x02}, true},
+		{int8(-1), []byte{0x10, 0xff}, int64(-1)},
+		{127, []byte{0x10, 0x7f}, int64(127)},
+		{128, []byte{0x11, 0x00, 0x80}, int64(128)},
+		{-32769, []byte{0x12, 0xff, 0x7f, 0xff}, int64(-32769)},
+		{int64(1) << 40, []byte{0x15, 0x01, 0, 0, 0, 0, 0}, int64(1) << 40},
+		{int64(math.MinInt64), []byte{0x17, 0x80, 0, 0, 0, 0, 0, 0, 0}, int64(math.MinInt64)},
+		{uint(255), []byte{0x18, 0xff}, uint64(255)},
+		{uint32(0x10000), []byte{0x1a, 0x01, 0x00, 0x00}, uint64(0x10000)},
+		{uint64(math.MaxUint64), []byte{0x1f, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}, uint64(math.MaxUint64)},
+		{float32(1.5), []byte{0x20, 0x3f, 0xc0, 0x00, 0x00}, float32(1.5)},
+		{2.0, []byte{0x21, 0x40, 0, 0, 0, 0, 0, 0, 0}, 2.0},
+		{"hi", []byte{0x30, 0x02, 'h', 'i'}, "hi"},
+		{[]byte{1, 2}, []byte{0x31, 0x02, 1, 2}, []byte{1, 2}},
+		{[2]byte{1, 2}, []byte{0x31, 0x02, 1, 2}, []byte{1, 2}},
+	} {
+		data := encodeValue(t, c.in)
+		utest.EqualNow(t, data, c.data)
+		utest.EqualNow(t, decodeValue(t, data), c.out)
+	}
+}
+
+func Test_Value_Containers(t *testing.T) {
+	s := "x"
+	data := encodeValue(t, []interface{}{1, "a", nil, &s, []int{2, 3}})
+	utest.EqualNow(t, data, []byte{
+		0x40, 0x05,
+		0x10, 0x01,
+		0x30, 0x01, 'a',
+		0x00,
+		0x30, 0x01, 'x',
+		0x40, 0x02, 0x10, 0x02, 0x10, 0x03,
+	})
+	utest.EqualNow(t, decodeValue(t, data), []interface{}{
+		int64(1), "a", nil, "x", []interface{}{int64(2), int64(3)},
+	})
+
+	data = encodeValue(t, map[string]interface{}{"k": true})
+	utest.EqualNow(t, data, []byte{0x41, 0x01, 0x30, 0x01, 'k', 0x02})
+	utest.EqualNow(t, decodeValue(t, data), map[string]interface{}{"k": true})
+
+	data = encodeValue(t, map[int]string{7: "v"})
+	utest.EqualNow(t, decodeValue(t, data), map[interface{}]interface{}{int64(7): "v"})
+}
+
+func Test_Value_Errors(t *testing.T) {
+	var buf bytes.Buffer
+	utest.EqualNow(t, WriteValue(NewWriter(&buf), struct{}{}), ErrValueType)
+	utest.EqualNow(t, WriteValue(NewWriter(&buf), []interface{}{make(chan int)}), ErrValueType)
+	var cycle interface{}
+	cycle = &cycle
+	utest.EqualNow(t, WriteValue(NewWriter(&buf), cycle), ErrValueTooDeep)
+
+	read := func(data []byte, maxLen int) error {
+		_, err := ReadValue(NewReader(bytes.NewReader(data)), maxLen)
+		return err
+	}
+	utest.EqualNow(t, read([]byte{0x03}, 0), ErrValueTag)
+	utest.EqualNow(t, read([]byte{0x30, 0x05, 'a'}, 4), ErrValueTooLarge)
+	utest.NotNilNow(t, read([]byte{0x30, 0x05, 'a'}, 0))
+	utest.NotNilNow(t, read([]byte{0x13, 0x00}, 0))
+	utest.EqualNow(t, read([]byte{0x41, 0x01, 0x40, 0x00, 0x00}, 0), ErrValueMapKey)
+
+	deep := bytes.Repeat([]byte{0x40, 0x01}, maxValueDepth+2)
+	utest.EqualNow(t, read(append(deep, 0x00), 0), ErrValueTooDeep)
+}
@@ -0,0 +1,356 @@
+package binary
+
+import (
+	"errors"
+	"io"
+)
+
+// Varint formats used by other protocols, next to the LEB128 Uvarint and
+// zig-zag Varint of encoding/binary. Get functions follow Uvarint: n == 0
+// means the buffer is too small, n < 0 means the value overflows 64 bits
+// and -n is the number of bytes read.
+
+const (
+	MaxQuicVarint      = 1<<62 - 1
+	MaxQuicVarintLen   = 8
+	MaxSQLiteVarintLen = 9
+	MaxVLQLen64        = 10
+	MaxGitVarintLen    = 10
+)
+
+var (
+	ErrVarintOverflow     = errors.New("funny/binary: varint overflows a 64-bit integer")
+	ErrVarintTruncated    = errors.New("funny/binary: varint truncated")
+	ErrVarintNonCanonical = errors.New("funny/binary: varint not in canonical form")
+	ErrQuicVarintTooLarge = errors.New("funny/binary: value too large for a QUIC varint")
+)
+
+// varintError turns the n <= 0 result of a Get function into an error.
+func varintError(n int) error {
+	if n == 0 {
+		return ErrVarintTruncated
+	}
+	return ErrVarintOverflow
+}
+
+func readVarintByte(r io.ByteReader, i int) (byte, error) {
+	c, err := r.ReadByte()
+	if err == io.EOF && i > 0 {
+		err = io.ErrUnexpectedEOF
+	}
+	return c, err
+}
+
+// GetUvarintStrict decodes a LEB128 Uvarint like GetUvarint but reports
+// failures as ErrVarintTruncated or ErrVarintOverflow. When canonical is set
+// over-long encodings, which end with a zero group, fail with
+// ErrVarintNonCanonical so every value has exactly one accepted form.
+func GetUvarintStrict(b []byte, canonical bool) (uint64, int, error) {
+	var v uint64
+	var s uint
+	for i, c := range b {
+		if i == MaxVarintLen64 {
+			return 0, 0, ErrVarintOverflow
+		}
+		if c < 0x80 {
+			if i == MaxVarintLen64-1 && c > 1 {
+				return 0, 0, ErrVarintOverflow
+			}
+			if canonical && c == 0 && i > 0 {
+				return 0, 0, ErrVarintNonCanonical
+			}
+			return v | uint64(c)<<s, i + 1, nil
+		}
+		v |= uint64(c&0x7F) << s
+		s += 7
+	}
+	return 0, 0, ErrVarintTruncated
+}
+
+func GetVarintStrict(b []byte, canonical bool) (int64, int, error) {
+	ux, n, err := GetUvarintStrict(b, canonical)
+	return unzigzag(ux), n, err
+}
+
+// ReadUvarintStrict is the io.ByteReader version of GetUvarintStrict,
+// a stream that ends inside a varint gives io.ErrUnexpectedEOF.
+func ReadUvarintStrict(r io.ByteReader, canonical bool) (uint64, error) {
+	var v uint64
+	var s uint
+	for i := 0; i < MaxVarintLen64; i++ {
+		c, err := readVarintByte(r, i)
+		if err != nil {
+			return v, err
+		}
+		if c < 0x80 {
+			if i == MaxVarintLen64-1 && c > 1 {
+				return v, ErrVarintOverflow
+			}
+			if canonical && c == 0 && i > 0 {
+				return v, ErrVarintNonCanonical
+			}
+			return v | uint64(c)<<s, nil
+		}
+		v |= uint64(c&0x7F) << s
+		s += 7
+	}
+	return v, ErrVarintOverflow
+}
+
+func ReadVarintStrict(r io.ByteReader, canonical bool) (int64, error) {
+	ux, err := ReadUvarintStrict(r, canonical)
+	return unzigzag(ux), err
+}
+
+func unzigzag(ux uint64) int64 {
+	x := int64(ux >> 1)
+	if ux&1 != 0 {
+		x = ^x
+	}
+	return x
+}
+
+// QUIC variable-length integer (RFC 9000 section 16), the two high bits of
+// the first byte give the length as 1, 2, 4 or 8 bytes.
+
+func QuicVarintSize(x uint64) int {
+	switch {
+	case x <= 0x3F:
+		return 1
+	case x <= 0x3FFF:
+		return 2
+	case x <= 0x3FFFFFFF:
+		return 4
+	}
+	return 8
+}
+
+func GetQuicVarint(b []byte) (uint64, int) {
+	if len(b) == 0 {
+		return 0, 0
+	}
+	n := 1 << (b[0] >> 6)
+	if len(b) < n {
+		return 0, 0
+	}
+	v := uint64(b[0] & 0x3F)
+	for i := 1; i < n; i++ {
+		v = v<<8 | uint64(b[i])
+	}
+	return v, n
+}
+
+// PutQuicVarint panics with ErrQuicVarintTooLarge when v > MaxQuicVarint.
+func PutQuicVarint(b []byte, v uint64) int {
+	if v > MaxQuicVarint {
+		panic(ErrQuicVarintTooLarge)
+	}
+	n := QuicVarintSize(v)
+	switch n {
+	case 1:
+		b[0] = byte(v)
+	case 2:
+		PutUint16BE(b, uint16(v)|0x4000)
+	case 4:
+		PutUint32BE(b, uint32(v)|0x80000000)
+	default:
+		PutUint64BE(b, v|0xC000000000000000)
+	}
+	return n
+}
+
+func ReadQuicVarint(r io.ByteReader) (uint64, error) {
+	c, err := r.ReadByte()
+	if err != nil {
+		return 0, err
+	}
+	n := 1 << (c >> 6)
+	v := uint64(c & 0x3F)
+	for i := 1; i < n; i++ {
+		if c, err = readVarintByte(r, i); err != nil {
+			return v, err
+		}
+		v = v<<8 | uint64(c)
+	}
+	return v, nil
+}
+
+// SQLite varint, big-endian base-128 with at most 9 bytes, the 9th byte
+// carries a full 8 bits.
+
+func SQLiteVarintSize(x uint64) int {
+	if x>>56 != 0 {
+		return 9
+	}
+	i := 1
+	for x >= 0x80 {
+		x >>= 7
+		i++
+	}
+	return i
+}
+
+func GetSQLiteVarint(b []byte) (uint64, int) {
+	var v uint64
+	for i := 0; i < len(b); i++ {
+		if i == 8 {
+			return v<<8 | uint64(b[i]), 9
+		}
+		v = v<<7 | uint64(b[i]&0x7F)
+		if b[i] < 0x80 {
+			return v, i + 1
+		}
+	}
+	return 0, 0
+}
+
+func PutSQLiteVarint(b []byte, v uint64) int {
+	n := SQLiteVarintSize(v)
+	i := n - 1
+	if n == 9 {
+		b[8] = byte(v)
+		v >>= 8
+		i--
+		b[i] = byte(v&0x7F) | 0x80
+	} else {
+		b[i] = byte(v & 0x7F)
+	}
+	for i > 0 {
+		v >>= 7
+		i--
+		b[i] = byte(v&0x7F) | 0x80
+	}
+	return n
+}
+
+func ReadSQLiteVarint(r io.ByteReader) (uint64, error) {
+	var v uint64
+	for i := 0; ; i++ {
+		c, err := readVarintByte(r, i)
+		if err != nil {
+			return v, err
+		}
+		if i == 8 {
+			return v<<8 | uint64(c), nil
+		}
+		v = v<<7 | uint64(c&0x7F)
+		if c < 0x80 {
+			return v, nil
+		}
+	}
+}
+
+// VLQ is the big-endian base-128 encoding of MIDI files, every byte but the
+// last has the high bit set.
+
+func VLQSize(x uint64) int {
+	i := 1
+	for x >= 0x80 {
+		x >>= 7
+		i++
+	}
+	return i
+}
+
+func GetVLQ(b []byte) (uint64, int) {
+	var v uint64
+	for i, c := range b {
+		if v>>57 != 0 {
+			return 0, -(i + 1)
+		}
+		v = v<<7 | uint64(c&0x7F)
+		if c < 0x80 {
+			return v, i + 1
+		}
+	}
+	return 0, 0
+}
+
+func PutVLQ(b []byte, v uint64) int {
+	n := VLQSize(v)
+	b[n-1] = byte(v & 0x7F)
+	for i := n - 2; i >= 0; i-- {
+		v >>= 7
+		b[i] = byte(v&0x7F) | 0x80
+	}
+	return n
+}
+
+func ReadVLQ(r io.ByteReader) (uint64, error) {
+	var v uint64
+	for i := 0; ; i++ {
+		c, err := readVarintByte(r, i)
+		if err != nil {
+			return v, err
+		}
+		if v>>57 != 0 {
+			return v, ErrVarintOverflow
+		}
+		v = v<<7 | uint64(c&0x7F)
+		if c < 0x80 {
+			return v, nil
+		}
+	}
+}
+
+// GitVarint is the offset encoding of Git packfile OFS_DELTA entries, a
+// big-endian base-128 where each continuation adds one so that every value
+// has a single encoding.
+
+func GitVarintSize(x uint64) int {
+	i := 1
+	for x >>= 7; x != 0; x >>= 7 {
+		x--
+		i++
+	}
+	return i
+}
+
+func GetGitVarint(b []byte) (uint64, int) {
+	var v uint64
+	for i, c := range b {
+		if i > 0 {
+			if v >= 1<<57-1 {
+				return 0, -(i + 1)
+			}
+			v++
+		}
+		v = v<<7 | uint64(c&0x7F)
+		if c < 0x80 {
+			return v, i + 1
+		}
+	}
+	return 0, 0
+}
+
+func PutGitVarint(b []byte, v uint64) int {
+	n := GitVarintSize(v)
+	i := n - 1
+	b[i] = byte(v & 0x7F)
+	for v >>= 7; v != 0; v >>= 7 {
+		v--
+		i--
+		b[i] = byte(v&0x7F) | 0x80
+	}
+	return n
+}
+
+func ReadGitVarint(r io.ByteReader) (uint64, error) {
+	var v uint64
+	for i := 0; ; i++ {
+		c, err := readVarintByte(r, i)
+		if err != nil {
+			return v, err
+		}
+		if i > 0 {
+			if v >= 1<<57-1 {
+				return v, ErrVarintOverflow
+			}
+			v++
+		}
+		v = v<<7 | uint64(c&0x7F)
+		if c < 0x80 {
+			return v, nil
+		}
+	}
+}
@@ -0,0 +1,226 @@
+package binary
+
+import (
+	"bytes"
+	"io"
+	"math/rand"
+	"testing"
+
+	"github.com/funny/utest"
+)
+
+type VarintCodec struct {
+	Size func(uint64) int
+	Get  func([]byte) (uint64, int)
+	Put  func([]byte, uint64) int
+	Read func(io.ByteReader) (uint64, error)
+	Max  uint64
+}
+
+var VarintCodecs = map[string]VarintCodec{
+	"Quic":   {QuicVarintSize, GetQuicVarint, PutQuicVarint, ReadQuicVarint, MaxQuicVarint},
+	"SQLite": {SQLiteVarintSize, GetSQLiteVarint, PutSQLiteVarint, ReadSQLiteVarint, 1<<64 - 1},
+	"VLQ":    {VLQSize, GetVLQ, PutVLQ, ReadVLQ, 1<<64 - 1},
+	"Git":    {GitVarintSize, GetGitVarint, PutGitVarint, ReadGitVarint, 1<<64 - 1},
+}
+
+func VarintVectorTest(t *testing.T, codec VarintCodec, v uint64, b []byte) {
+	utest.EqualNow(t, codec.Size(v), len(b))
+
+	c := make([]byte, MaxVarintLen64)
+	utest.EqualNow(t, codec.Put(c, v), len(b))
+	utest.EqualNow(t, c[:len(b)], b)
+
+	v2, n := codec.Get(b)
+	utest.EqualNow(t, v2, v)
+	utest.EqualNow(t, n, len(b))
+
+	v2, n = codec.Get(b[:len(b)-1])
+	utest.EqualNow(t, n, 0)
+
+	v2, err := codec.Read(bytes.NewReader(b))
+	utest.IsNilNow(t, err)
+	utest.EqualNow(t, v2, v)
+}
+
+func Test_QuicVarint_Vectors(t *testing.T) {
+	codec := VarintCodecs["Quic"]
+	VarintVectorTest(t, codec, 151288809941952652, []byte{0xc2, 0x19, 0x7c, 0x5e, 0xff, 0x14, 0xe8, 0x8c})
+	VarintVectorTest(t, codec, 494878333, []byte{0x9d, 0x7f, 0x3e, 0x7d})
+	VarintVectorTest(t, codec, 15293, []byte{0x7b, 0xbd})
+	VarintVectorTest(t, codec, 37, []byte{0x25})
+
+	v, n := GetQuicVarint([]byte{0x40, 0x25})
+	utest.EqualNow(t, v, 37)
+	utest.EqualNow(t, n, 2)
+}
+
+func Test_QuicVarint_WriterRange(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewWriter(&buf)
+	w.WriteQuicVarint(MaxQuicVarint + 1)
+	w.WriteUint8(1)
+	utest.EqualNow(t, w.Error(), ErrQuicVarintTooLarge)
+	utest.EqualNow(t, buf.Len(), 0)
+}
+
+func Test_SQLiteVarint_Vectors(t *testing.T) {
+	codec := VarintCodecs["SQLite"]
+	VarintVectorTest(t, codec, 0x7F, []byte{0x7F})
+	VarintVectorTest(t, codec, 0x80, []byte{0x81, 0x00})
+	VarintVectorTest(t, codec, 0x3FFF, []byte{0xFF, 0x7F})
+	VarintVectorTest(t, codec, 1<<56-1, []byte{0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0x7F})
+	VarintVectorTest(t, codec, 1<<56, []byte{0x80, 0xC0, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x00})
+	VarintVectorTest(t, codec, 1<<64-1, []byte{0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF})
+}
+
+func Test_VLQ_Vectors(t *testing.T) {
+	codec := VarintCodecs["VLQ"]
+	VarintVectorTest(t, codec, 0x00, []byte{0x00})
+	VarintVectorTest(t, codec, 0x7F, []byte{0x7F})
+	VarintVectorTest(t, codec, 0x80, []byte{0x81, 0x00})
+	VarintVectorTest(t, codec, 0x2000, []byte{0xC0, 0x00})
+	VarintVectorTest(t, codec, 0x4000, []byte{0x81, 0x80, 0x00})
+	VarintVectorTest(t, codec, 0x0FFFFFFF, []byte{0xFF, 0xFF, 0xFF, 0x7F})
+
+	_, n := GetVLQ([]byte{0x82, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x00})
+	utest.EqualNow(t, n, -10)
+	_, err := ReadVLQ(bytes.NewReader([]byte{0x82, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x00}))
+	utest.EqualNow(t, err, ErrVarintOverflow)
+}
+
+func Test_GitVarint_Vectors(t *testing.T) {
+	codec := VarintCodecs["Git"]
+	VarintVectorTest(t, codec, 0x00, []byte{0x00})
+	VarintVectorTest(t, codec, 0x7F, []byte{0x7F})
+	VarintVectorTest(t, codec, 0x80, []byte{0x80, 0x00})
+	VarintVectorTest(t, codec, 0x407F, []byte{0xFF, 0x7F})
+	VarintVectorTest(t, codec, 0x4080, []byte{0x80, 0x80, 0x00})
+}
+
+func Test_Varint_Formats(t *testing.T) {
+	b := make([]byte, MaxVarintLen64)
+	for name, codec := range VarintCodecs {
+		for i := 0; i < 10000; i++ {
+			v := rand.Uint64() >> uint(rand.Intn(64))
+			if v > codec.Max {
+				v = codec.Max
+			}
+			n := codec.Put(b, v)
+			utest.EqualNow(t, n, codec.Size(v))
+
+			v2, n2 := codec.Get(b[:n])
+			utest.EqualNow(t, n2, n)
+			if v2 != v {
+				t.Fatalf("%s: %d != %d", name, v2, v)
+			}
+
+			v2, err := codec.Read(bytes.NewReader(b[:n]))
+			utest.IsNilNow(t, err)
+			utest.EqualNow(t, v2, v)
+
+			if n > 1 {
+				_, err = codec.Read(bytes.NewReader(b[:n-1]))
+				utest.EqualNow(t, err, io.ErrUnexpectedEOF)
+			}
+		}
+	}
+}
+
+func Test_Varint_ReadWrite(t *testing.T) {
+	var buf = Buffer{Data: make([]byte, 4*MaxVarintLen64)}
+	ReadWriteTest(t, 10000, func(r *Reader, w *Writer) {
+		v1 := rand.Uint64() >> 2
+		v2 := rand.Uint64()
+		v3 := rand.Uint64()
+		v4 := rand.Uint64()
+
+		w.WriteQuicVarint(v1)
+		w.WriteSQLiteVarint(v2)
+		w.WriteVLQ(v3)
+		w.WriteGitVarint(v4)
+		utest.IsNilNow(t, w.Error())
+
+		utest.EqualNow(t, r.ReadQuicVarint(), v1)
+		utest.EqualNow(t, r.ReadSQLiteVarint(), v2)
+		utest.EqualNow(t, r.ReadVLQ(), v3)
+		utest.EqualNow(t, r.ReadGitVarint(), v4)
+		utest.IsNilNow(t, r.Error())
+
+		buf.ReadPos = 0
+		buf.WritePos = 0
+		buf.WriteQuicVarint(v1)
+		buf.WriteSQLiteVarint(v2)
+		buf.WriteVLQ(v3)
+		buf.WriteGitVarint(v4)
+		utest.EqualNow(t, buf.ReadQuicVarint(), v1)
+		utest.EqualNow(t, buf.ReadSQLiteVarint(), v2)
+		utest.EqualNow(t, buf.ReadVLQ(), v3)
+		utest.EqualNow(t, buf.ReadGitVarint(), v4)
+	})
+}
+
+func Test_Varint_Strict(t *testing.T) {
+	overflow := []byte{0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0x02}
+	tooLong := []byte{0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x00}
+	overLong := []byte{0x81, 0x80, 0x00}
+
+	for _, canonical := range []bool{false, true} {
+		_, _, err := GetUvarintStrict(overflow, canonical)
+		utest.EqualNow(t, err, ErrVarintOverflow)
+		_, err = ReadUvarintStrict(bytes.NewReader(overflow), canonical)
+		utest.EqualNow(t, err, ErrVarintOverflow)
+
+		_, _, err = GetUvarintStrict(tooLong, canonical)
+		utest.EqualNow(t, err, ErrVarintOverflow)
+		_, err = ReadUvarintStrict(bytes.NewReader(tooLong), canonical)
+		utest.EqualNow(t, err, ErrVarintOverflow)
+
+		_, _, err = GetUvarintStrict(overLong[:2], canonical)
+		utest.EqualNow(t, err, ErrVarintTruncated)
+		_, err = ReadUvarintStrict(bytes.NewReader(overLong[:2]), canonical)
+		utest.EqualNow(t, err, io.ErrUnexpectedEOF)
+	}
+
+	v, n, err := GetUvarintStrict(overLong, false)
+	utest.IsNilNow(t, err)
+	utest.EqualNow(t, v, 1)
+	utest.EqualNow(t, n, 3)
+
+	_, _, err = GetUvarintStrict(overLong, true)
+	utest.EqualNow(t, err, ErrVarintNonCanonical)
+	_, err = ReadUvarintStrict(bytes.NewReader(overLong), true)
+	utest.EqualNow(t, err, ErrVarintNonCanonical)
+
+	_, _, err = GetUvarintStrict([]byte{0x00}, true)
+	utest.IsNilNow(t, err)
+
+	b := make([]byte, MaxVarintLen64)
+	for i := 0; i < 10000; i++ {
+		x := rand.Int63() - rand.Int63()
+		n := PutVarint(b, x)
+
+		x2, n2, err := GetVarintStrict(b, true)
+		utest.IsNilNow(t, err)
+		utest.EqualNow(t, x2, x)
+		utest.EqualNow(t, n2, n)
+
+		x2, err = ReadVarintStrict(bytes.NewReader(b[:n]), true)
+		utest.IsNilNow(t, err)
+		utest.EqualNow(t, x2, x)
+	}
+}
+
+func Test_Varint_BufferOverflow(t *testing.T) {
+	defer func() {
+		utest.EqualNow(t, recover(), ErrVarintOverflow)
+	}()
+	buf := Buffer{Data: []byte{0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0x01}}
+	buf.ReadUvarint()
+}
+
+func Test_Varint_ReaderOverflow(t *testing.T) {
+	r := NewReader(bytes.NewReader([]byte{0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0x01}))
+	r.ReadUvarint()
+	utest.EqualNow(t, r.Error(), ErrVarintOverflow)
+}
@@ -2,12 +2,18 @@ package binary
 
 import (
 	"io"
+	"net"
 )
 
 type Writer struct {
 	W   io.Writer
-	wb  [MaxVarintLen64]byte
+	wb  [MaxVarintLen128]byte
 	err error
+
+	// failed is the first value the Writer refused to encode, like a BCD
+	// overflow. Unlike err it is not replaced by later writes, which are
+	// skipped so the stream does not go on without the field.
+	failed error
 }
 
 func NewWriter(w io.Writer) *Writer {
@@ -17,18 +23,32 @@ func NewWriter(w io.Writer) *Writer {
 func (writer *Writer) Reset(w io.Writer) {
 	writer.W = w
 	writer.err = nil
+	writer.failed = nil
 }
 
 func (writer *Writer) Error() error {
+	if writer.failed != nil {
+		return writer.failed
+	}
 	return writer.err
 }
 
 func (writer *Writer) Write(b []byte) (n int, err error) {
+	if writer.failed != nil {
+		return 0, writer.failed
+	}
 	n, err = writer.W.Write(b)
 	writer.err = err
 	return
 }
 
+// fail records a value that cannot be encoded.
+func (writer *Writer) fail(err error) {
+	if writer.failed == nil {
+		writer.failed = err
+	}
+}
+
 func (writer *Writer) WriteBytes(b []byte) {
 	writer.Write(b)
 }
@@ -37,6 +57,73 @@ func (writer *Writer) WriteString(s string) {
 	writer.WriteBytes([]byte(s))
 }
 
+func (writer *Writer) scratch(n int) []byte {
+	if n <= len(writer.wb) {
+		return writer.wb[:n]
+	}
+	return make([]byte, n)
+}
+
+func (writer *Writer) WriteBCD(nDigits int, v uint64) {
+	b := writer.scratch(BCDSize(nDigits))
+	if err := PutBCD(b, nDigits, v); err != nil {
+		writer.fail(err)
+		return
+	}
+	writer.Write(b)
+}
+
+func (writer *Writer) WritePackedDecimal(nDigits int, v int64) {
+	b := writer.scratch(PackedDecimalSize(nDigits))
+	if err := PutPackedDecimal(b, nDigits, v); err != nil {
+		writer.fail(err)
+		return
+	}
+	writer.Write(b)
+}
+
+func (writer *Writer) WriteIPv4(ip net.IP) {
+	ip4 := ip.To4()
+	if ip4 == nil {
+		writer.fail(ErrInvalidAddr)
+		return
+	}
+	writer.Write(ip4)
+}
+
+func (writer *Writer) WriteIPv6(ip net.IP) {
+	ip16 := ip.To16()
+	if ip16 == nil {
+		writer.fail(ErrInvalidAddr)
+		return
+	}
+	writer.Write(ip16)
+}
+
+func (writer *Writer) WriteMAC(mac net.HardwareAddr) {
+	if len(mac) != MACLen {
+		writer.fail(ErrInvalidAddr)
+		return
+	}
+	writer.Write(mac)
+}
+
+func (writer *Writer) WriteUUID(u UUID) {
+	writer.Write(u[:])
+}
+
+func (writer *Writer) WriteGUID(u UUID) {
+	b := writer.wb[:UUIDLen]
+	PutGUID(b, u)
+	writer.Write(b)
+}
+
+func (writer *Writer) WriteBools(v []bool, order BitOrder) {
+	b := writer.scratch(BoolsSize(len(v)))
+	PutBools(b, v, order)
+	writer.Write(b)
+}
+
 func (writer *Writer) WriteUvarint(v uint64) {
 	writer.Write(writer.wb[:PutUvarint(writer.wb[:], v)])
 }
@@ -45,6 +132,34 @@ func (writer *Writer) WriteVarint(v int64) {
 	writer.Write(writer.wb[:PutVarint(writer.wb[:], v)])
 }
 
+func (writer *Writer) WriteQuicVarint(v uint64) {
+	if v > MaxQuicVarint {
+		writer.fail(ErrQuicVarintTooLarge)
+		return
+	}
+	writer.Write(writer.wb[:PutQuicVarint(writer.wb[:], v)])
+}
+
+func (writer *Writer) WriteSQLiteVarint(v uint64) {
+	writer.Write(writer.wb[:PutSQLiteVarint(writer.wb[:], v)])
+}
+
+func (writer *Writer) WriteVLQ(v uint64) {
+	writer.Write(writer.wb[:PutVLQ(writer.wb[:], v)])
+}
+
+func (writer *Writer) WriteGitVarint(v uint64) {
+	writer.Write(writer.wb[:PutGitVarint(writer.wb[:], v)])
+}
+
+func (writer *Writer) WriteUvarint128(v Uint128) {
+	writer.Write(writer.wb[:PutUvarint128(writer.wb[:], v)])
+}
+
+func (writer *Writer) WriteVarint128(v Int128) {
+	writer.Write(writer.wb[:PutVarint128(writer.wb[:], v)])
+}
+
 func (writer *Writer) WriteUint8(v uint8) {
 	writer.wb[0] = v
 	writer.Write(writer.wb[:1])
@@ -141,6 +256,86 @@ func (writer *Writer) WriteFloat64LE(v float64) {
 	writer.Write(writer.wb[:8])
 }
 
+func (writer *Writer) WriteComplex64BE(v complex64) {
+	PutComplex64BE(writer.wb[:8], v)
+	writer.Write(writer.wb[:8])
+}
+
+func (writer *Writer) WriteComplex64LE(v complex64) {
+	PutComplex64LE(writer.wb[:8], v)
+	writer.Write(writer.wb[:8])
+}
+
+func (writer *Writer) WriteComplex128BE(v complex128) {
+	PutComplex128BE(writer.wb[:16], v)
+	writer.Write(writer.wb[:16])
+}
+
+func (writer *Writer) WriteComplex128LE(v complex128) {
+	PutComplex128LE(writer.wb[:16], v)
+	writer.Write(writer.wb[:16])
+}
+
+func (writer *Writer) WriteFloat80BE(v float64) {
+	PutFloat80BE(writer.wb[:10], v)
+	writer.Write(writer.wb[:10])
+}
+
+func (writer *Writer) WriteFloat80LE(v float64) {
+	PutFloat80LE(writer.wb[:10], v)
+	writer.Write(writer.wb[:10])
+}
+
+func (writer *Writer) WriteIBMFloat32BE(v float64) {
+	PutIBMFloat32BE(writer.wb[:4], v)
+	writer.Write(writer.wb[:4])
+}
+
+func (writer *Writer) WriteIBMFloat64BE(v float64) {
+	PutIBMFloat64BE(writer.wb[:8], v)
+	writer.Write(writer.wb[:8])
+}
+
+func (writer *Writer) WriteUint128BE(v Uint128) {
+	PutUint128BE(writer.wb[:16], v)
+	writer.Write(writer.wb[:16])
+}
+
+func (writer *Writer) WriteUint128LE(v Uint128) {
+	PutUint128LE(writer.wb[:16], v)
+	writer.Write(writer.wb[:16])
+}
+
+func (writer *Writer) WriteInt128BE(v Int128) {
+	PutInt128BE(writer.wb[:16], v)
+	writer.Write(writer.wb[:16])
+}
+
+func (writer *Writer) WriteInt128LE(v Int128) {
+	PutInt128LE(writer.wb[:16], v)
+	writer.Write(writer.wb[:16])
+}
+
+func (writer *Writer) WriteFloat16BE(v float32) {
+	PutFloat16BE(writer.wb[:2], v)
+	writer.Write(writer.wb[:2])
+}
+
+func (writer *Writer) WriteFloat16LE(v float32) {
+	PutFloat16LE(writer.wb[:2], v)
+	writer.Write(writer.wb[:2])
+}
+
+func (writer *Writer) WriteBFloat16BE(v float32) {
+	PutBFloat16BE(writer.wb[:2], v)
+	writer.Write(writer.wb[:2])
+}
+
+func (writer *Writer) WriteBFloat16LE(v float32) {
+	PutBFloat16LE(writer.wb[:2], v)
+	writer.Write(writer.wb[:2])
+}
+
 func (writer *Writer) WriteInt8(v int8)     { writer.WriteUint8(uint8(v)) }
 func (writer *Writer) WriteInt16BE(v int16) { writer.WriteUint16BE(uint16(v)) }
 func (writer *Writer) WriteInt16LE(v int16) { writer.WriteUint16LE(uint16(v)) }